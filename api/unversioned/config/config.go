@@ -35,6 +35,12 @@ ignoredStatuses:
 type Manager struct {
 	mtx sync.Mutex
 	cfg *unversioned.EraserConfig
+
+	// file is the configuration from the configuration file, which is used
+	// unless an EraserConfig resource overrides it
+	file       unversioned.EraserConfig
+	overridden bool
+	restart    func()
 }
 
 func (m *Manager) Read() (unversioned.EraserConfig, error) {
@@ -49,27 +55,114 @@ func (m *Manager) Read() (unversioned.EraserConfig, error) {
 	return cfg, nil
 }
 
+// Update sets the configuration from the configuration file. It is only
+// used while the configuration is not overridden.
 func (m *Manager) Update(newC *unversioned.EraserConfig) error {
+	if newC == nil {
+		return fmt.Errorf("new configuration is nil, aborting")
+	}
+
 	m.mtx.Lock()
-	defer m.mtx.Unlock()
+	m.file = *newC
+	overridden := m.overridden
+	m.mtx.Unlock()
 
-	if m.cfg == nil {
-		return fmt.Errorf("ConfigManager configuration is nil, aborting")
+	if overridden {
+		return nil
 	}
 
+	return m.set(newC)
+}
+
+// Override sets the configuration from an EraserConfig resource, which is
+// used instead of the configuration file until ClearOverride is called.
+func (m *Manager) Override(newC *unversioned.EraserConfig) error {
 	if newC == nil {
 		return fmt.Errorf("new configuration is nil, aborting")
 	}
 
+	m.mtx.Lock()
+	m.overridden = true
+	m.mtx.Unlock()
+
+	return m.set(newC)
+}
+
+// ClearOverride goes back to the configuration from the configuration file.
+func (m *Manager) ClearOverride() error {
+	m.mtx.Lock()
+	if !m.overridden {
+		m.mtx.Unlock()
+		return nil
+	}
+	m.overridden = false
+	file := m.file
+	m.mtx.Unlock()
+
+	return m.set(&file)
+}
+
+// Overridden returns whether the configuration is from an EraserConfig
+// resource rather than the configuration file.
+func (m *Manager) Overridden() bool {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	return m.overridden
+}
+
+// OnRestart sets the function which restarts the manager, which is called
+// when the configuration changes in a way that the running controllers
+// cannot pick up.
+func (m *Manager) OnRestart(restart func()) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.restart = restart
+}
+
+func (m *Manager) set(newC *unversioned.EraserConfig) error {
+	m.mtx.Lock()
+	if m.cfg == nil {
+		m.mtx.Unlock()
+		return fmt.Errorf("ConfigManager configuration is nil, aborting")
+	}
+
+	restart := needsRestart(m.cfg, newC)
 	*m.cfg = *newC
+	restartFunc := m.restart
+	m.mtx.Unlock()
+
+	if restart && restartFunc != nil {
+		restartFunc()
+	}
+
 	return nil
 }
 
+// needsRestart returns whether the controllers which the manager runs differ
+// between the configurations.
+func needsRestart(oldConfig, newConfig *unversioned.EraserConfig) bool {
+	type check struct {
+		collector bool
+		scanner   bool
+	}
+
+	oldComponents := check{collector: oldConfig.Components.Collector.Enabled, scanner: oldConfig.Components.Scanner.Enabled}
+	newComponents := check{collector: newConfig.Components.Collector.Enabled, scanner: newConfig.Components.Scanner.Enabled}
+	return oldComponents != newComponents
+}
+
 func NewManager(cfg *unversioned.EraserConfig) *Manager {
-	return &Manager{
+	m := &Manager{
 		mtx: sync.Mutex{},
 		cfg: cfg,
 	}
+	if cfg != nil {
+		m.file = *cfg
+	}
+
+	return m
 }
 
 const (
@@ -85,7 +178,17 @@ func Default() *unversioned.EraserConfig {
 				Address: "unix:///run/containerd/containerd.sock",
 			},
 			OTLPEndpoint: "",
-			LogLevel:     "info",
+			OTLP: unversioned.OTLPConfig{
+				Protocol:       "http/protobuf",
+				Insecure:       true,
+				Compression:    "none",
+				ExportInterval: unversioned.Duration(time.Minute),
+			},
+			Tracing: unversioned.TracingConfig{
+				Enabled:     false,
+				SampleRatio: 1.0,
+			},
+			LogLevel: "info",
 			Scheduling: unversioned.ScheduleConfig{
 				RepeatInterval:   unversioned.Duration(oneDay),
 				BeginImmediately: true,
@@ -109,6 +212,10 @@ func Default() *unversioned.EraserConfig {
 				},
 			},
 			AdditionalPodLabels: map[string]string{},
+			Metrics: unversioned.MetricsConfig{
+				RepositoryLabels: false,
+				MaxRepositories:  10,
+			},
 		},
 		Components: unversioned.Components{
 			Collector: unversioned.OptionalContainerConfig{
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+)
+
+func TestManagerOverride(t *testing.T) {
+	file := Default()
+	m := NewManager(file)
+
+	restarts := 0
+	m.OnRestart(func() { restarts++ })
+
+	resource := Default()
+	resource.Manager.Scheduling.RepeatInterval = unversioned.Duration(time.Hour)
+	resource.Components.Collector.Enabled = !file.Components.Collector.Enabled
+	if err := m.Override(resource); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := read(t, m).Manager.Scheduling.RepeatInterval; got != resource.Manager.Scheduling.RepeatInterval {
+		t.Errorf("expected the overridden repeatInterval %s, got %s", time.Duration(resource.Manager.Scheduling.RepeatInterval), time.Duration(got))
+	}
+	if restarts != 1 {
+		t.Errorf("expected enabling the collector to restart the manager once, got %d restarts", restarts)
+	}
+
+	// the configuration file is only used once the override is cleared
+	updated := Default()
+	updated.Manager.Scheduling.RepeatInterval = unversioned.Duration(2 * time.Hour)
+	if err := m.Update(updated); err != nil {
+		t.Fatal(err)
+	}
+	if got := read(t, m).Manager.Scheduling.RepeatInterval; got != resource.Manager.Scheduling.RepeatInterval {
+		t.Errorf("expected the overridden repeatInterval %s to be kept, got %s", time.Duration(resource.Manager.Scheduling.RepeatInterval), time.Duration(got))
+	}
+
+	if err := m.ClearOverride(); err != nil {
+		t.Fatal(err)
+	}
+	if got := read(t, m).Manager.Scheduling.RepeatInterval; got != updated.Manager.Scheduling.RepeatInterval {
+		t.Errorf("expected the repeatInterval %s from the file, got %s", time.Duration(updated.Manager.Scheduling.RepeatInterval), time.Duration(got))
+	}
+	if m.Overridden() {
+		t.Error("expected the override to be cleared")
+	}
+	if restarts != 2 {
+		t.Errorf("expected disabling the collector to restart the manager again, got %d restarts", restarts)
+	}
+}
+
+func read(t *testing.T, m *Manager) unversioned.EraserConfig {
+	t.Helper()
+
+	cfg, err := m.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cfg
+}
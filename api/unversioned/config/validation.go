@@ -0,0 +1,220 @@
+package config
+
+import (
+	"time"
+
+	"github.com/docker/distribution/reference"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+	"github.com/eraser-dev/eraser/pkg/schedule"
+)
+
+// Validate checks the parts of an EraserConfig which would otherwise only
+// fail, or be ignored, when the manager or a job uses them.
+func Validate(cfg *unversioned.EraserConfig) field.ErrorList {
+	var errs field.ErrorList
+	errs = append(errs, validateManager(&cfg.Manager, field.NewPath("manager"))...)
+	errs = append(errs, validateComponents(&cfg.Components, field.NewPath("components"))...)
+	return errs
+}
+
+func validateManager(mgr *unversioned.ManagerConfig, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	switch mgr.OTLP.Protocol {
+	case "", "grpc", "http/protobuf":
+	default:
+		errs = append(errs, field.NotSupported(path.Child("otlp", "protocol"), mgr.OTLP.Protocol, []string{"grpc", "http/protobuf"}))
+	}
+
+	switch mgr.OTLP.Compression {
+	case "", "gzip", "none":
+	default:
+		errs = append(errs, field.NotSupported(path.Child("otlp", "compression"), mgr.OTLP.Compression, []string{"gzip", "none"}))
+	}
+
+	errs = append(errs, validateNonNegative(mgr.OTLP.ExportInterval, path.Child("otlp", "exportInterval"))...)
+	errs = append(errs, validateRatio(mgr.Tracing.SampleRatio, path.Child("tracing", "sampleRatio"))...)
+
+	errs = append(errs, validateScheduling(&mgr.Scheduling, path.Child("scheduling"))...)
+
+	if mgr.Profile.Enabled {
+		for _, msg := range validation.IsValidPortNum(mgr.Profile.Port) {
+			errs = append(errs, field.Invalid(path.Child("profile", "port"), mgr.Profile.Port, msg))
+		}
+	}
+
+	errs = append(errs, validateRatio(mgr.ImageJob.SuccessRatio, path.Child("imageJob", "successRatio"))...)
+	errs = append(errs, validateNonNegative(mgr.ImageJob.Cleanup.DelayOnSuccess, path.Child("imageJob", "cleanup", "delayOnSuccess"))...)
+	errs = append(errs, validateNonNegative(mgr.ImageJob.Cleanup.DelayOnFailure, path.Child("imageJob", "cleanup", "delayOnFailure"))...)
+	errs = append(errs, validateRollout(&mgr.ImageJob.Rollout, path.Child("imageJob", "rollout"))...)
+
+	for i, image := range mgr.ImageJob.AllowedImages {
+		if _, err := reference.ParseNormalizedNamed(image); err != nil {
+			errs = append(errs, field.Invalid(path.Child("imageJob", "allowedImages").Index(i), image, err.Error()))
+		}
+	}
+
+	for i, secret := range mgr.PullSecrets {
+		for _, msg := range validation.IsDNS1123Subdomain(secret) {
+			errs = append(errs, field.Invalid(path.Child("pullSecrets").Index(i), secret, msg))
+		}
+	}
+
+	errs = append(errs, ValidateNodeFilter(&mgr.NodeFilter, path.Child("nodeFilter"))...)
+
+	for k, v := range mgr.AdditionalPodLabels {
+		labelPath := path.Child("additionalPodLabels").Key(k)
+		for _, msg := range validation.IsQualifiedName(k) {
+			errs = append(errs, field.Invalid(labelPath, k, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(v) {
+			errs = append(errs, field.Invalid(labelPath, v, msg))
+		}
+	}
+
+	if mgr.Metrics.MaxRepositories < 0 {
+		errs = append(errs, field.Invalid(path.Child("metrics", "maxRepositories"), mgr.Metrics.MaxRepositories, "must not be negative"))
+	}
+
+	return errs
+}
+
+func validateScheduling(cfg *unversioned.ScheduleConfig, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	loc, err := time.LoadLocation(cfg.TimeZone)
+	if err != nil {
+		return field.ErrorList{field.Invalid(path.Child("timeZone"), cfg.TimeZone, err.Error())}
+	}
+
+	if cfg.Cron != "" {
+		if _, err := schedule.ParseCron(cfg.Cron, loc); err != nil {
+			errs = append(errs, field.Invalid(path.Child("cron"), cfg.Cron, err.Error()))
+		}
+	} else if cfg.RepeatInterval <= 0 {
+		errs = append(errs, field.Invalid(path.Child("repeatInterval"), time.Duration(cfg.RepeatInterval).String(), "must be positive"))
+	}
+
+	errs = append(errs, validateWindows(cfg.AllowedWindows, loc, path.Child("allowedWindows"))...)
+	errs = append(errs, validateWindows(cfg.BlockedWindows, loc, path.Child("blockedWindows"))...)
+	if len(errs) > 0 {
+		return errs
+	}
+
+	// the windows could leave no time at all to run in
+	sched, err := schedule.New(cfg)
+	if err == nil {
+		_, err = sched.First(time.Now(), true)
+	}
+	if err != nil {
+		errs = append(errs, field.Forbidden(path, err.Error()))
+	}
+
+	return errs
+}
+
+func validateWindows(windows []unversioned.MaintenanceWindow, loc *time.Location, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	for i, w := range windows {
+		if _, err := schedule.ParseCron(w.Cron, loc); err != nil {
+			errs = append(errs, field.Invalid(path.Index(i).Child("cron"), w.Cron, err.Error()))
+		}
+		if w.Duration <= 0 {
+			errs = append(errs, field.Invalid(path.Index(i).Child("duration"), time.Duration(w.Duration).String(), "must be positive"))
+		}
+	}
+
+	return errs
+}
+
+func validateRollout(rollout *unversioned.RolloutConfig, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	if size, err := intstr.GetScaledValueFromIntOrPercent(&rollout.BatchSize, 100, true); err != nil {
+		errs = append(errs, field.Invalid(path.Child("batchSize"), rollout.BatchSize.String(), err.Error()))
+	} else if size < 0 {
+		errs = append(errs, field.Invalid(path.Child("batchSize"), rollout.BatchSize.String(), "must not be negative"))
+	}
+
+	errs = append(errs, validateNonNegative(rollout.PauseBetweenBatches, path.Child("pauseBetweenBatches"))...)
+
+	if rollout.NodePoolLabel != "" {
+		for _, msg := range validation.IsQualifiedName(rollout.NodePoolLabel) {
+			errs = append(errs, field.Invalid(path.Child("nodePoolLabel"), rollout.NodePoolLabel, msg))
+		}
+	}
+
+	if rollout.NodePoolConcurrency < 0 {
+		errs = append(errs, field.Invalid(path.Child("nodePoolConcurrency"), rollout.NodePoolConcurrency, "must not be negative"))
+	} else if rollout.NodePoolConcurrency > 0 && rollout.NodePoolLabel == "" {
+		errs = append(errs, field.Required(path.Child("nodePoolLabel"), "needed to limit nodePoolConcurrency"))
+	}
+
+	errs = append(errs, validateRatio(rollout.StopOnFailureRatio, path.Child("stopOnFailureRatio"))...)
+
+	return errs
+}
+
+// ValidateNodeFilter checks the type of a node filter, and that each of its
+// selectors is a valid label selector.
+func ValidateNodeFilter(filter *unversioned.NodeFilterConfig, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	switch filter.Type {
+	case "include", "exclude":
+	default:
+		errs = append(errs, field.NotSupported(path.Child("type"), filter.Type, []string{"include", "exclude"}))
+	}
+
+	for i, selector := range filter.Selectors {
+		if _, err := labels.Parse(selector); err != nil {
+			errs = append(errs, field.Invalid(path.Child("selectors").Index(i), selector, err.Error()))
+		}
+	}
+
+	return errs
+}
+
+func validateComponents(components *unversioned.Components, path *field.Path) field.ErrorList {
+	var errs field.ErrorList
+
+	errs = append(errs, validateImage(&components.Remover.Image, path.Child("remover", "image"))...)
+	if components.Collector.Enabled {
+		errs = append(errs, validateImage(&components.Collector.Image, path.Child("collector", "image"))...)
+	}
+	if components.Scanner.Enabled {
+		errs = append(errs, validateImage(&components.Scanner.Image, path.Child("scanner", "image"))...)
+	}
+
+	return errs
+}
+
+func validateImage(image *unversioned.RepoTag, path *field.Path) field.ErrorList {
+	if image.Repo == "" {
+		return field.ErrorList{field.Required(path.Child("repo"), "")}
+	}
+
+	return nil
+}
+
+func validateRatio(ratio float64, path *field.Path) field.ErrorList {
+	if ratio < 0 || ratio > 1 {
+		return field.ErrorList{field.Invalid(path, ratio, "must be between 0 and 1")}
+	}
+
+	return nil
+}
+
+func validateNonNegative(d unversioned.Duration, path *field.Path) field.ErrorList {
+	if d < 0 {
+		return field.ErrorList{field.Invalid(path, time.Duration(d).String(), "must not be negative")}
+	}
+
+	return nil
+}
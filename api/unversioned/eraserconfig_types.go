@@ -25,6 +25,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 type (
@@ -154,12 +155,20 @@ type ContainerConfig struct {
 	Request ResourceRequirements `json:"request,omitempty"`
 	Limit   ResourceRequirements `json:"limit,omitempty"`
 	Config  *string              `json:"config,omitempty"`
-	Volumes []corev1.Volume      `json:"volumes,omitempty"`
+	// Volumes are mounted read-only in the scanner. hostPath volumes are
+	// mounted at their path; persistentVolumeClaim and secret volumes are
+	// mounted at /mnt/eraser/<name>.
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
+	// WritableVolumes are mounted like Volumes, but read-write. They can be
+	// used to persist data such as a scan cache between runs.
+	WritableVolumes []corev1.Volume `json:"writableVolumes,omitempty"`
 }
 
 type ManagerConfig struct {
 	Runtime             RuntimeSpec       `json:"runtime,omitempty"`
 	OTLPEndpoint        string            `json:"otlpEndpoint,omitempty"`
+	OTLP                OTLPConfig        `json:"otlp,omitempty"`
+	Tracing             TracingConfig     `json:"tracing,omitempty"`
 	LogLevel            string            `json:"logLevel,omitempty"`
 	Scheduling          ScheduleConfig    `json:"scheduling,omitempty"`
 	Profile             ProfileConfig     `json:"profile,omitempty"`
@@ -168,11 +177,65 @@ type ManagerConfig struct {
 	NodeFilter          NodeFilterConfig  `json:"nodeFilter,omitempty"`
 	PriorityClassName   string            `json:"priorityClassName,omitempty"`
 	AdditionalPodLabels map[string]string `json:"additionalPodLabels,omitempty"`
+	Metrics             MetricsConfig     `json:"metrics,omitempty"`
+}
+
+type MetricsConfig struct {
+	// RepositoryLabels adds the repository of each image to the removal
+	// metrics.
+	RepositoryLabels bool `json:"repositoryLabels,omitempty"`
+	// MaxRepositories limits the number of distinct repository labels.
+	// Further repositories are counted as "other".
+	MaxRepositories int `json:"maxRepositories,omitempty"`
+}
+
+type OTLPConfig struct {
+	// Protocol is either "http/protobuf" or "grpc".
+	Protocol string `json:"protocol,omitempty"`
+	// Insecure disables TLS.
+	Insecure bool `json:"insecure,omitempty"`
+	// TLSSecretName is a Secret in Eraser's namespace which holds the CA to
+	// verify the collector with in ca.crt, and optionally a client
+	// certificate in tls.crt and tls.key.
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+	// HeadersSecretName is a Secret in Eraser's namespace which holds the
+	// headers sent with every export, for example to authenticate, in its
+	// headers key in the format of OTEL_EXPORTER_OTLP_HEADERS.
+	HeadersSecretName string `json:"headersSecretName,omitempty"`
+	// Compression is either "gzip" or "none".
+	Compression    string   `json:"compression,omitempty"`
+	ExportInterval Duration `json:"exportInterval,omitempty"`
+}
+
+type TracingConfig struct {
+	// Enabled exports traces of each ImageJob to the OTLP endpoint.
+	Enabled bool `json:"enabled,omitempty"`
+	// SampleRatio is the fraction of ImageJobs which are traced.
+	SampleRatio float64 `json:"sampleRatio,omitempty"`
 }
 
 type ScheduleConfig struct {
 	RepeatInterval   Duration `json:"repeatInterval,omitempty"`
 	BeginImmediately bool     `json:"beginImmediately,omitempty"`
+	// Cron is a standard five-field cron expression, such as "0 2 * * *",
+	// for when the collector runs. It takes the place of RepeatInterval.
+	Cron string `json:"cron,omitempty"`
+	// TimeZone is the IANA time zone, such as "America/New_York", in which
+	// Cron and the maintenance windows are evaluated. Defaults to UTC.
+	TimeZone string `json:"timeZone,omitempty"`
+	// AllowedWindows are the only times in which runs start, if any are
+	// set. Runs which fall outside them are moved to the next window.
+	AllowedWindows []MaintenanceWindow `json:"allowedWindows,omitempty"`
+	// BlockedWindows are times in which runs never start. Runs which fall
+	// inside them are moved to after the window.
+	BlockedWindows []MaintenanceWindow `json:"blockedWindows,omitempty"`
+}
+
+// MaintenanceWindow is a recurring period of time, which begins at each
+// time matched by Cron and lasts for Duration.
+type MaintenanceWindow struct {
+	Cron     string   `json:"cron"`
+	Duration Duration `json:"duration"`
 }
 
 type ProfileConfig struct {
@@ -183,6 +246,36 @@ type ProfileConfig struct {
 type ImageJobConfig struct {
 	SuccessRatio float64               `json:"successRatio,omitempty"`
 	Cleanup      ImageJobCleanupConfig `json:"cleanup,omitempty"`
+	Rollout      RolloutConfig         `json:"rollout,omitempty"`
+	// AllowedImages are the images which an ImageJob's removerImage,
+	// collectorImage and scannerImage may be set to. An ImageJob which
+	// overrides a component image with any other image is rejected.
+	AllowedImages []string `json:"allowedImages,omitempty"`
+}
+
+// RolloutConfig staggers the pods of each ImageJob across the cluster, so
+// that image removal and scans don't load every node at the same time. By
+// default, pods start on every node at once.
+type RolloutConfig struct {
+	// BatchSize is the number of nodes, such as 5, or the percentage of the
+	// job's nodes, such as "20%", which run in each batch. A batch starts
+	// once the pods of the previous one have finished. 0 runs every node in
+	// one batch.
+	BatchSize intstr.IntOrString `json:"batchSize,omitempty"`
+	// PauseBetweenBatches is how long to wait after a batch has finished
+	// before starting the next.
+	PauseBetweenBatches Duration `json:"pauseBetweenBatches,omitempty"`
+	// NodePoolLabel is the node label, such as
+	// cloud.google.com/gke-nodepool, whose values group nodes into pools.
+	NodePoolLabel string `json:"nodePoolLabel,omitempty"`
+	// NodePoolConcurrency is the number of nodes of each pool which run at
+	// the same time. 0 is unlimited.
+	NodePoolConcurrency int `json:"nodePoolConcurrency,omitempty"`
+	// StopOnFailureRatio stops the rollout once this fraction of the
+	// finished pods have failed. It is checked between batches, or while
+	// pods run once at least 10 have finished. The nodes which haven't
+	// started yet are left out of the job. 0 never stops.
+	StopOnFailureRatio float64 `json:"stopOnFailureRatio,omitempty"`
 }
 
 type ImageJobCleanupConfig struct {
@@ -213,13 +306,54 @@ type Components struct {
 
 //+kubebuilder:object:root=true
 
-// EraserConfig is the Schema for the eraserconfigs API.
+// EraserConfig is the Schema for the eraserconfigs API. The manager reads
+// it from its configuration file, and from the EraserConfig resource named
+// eraser-config, which takes precedence while it exists.
 type EraserConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Manager    ManagerConfig `json:"manager"`
+	Components Components    `json:"components"`
+
+	Status EraserConfigStatus `json:"status,omitempty"`
+}
+
+// EraserConfigStatus is the state of an EraserConfig resource, as last
+// loaded by the manager.
+type EraserConfigStatus struct {
+	// ObservedGeneration is the generation which was last loaded, whether
+	// or not it could be.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastLoadTime is when the manager last loaded the EraserConfig.
+	LastLoadTime *metav1.Time `json:"lastLoadTime,omitempty"`
+	// LastLoadError is why the observed generation could not be loaded. It
+	// is empty if it was loaded, and otherwise the manager keeps using the
+	// configuration it used before.
+	LastLoadError string `json:"lastLoadError,omitempty"`
+	// Effective is the configuration the manager is using, with defaults
+	// filled in.
+	Effective *EffectiveConfig `json:"effective,omitempty"`
+	// NextScheduledRun is when the collector will next run, if it is
+	// enabled.
+	NextScheduledRun *metav1.Time `json:"nextScheduledRun,omitempty"`
+}
+
+// EffectiveConfig is the configuration the manager is using.
+type EffectiveConfig struct {
+	Manager    ManagerConfig `json:"manager"`
+	Components Components    `json:"components"`
+}
+
+//+kubebuilder:object:root=true
+
+// EraserConfigList contains a list of EraserConfig.
+type EraserConfigList struct {
 	metav1.TypeMeta `json:",inline"`
-	Manager         ManagerConfig `json:"manager"`
-	Components      Components    `json:"components"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EraserConfig `json:"items"`
 }
 
 func init() {
-	SchemeBuilder.Register(&EraserConfig{})
+	SchemeBuilder.Register(&EraserConfig{}, &EraserConfigList{})
 }
@@ -18,6 +18,7 @@ limitations under the License.
 package unversioned
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -25,6 +26,8 @@ type Image struct {
 	ImageID string   `json:"image_id"`
 	Names   []string `json:"names,omitempty"`
 	Digests []string `json:"digests,omitempty"`
+	Size    int64    `json:"size,omitempty"`
+	Pinned  bool     `json:"pinned,omitempty"`
 }
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -39,6 +42,106 @@ const (
 	PhaseFailed    JobPhase = "Failed"
 )
 
+// The types of the Conditions of ImageJobs and ImageLists.
+const (
+	// ConditionScheduled is true once the job's nodes are selected, and
+	// false while it is queued behind another job.
+	ConditionScheduled = "Scheduled"
+	// ConditionPodsCreated is true once a pod was started on every node.
+	ConditionPodsCreated = "PodsCreated"
+	// ConditionProgressing is true while the job's pods are running.
+	ConditionProgressing = "Progressing"
+	// ConditionSucceeded is true if the job completed, and false if it failed.
+	ConditionSucceeded = "Succeeded"
+	// ConditionDegraded is true if any of the job's pods failed.
+	ConditionDegraded = "Degraded"
+	// ConditionSuccessRatioNotMet is true if too few of the job's pods
+	// succeeded.
+	ConditionSuccessRatioNotMet = "SuccessRatioNotMet"
+)
+
+// JobMode defines what an ImageJob does on each node.
+type JobMode string
+
+const (
+	// ModeRemove removes the images of the spec.
+	ModeRemove JobMode = "Remove"
+	// ModeCollect removes the images which are not running or, if the
+	// scanner is enabled, those which are vulnerable.
+	ModeCollect JobMode = "Collect"
+)
+
+// NodeFilter selects nodes by label.
+type NodeFilter struct {
+	// include runs on the nodes which match any of the selectors, and
+	// exclude on the others.
+	Type string `json:"type"`
+	// label selectors, such as eraser.sh/cleanup.filter or kubernetes.io/os=windows
+	Selectors []string `json:"selectors,omitempty"`
+}
+
+// ImageJobSpec defines the desired state of ImageJob.
+type ImageJobSpec struct {
+	// Remove to remove the images of the spec, or Collect to collect the
+	// images of each node and remove the unused ones.
+	Mode JobMode `json:"mode"`
+
+	// images to remove in Remove mode, by name, digest or ID. "*" removes
+	// every image which is not running.
+	Images []string `json:"images,omitempty"`
+
+	// the nodes to run on. Defaults to the manager's node filter.
+	NodeFilter *NodeFilter `json:"nodeFilter,omitempty"`
+
+	// image of the remover. Defaults to the image of the manager's configuration.
+	RemoverImage string `json:"removerImage,omitempty"`
+	// image of the collector. Defaults to the image of the manager's configuration.
+	CollectorImage string `json:"collectorImage,omitempty"`
+	// image of the scanner. Defaults to the image of the manager's configuration.
+	ScannerImage string `json:"scannerImage,omitempty"`
+
+	// whether to scan the images in Collect mode
+	ScannerEnabled bool `json:"scannerEnabled,omitempty"`
+
+	// maximum number of nodes to run on at the same time. 0 runs on every
+	// node at once.
+	Concurrency int `json:"concurrency,omitempty"`
+}
+
+// NodeStatus is the state of an ImageJob's pod on one node.
+type NodeStatus struct {
+	// name of the node
+	Name string `json:"name"`
+
+	// name of the job's pod on the node
+	PodName string `json:"podName"`
+
+	// phase of the pod
+	Phase corev1.PodPhase `json:"phase,omitempty"`
+
+	// time the pod was started by the kubelet
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// time the last of the pod's containers finished
+	FinishTime *metav1.Time `json:"finishTime,omitempty"`
+
+	// reason the pod failed or is waiting, such as OOMKilled or ImagePullBackOff
+	Reason string `json:"reason,omitempty"`
+
+	// termination message of the container which failed, or why the pod is
+	// waiting, cut off after 256 bytes
+	Message string `json:"message,omitempty"`
+
+	// number of images removed from the node
+	ImagesRemoved int64 `json:"imagesRemoved,omitempty"`
+
+	// number of images kept because they were excluded or running
+	ImagesProtected int64 `json:"imagesProtected,omitempty"`
+
+	// number of images which could not be removed
+	ImagesFailed int64 `json:"imagesFailed,omitempty"`
+}
+
 // ImageJobStatus defines the observed state of ImageJob.
 type ImageJobStatus struct {
 	// number of pods that failed
@@ -58,6 +161,21 @@ type ImageJobStatus struct {
 
 	// Time to delay deletion until
 	DeleteAfter *metav1.Time `json:"deleteAfter,omitempty"`
+
+	// nodes which are waiting to run, if concurrency is limited or the
+	// rollout is staggered
+	PendingNodes []string `json:"pendingNodes,omitempty"`
+
+	// number of nodes which were left out after the rollout stopped, because
+	// too many pods failed
+	Stopped int `json:"stopped,omitempty"`
+
+	// state of the job's pod on each node, updated as the pods finish. At
+	// most 100 nodes are listed, leaving out those which succeeded first.
+	Nodes []NodeStatus `json:"nodes,omitempty"`
+
+	// latest observations of the job's state
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // ImageJob is the Schema for the imagejobs API.
@@ -65,6 +183,7 @@ type ImageJob struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
+	Spec   ImageJobSpec   `json:"spec,omitempty"`
 	Status ImageJobStatus `json:"status,omitempty"`
 }
 
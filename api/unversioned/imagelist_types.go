@@ -34,6 +34,8 @@ type ImageListStatus struct {
 	Failed int64 `json:"failed"`
 	// Number of nodes that were skipped due to a skip selector
 	Skipped int64 `json:"skipped"`
+	// Latest observations of the state of the list's ImageJob
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // ImageList is the Schema for the imagelists API.
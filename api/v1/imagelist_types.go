@@ -17,6 +17,14 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// PruneAll is the image which selects every non-running image.
+	PruneAll = "*"
+	// ConfirmPruneAnnotation must be "true" on an ImageList whose images
+	// include PruneAll.
+	ConfirmPruneAnnotation = "eraser.sh/confirm-prune"
+)
+
 // ImageListSpec defines the desired state of ImageList.
 type ImageListSpec struct {
 	// The list of non-compliant images to delete if non-running.
@@ -33,6 +41,11 @@ type ImageListStatus struct {
 	Failed int64 `json:"failed"`
 	// Number of nodes that were skipped due to a skip selector
 	Skipped int64 `json:"skipped"`
+	// Latest observations of the state of the list's ImageJob
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
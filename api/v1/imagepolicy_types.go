@@ -0,0 +1,59 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ImagePolicyRule is a named CEL expression which decides whether an image should be removed.
+type ImagePolicyRule struct {
+	// Name of the rule, used in logs when the rule matches.
+	Name string `json:"name"`
+	// CEL expression evaluating to a bool. Available variables are image, vulns, eol, age, node and scanned.
+	Expression string `json:"expression"`
+}
+
+// ImagePolicySpec defines the desired state of ImagePolicy.
+type ImagePolicySpec struct {
+	// Rules are evaluated in order; an image is removed if any rule evaluates to true.
+	Rules []ImagePolicyRule `json:"rules"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope="Cluster"
+// +kubebuilder:storageversion
+// ImagePolicy is the Schema for the imagepolicies API.
+type ImagePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ImagePolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// ImagePolicyList contains a list of ImagePolicy.
+type ImagePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ImagePolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ImagePolicy{}, &ImagePolicyList{})
+}
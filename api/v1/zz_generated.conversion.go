@@ -24,6 +24,7 @@ import (
 	unsafe "unsafe"
 
 	unversioned "github.com/eraser-dev/eraser/api/unversioned"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
@@ -66,6 +67,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ImageJobSpec)(nil), (*unversioned.ImageJobSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_ImageJobSpec_To_unversioned_ImageJobSpec(a.(*ImageJobSpec), b.(*unversioned.ImageJobSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*unversioned.ImageJobSpec)(nil), (*ImageJobSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_unversioned_ImageJobSpec_To_v1_ImageJobSpec(a.(*unversioned.ImageJobSpec), b.(*ImageJobSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ImageJobStatus)(nil), (*unversioned.ImageJobStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1_ImageJobStatus_To_unversioned_ImageJobStatus(a.(*ImageJobStatus), b.(*unversioned.ImageJobStatus), scope)
 	}); err != nil {
@@ -116,6 +127,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NodeFilter)(nil), (*unversioned.NodeFilter)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_NodeFilter_To_unversioned_NodeFilter(a.(*NodeFilter), b.(*unversioned.NodeFilter), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*unversioned.NodeFilter)(nil), (*NodeFilter)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_unversioned_NodeFilter_To_v1_NodeFilter(a.(*unversioned.NodeFilter), b.(*NodeFilter), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*NodeStatus)(nil), (*unversioned.NodeStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1_NodeStatus_To_unversioned_NodeStatus(a.(*NodeStatus), b.(*unversioned.NodeStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*unversioned.NodeStatus)(nil), (*NodeStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_unversioned_NodeStatus_To_v1_NodeStatus(a.(*unversioned.NodeStatus), b.(*NodeStatus), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -123,6 +154,8 @@ func autoConvert_v1_Image_To_unversioned_Image(in *Image, out *unversioned.Image
 	out.ImageID = in.ImageID
 	out.Names = *(*[]string)(unsafe.Pointer(&in.Names))
 	out.Digests = *(*[]string)(unsafe.Pointer(&in.Digests))
+	out.Size = in.Size
+	out.Pinned = in.Pinned
 	return nil
 }
 
@@ -135,6 +168,8 @@ func autoConvert_unversioned_Image_To_v1_Image(in *unversioned.Image, out *Image
 	out.ImageID = in.ImageID
 	out.Names = *(*[]string)(unsafe.Pointer(&in.Names))
 	out.Digests = *(*[]string)(unsafe.Pointer(&in.Digests))
+	out.Size = in.Size
+	out.Pinned = in.Pinned
 	return nil
 }
 
@@ -145,6 +180,9 @@ func Convert_unversioned_Image_To_v1_Image(in *unversioned.Image, out *Image, s
 
 func autoConvert_v1_ImageJob_To_unversioned_ImageJob(in *ImageJob, out *unversioned.ImageJob, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1_ImageJobSpec_To_unversioned_ImageJobSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
 	if err := Convert_v1_ImageJobStatus_To_unversioned_ImageJobStatus(&in.Status, &out.Status, s); err != nil {
 		return err
 	}
@@ -158,6 +196,9 @@ func Convert_v1_ImageJob_To_unversioned_ImageJob(in *ImageJob, out *unversioned.
 
 func autoConvert_unversioned_ImageJob_To_v1_ImageJob(in *unversioned.ImageJob, out *ImageJob, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_unversioned_ImageJobSpec_To_v1_ImageJobSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
 	if err := Convert_unversioned_ImageJobStatus_To_v1_ImageJobStatus(&in.Status, &out.Status, s); err != nil {
 		return err
 	}
@@ -191,6 +232,40 @@ func Convert_unversioned_ImageJobList_To_v1_ImageJobList(in *unversioned.ImageJo
 	return autoConvert_unversioned_ImageJobList_To_v1_ImageJobList(in, out, s)
 }
 
+func autoConvert_v1_ImageJobSpec_To_unversioned_ImageJobSpec(in *ImageJobSpec, out *unversioned.ImageJobSpec, s conversion.Scope) error {
+	out.Mode = unversioned.JobMode(in.Mode)
+	out.Images = *(*[]string)(unsafe.Pointer(&in.Images))
+	out.NodeFilter = (*unversioned.NodeFilter)(unsafe.Pointer(in.NodeFilter))
+	out.RemoverImage = in.RemoverImage
+	out.CollectorImage = in.CollectorImage
+	out.ScannerImage = in.ScannerImage
+	out.ScannerEnabled = in.ScannerEnabled
+	out.Concurrency = in.Concurrency
+	return nil
+}
+
+// Convert_v1_ImageJobSpec_To_unversioned_ImageJobSpec is an autogenerated conversion function.
+func Convert_v1_ImageJobSpec_To_unversioned_ImageJobSpec(in *ImageJobSpec, out *unversioned.ImageJobSpec, s conversion.Scope) error {
+	return autoConvert_v1_ImageJobSpec_To_unversioned_ImageJobSpec(in, out, s)
+}
+
+func autoConvert_unversioned_ImageJobSpec_To_v1_ImageJobSpec(in *unversioned.ImageJobSpec, out *ImageJobSpec, s conversion.Scope) error {
+	out.Mode = JobMode(in.Mode)
+	out.Images = *(*[]string)(unsafe.Pointer(&in.Images))
+	out.NodeFilter = (*NodeFilter)(unsafe.Pointer(in.NodeFilter))
+	out.RemoverImage = in.RemoverImage
+	out.CollectorImage = in.CollectorImage
+	out.ScannerImage = in.ScannerImage
+	out.ScannerEnabled = in.ScannerEnabled
+	out.Concurrency = in.Concurrency
+	return nil
+}
+
+// Convert_unversioned_ImageJobSpec_To_v1_ImageJobSpec is an autogenerated conversion function.
+func Convert_unversioned_ImageJobSpec_To_v1_ImageJobSpec(in *unversioned.ImageJobSpec, out *ImageJobSpec, s conversion.Scope) error {
+	return autoConvert_unversioned_ImageJobSpec_To_v1_ImageJobSpec(in, out, s)
+}
+
 func autoConvert_v1_ImageJobStatus_To_unversioned_ImageJobStatus(in *ImageJobStatus, out *unversioned.ImageJobStatus, s conversion.Scope) error {
 	out.Failed = in.Failed
 	out.Succeeded = in.Succeeded
@@ -198,6 +273,10 @@ func autoConvert_v1_ImageJobStatus_To_unversioned_ImageJobStatus(in *ImageJobSta
 	out.Skipped = in.Skipped
 	out.Phase = unversioned.JobPhase(in.Phase)
 	out.DeleteAfter = (*metav1.Time)(unsafe.Pointer(in.DeleteAfter))
+	out.PendingNodes = *(*[]string)(unsafe.Pointer(&in.PendingNodes))
+	out.Stopped = in.Stopped
+	out.Nodes = *(*[]unversioned.NodeStatus)(unsafe.Pointer(&in.Nodes))
+	out.Conditions = *(*[]metav1.Condition)(unsafe.Pointer(&in.Conditions))
 	return nil
 }
 
@@ -213,6 +292,10 @@ func autoConvert_unversioned_ImageJobStatus_To_v1_ImageJobStatus(in *unversioned
 	out.Skipped = in.Skipped
 	out.Phase = JobPhase(in.Phase)
 	out.DeleteAfter = (*metav1.Time)(unsafe.Pointer(in.DeleteAfter))
+	out.PendingNodes = *(*[]string)(unsafe.Pointer(&in.PendingNodes))
+	out.Stopped = in.Stopped
+	out.Nodes = *(*[]NodeStatus)(unsafe.Pointer(&in.Nodes))
+	out.Conditions = *(*[]metav1.Condition)(unsafe.Pointer(&in.Conditions))
 	return nil
 }
 
@@ -300,6 +383,7 @@ func autoConvert_v1_ImageListStatus_To_unversioned_ImageListStatus(in *ImageList
 	out.Success = in.Success
 	out.Failed = in.Failed
 	out.Skipped = in.Skipped
+	out.Conditions = *(*[]metav1.Condition)(unsafe.Pointer(&in.Conditions))
 	return nil
 }
 
@@ -313,6 +397,7 @@ func autoConvert_unversioned_ImageListStatus_To_v1_ImageListStatus(in *unversion
 	out.Success = in.Success
 	out.Failed = in.Failed
 	out.Skipped = in.Skipped
+	out.Conditions = *(*[]metav1.Condition)(unsafe.Pointer(&in.Conditions))
 	return nil
 }
 
@@ -320,3 +405,63 @@ func autoConvert_unversioned_ImageListStatus_To_v1_ImageListStatus(in *unversion
 func Convert_unversioned_ImageListStatus_To_v1_ImageListStatus(in *unversioned.ImageListStatus, out *ImageListStatus, s conversion.Scope) error {
 	return autoConvert_unversioned_ImageListStatus_To_v1_ImageListStatus(in, out, s)
 }
+
+func autoConvert_v1_NodeFilter_To_unversioned_NodeFilter(in *NodeFilter, out *unversioned.NodeFilter, s conversion.Scope) error {
+	out.Type = in.Type
+	out.Selectors = *(*[]string)(unsafe.Pointer(&in.Selectors))
+	return nil
+}
+
+// Convert_v1_NodeFilter_To_unversioned_NodeFilter is an autogenerated conversion function.
+func Convert_v1_NodeFilter_To_unversioned_NodeFilter(in *NodeFilter, out *unversioned.NodeFilter, s conversion.Scope) error {
+	return autoConvert_v1_NodeFilter_To_unversioned_NodeFilter(in, out, s)
+}
+
+func autoConvert_unversioned_NodeFilter_To_v1_NodeFilter(in *unversioned.NodeFilter, out *NodeFilter, s conversion.Scope) error {
+	out.Type = in.Type
+	out.Selectors = *(*[]string)(unsafe.Pointer(&in.Selectors))
+	return nil
+}
+
+// Convert_unversioned_NodeFilter_To_v1_NodeFilter is an autogenerated conversion function.
+func Convert_unversioned_NodeFilter_To_v1_NodeFilter(in *unversioned.NodeFilter, out *NodeFilter, s conversion.Scope) error {
+	return autoConvert_unversioned_NodeFilter_To_v1_NodeFilter(in, out, s)
+}
+
+func autoConvert_v1_NodeStatus_To_unversioned_NodeStatus(in *NodeStatus, out *unversioned.NodeStatus, s conversion.Scope) error {
+	out.Name = in.Name
+	out.PodName = in.PodName
+	out.Phase = corev1.PodPhase(in.Phase)
+	out.StartTime = (*metav1.Time)(unsafe.Pointer(in.StartTime))
+	out.FinishTime = (*metav1.Time)(unsafe.Pointer(in.FinishTime))
+	out.Reason = in.Reason
+	out.Message = in.Message
+	out.ImagesRemoved = in.ImagesRemoved
+	out.ImagesProtected = in.ImagesProtected
+	out.ImagesFailed = in.ImagesFailed
+	return nil
+}
+
+// Convert_v1_NodeStatus_To_unversioned_NodeStatus is an autogenerated conversion function.
+func Convert_v1_NodeStatus_To_unversioned_NodeStatus(in *NodeStatus, out *unversioned.NodeStatus, s conversion.Scope) error {
+	return autoConvert_v1_NodeStatus_To_unversioned_NodeStatus(in, out, s)
+}
+
+func autoConvert_unversioned_NodeStatus_To_v1_NodeStatus(in *unversioned.NodeStatus, out *NodeStatus, s conversion.Scope) error {
+	out.Name = in.Name
+	out.PodName = in.PodName
+	out.Phase = corev1.PodPhase(in.Phase)
+	out.StartTime = (*metav1.Time)(unsafe.Pointer(in.StartTime))
+	out.FinishTime = (*metav1.Time)(unsafe.Pointer(in.FinishTime))
+	out.Reason = in.Reason
+	out.Message = in.Message
+	out.ImagesRemoved = in.ImagesRemoved
+	out.ImagesProtected = in.ImagesProtected
+	out.ImagesFailed = in.ImagesFailed
+	return nil
+}
+
+// Convert_unversioned_NodeStatus_To_v1_NodeStatus is an autogenerated conversion function.
+func Convert_unversioned_NodeStatus_To_v1_NodeStatus(in *unversioned.NodeStatus, out *NodeStatus, s conversion.Scope) error {
+	return autoConvert_unversioned_NodeStatus_To_v1_NodeStatus(in, out, s)
+}
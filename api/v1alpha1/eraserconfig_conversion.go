@@ -0,0 +1,65 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+	"github.com/eraser-dev/eraser/api/v1alpha3"
+)
+
+// ConvertTo converts this EraserConfig to the hub version, v1alpha3.
+func (c *EraserConfig) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*v1alpha3.EraserConfig)
+
+	// an unset runtime is defaulted when the configuration is loaded, so it
+	// is left unset rather than failing to convert
+	src := c
+	unsetRuntime := c.Manager.Runtime == ""
+	if unsetRuntime {
+		src = c.DeepCopy()
+		src.Manager.Runtime = RuntimeContainerd
+	}
+
+	var unv unversioned.EraserConfig
+	if err := Convert_v1alpha1_EraserConfig_To_unversioned_EraserConfig(src, &unv, nil); err != nil {
+		return err
+	}
+
+	if err := v1alpha3.Convert_unversioned_EraserConfig_To_v1alpha3_EraserConfig(&unv, dst, nil); err != nil {
+		return err
+	}
+
+	if unsetRuntime {
+		dst.Manager.Runtime = v1alpha3.RuntimeSpec{}
+	}
+
+	return nil
+}
+
+// ConvertFrom converts an EraserConfig from the hub version, v1alpha3.
+func (c *EraserConfig) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*v1alpha3.EraserConfig)
+
+	var unv unversioned.EraserConfig
+	if err := v1alpha3.Convert_v1alpha3_EraserConfig_To_unversioned_EraserConfig(src, &unv, nil); err != nil {
+		return err
+	}
+
+	return Convert_unversioned_EraserConfig_To_v1alpha1_EraserConfig(&unv, c, nil)
+}
@@ -0,0 +1,78 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/eraser-dev/eraser/api/v1alpha3"
+)
+
+func TestEraserConfigConversion(t *testing.T) {
+	tests := map[string]struct {
+		runtime     Runtime
+		wantRuntime v1alpha3.RuntimeSpec
+	}{
+		"Runtime": {
+			runtime:     RuntimeCrio,
+			wantRuntime: v1alpha3.RuntimeSpec{Name: v1alpha3.RuntimeCrio, Address: "unix://" + v1alpha3.CrioPath},
+		},
+		"UnsetRuntime": {
+			runtime:     "",
+			wantRuntime: v1alpha3.RuntimeSpec{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			src := &EraserConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "eraser-config", Generation: 2},
+				Manager: ManagerConfig{
+					Runtime:    test.runtime,
+					Scheduling: ScheduleConfig{RepeatInterval: Duration(time.Hour)},
+				},
+				Components: Components{
+					Eraser: ContainerConfig{Image: RepoTag{Repo: "remover", Tag: "v1"}},
+				},
+				Status: EraserConfigStatus{ObservedGeneration: 1, LastLoadError: "invalid"},
+			}
+
+			hub := &v1alpha3.EraserConfig{}
+			if err := src.ConvertTo(hub); err != nil {
+				t.Fatalf("Error: %v", err)
+			}
+
+			if hub.Name != src.Name || hub.Generation != src.Generation {
+				t.Errorf("Unexpected metadata. Expected %v, but got %v", src.ObjectMeta, hub.ObjectMeta)
+			}
+			if hub.Manager.Runtime != test.wantRuntime {
+				t.Errorf("Unexpected runtime. Expected %v, but got %v", test.wantRuntime, hub.Manager.Runtime)
+			}
+			if hub.Manager.Scheduling.RepeatInterval != v1alpha3.Duration(time.Hour) {
+				t.Errorf("Unexpected repeatInterval. Expected %v, but got %v", time.Hour, time.Duration(hub.Manager.Scheduling.RepeatInterval))
+			}
+			if hub.Components.Remover.Image.Repo != "remover" {
+				t.Errorf("Expected the eraser component to be converted to the remover, but got %v", hub.Components.Remover)
+			}
+			if hub.Status.ObservedGeneration != 1 || hub.Status.LastLoadError != "invalid" {
+				t.Errorf("Unexpected status. Expected %v, but got %v", src.Status, hub.Status)
+			}
+
+			dst := &EraserConfig{}
+			if err := dst.ConvertFrom(hub); err != nil {
+				t.Fatalf("Error: %v", err)
+			}
+
+			if dst.Manager.Runtime != test.runtime {
+				t.Errorf("Unexpected runtime. Expected %q, but got %q", test.runtime, dst.Manager.Runtime)
+			}
+			if dst.Components.Eraser.Image != src.Components.Eraser.Image {
+				t.Errorf("Unexpected eraser image. Expected %v, but got %v", src.Components.Eraser.Image, dst.Components.Eraser.Image)
+			}
+			if dst.Status.ObservedGeneration != 1 || dst.Status.LastLoadError != "invalid" {
+				t.Errorf("Unexpected status. Expected %v, but got %v", src.Status, dst.Status)
+			}
+		})
+	}
+}
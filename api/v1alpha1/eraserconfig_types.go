@@ -29,6 +29,7 @@ import (
 )
 
 type (
+	// +kubebuilder:validation:Type=string
 	Duration time.Duration
 	Runtime  string
 )
@@ -89,7 +90,11 @@ type ContainerConfig struct {
 	Request ResourceRequirements `json:"request,omitempty"`
 	Limit   ResourceRequirements `json:"limit,omitempty"`
 	Config  *string              `json:"config,omitempty"`
-	Volumes []corev1.Volume      `json:"volumes,omitempty"`
+	// Volumes are mounted read-only in the scanner. They are validated when
+	// its pods are created, rather than by the schema of the CRD.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Volumes []corev1.Volume `json:"volumes,omitempty"`
 }
 
 type ManagerConfig struct {
@@ -146,16 +151,62 @@ type Components struct {
 }
 
 //+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope="Cluster"
 
-// EraserConfig is the Schema for the eraserconfigs API.
+// EraserConfig is the Schema for the eraserconfigs API. The manager reads
+// it from its configuration file, and from the EraserConfig resource named
+// eraser-config, which takes precedence while it exists.
 type EraserConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +optional
+	Manager ManagerConfig `json:"manager"`
+	// +optional
+	Components Components `json:"components"`
+
+	// +optional
+	Status EraserConfigStatus `json:"status,omitempty"`
+}
+
+// EraserConfigStatus is the state of an EraserConfig resource, as last
+// loaded by the manager.
+type EraserConfigStatus struct {
+	// ObservedGeneration is the generation which was last loaded, whether
+	// or not it could be.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// LastLoadTime is when the manager last loaded the EraserConfig.
+	LastLoadTime *metav1.Time `json:"lastLoadTime,omitempty"`
+	// LastLoadError is why the observed generation could not be loaded. It
+	// is empty if it was loaded, and otherwise the manager keeps using the
+	// configuration it used before.
+	LastLoadError string `json:"lastLoadError,omitempty"`
+	// Effective is the configuration the manager is using, with defaults
+	// filled in.
+	Effective *EffectiveConfig `json:"effective,omitempty"`
+	// NextScheduledRun is when the collector will next run, if it is
+	// enabled.
+	NextScheduledRun *metav1.Time `json:"nextScheduledRun,omitempty"`
+}
+
+// EffectiveConfig is the configuration the manager is using.
+type EffectiveConfig struct {
+	Manager    ManagerConfig `json:"manager"`
+	Components Components    `json:"components"`
+}
+
+//+kubebuilder:object:root=true
+
+// EraserConfigList contains a list of EraserConfig.
+type EraserConfigList struct {
 	metav1.TypeMeta `json:",inline"`
-	Manager         ManagerConfig `json:"manager"`
-	Components      Components    `json:"components"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []EraserConfig `json:"items"`
 }
 
 func init() {
-	SchemeBuilder.Register(&EraserConfig{})
+	SchemeBuilder.Register(&EraserConfig{}, &EraserConfigList{})
 }
 
 // In future versions of EraserConfig (for example, v1alpha2), the
@@ -42,8 +42,13 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*unversioned.ContainerConfig)(nil), (*ContainerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_unversioned_ContainerConfig_To_v1alpha1_ContainerConfig(a.(*unversioned.ContainerConfig), b.(*ContainerConfig), scope)
+	if err := s.AddGeneratedConversionFunc((*EffectiveConfig)(nil), (*unversioned.EffectiveConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_EffectiveConfig_To_unversioned_EffectiveConfig(a.(*EffectiveConfig), b.(*unversioned.EffectiveConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*unversioned.EffectiveConfig)(nil), (*EffectiveConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_unversioned_EffectiveConfig_To_v1alpha1_EffectiveConfig(a.(*unversioned.EffectiveConfig), b.(*EffectiveConfig), scope)
 	}); err != nil {
 		return err
 	}
@@ -57,6 +62,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*EraserConfigList)(nil), (*unversioned.EraserConfigList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_EraserConfigList_To_unversioned_EraserConfigList(a.(*EraserConfigList), b.(*unversioned.EraserConfigList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*unversioned.EraserConfigList)(nil), (*EraserConfigList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_unversioned_EraserConfigList_To_v1alpha1_EraserConfigList(a.(*unversioned.EraserConfigList), b.(*EraserConfigList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*EraserConfigStatus)(nil), (*unversioned.EraserConfigStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_EraserConfigStatus_To_unversioned_EraserConfigStatus(a.(*EraserConfigStatus), b.(*unversioned.EraserConfigStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*unversioned.EraserConfigStatus)(nil), (*EraserConfigStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_unversioned_EraserConfigStatus_To_v1alpha1_EraserConfigStatus(a.(*unversioned.EraserConfigStatus), b.(*EraserConfigStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*Image)(nil), (*unversioned.Image)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_Image_To_unversioned_Image(a.(*Image), b.(*unversioned.Image), scope)
 	}); err != nil {
@@ -92,11 +117,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*unversioned.ImageJobConfig)(nil), (*ImageJobConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_unversioned_ImageJobConfig_To_v1alpha1_ImageJobConfig(a.(*unversioned.ImageJobConfig), b.(*ImageJobConfig), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*ImageJobList)(nil), (*unversioned.ImageJobList)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_ImageJobList_To_unversioned_ImageJobList(a.(*ImageJobList), b.(*unversioned.ImageJobList), scope)
 	}); err != nil {
@@ -107,6 +127,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ImageJobSpec)(nil), (*unversioned.ImageJobSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ImageJobSpec_To_unversioned_ImageJobSpec(a.(*ImageJobSpec), b.(*unversioned.ImageJobSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*unversioned.ImageJobSpec)(nil), (*ImageJobSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_unversioned_ImageJobSpec_To_v1alpha1_ImageJobSpec(a.(*unversioned.ImageJobSpec), b.(*ImageJobSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ImageJobStatus)(nil), (*unversioned.ImageJobStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_ImageJobStatus_To_unversioned_ImageJobStatus(a.(*ImageJobStatus), b.(*unversioned.ImageJobStatus), scope)
 	}); err != nil {
@@ -157,6 +187,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NodeFilter)(nil), (*unversioned.NodeFilter)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_NodeFilter_To_unversioned_NodeFilter(a.(*NodeFilter), b.(*unversioned.NodeFilter), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*unversioned.NodeFilter)(nil), (*NodeFilter)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_unversioned_NodeFilter_To_v1alpha1_NodeFilter(a.(*unversioned.NodeFilter), b.(*NodeFilter), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NodeFilterConfig)(nil), (*unversioned.NodeFilterConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_NodeFilterConfig_To_unversioned_NodeFilterConfig(a.(*NodeFilterConfig), b.(*unversioned.NodeFilterConfig), scope)
 	}); err != nil {
@@ -167,6 +207,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NodeStatus)(nil), (*unversioned.NodeStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_NodeStatus_To_unversioned_NodeStatus(a.(*NodeStatus), b.(*unversioned.NodeStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*unversioned.NodeStatus)(nil), (*NodeStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_unversioned_NodeStatus_To_v1alpha1_NodeStatus(a.(*unversioned.NodeStatus), b.(*NodeStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*OptionalContainerConfig)(nil), (*unversioned.OptionalContainerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_OptionalContainerConfig_To_unversioned_OptionalContainerConfig(a.(*OptionalContainerConfig), b.(*unversioned.OptionalContainerConfig), scope)
 	}); err != nil {
@@ -212,13 +262,18 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*unversioned.ScheduleConfig)(nil), (*ScheduleConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_unversioned_ScheduleConfig_To_v1alpha1_ScheduleConfig(a.(*unversioned.ScheduleConfig), b.(*ScheduleConfig), scope)
+	if err := s.AddConversionFunc((*unversioned.Components)(nil), (*Components)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_unversioned_Components_To_v1alpha1_Components(a.(*unversioned.Components), b.(*Components), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddConversionFunc((*unversioned.Components)(nil), (*Components)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_unversioned_Components_To_v1alpha1_Components(a.(*unversioned.Components), b.(*Components), scope)
+	if err := s.AddConversionFunc((*unversioned.ContainerConfig)(nil), (*ContainerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_unversioned_ContainerConfig_To_v1alpha1_ContainerConfig(a.(*unversioned.ContainerConfig), b.(*ContainerConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*unversioned.ImageJobConfig)(nil), (*ImageJobConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_unversioned_ImageJobConfig_To_v1alpha1_ImageJobConfig(a.(*unversioned.ImageJobConfig), b.(*ImageJobConfig), scope)
 	}); err != nil {
 		return err
 	}
@@ -232,6 +287,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*unversioned.ScheduleConfig)(nil), (*ScheduleConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_unversioned_ScheduleConfig_To_v1alpha1_ScheduleConfig(a.(*unversioned.ScheduleConfig), b.(*ScheduleConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*Components)(nil), (*unversioned.Components)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_Components_To_unversioned_Components(a.(*Components), b.(*unversioned.Components), scope)
 	}); err != nil {
@@ -304,21 +364,51 @@ func autoConvert_unversioned_ContainerConfig_To_v1alpha1_ContainerConfig(in *unv
 	}
 	out.Config = (*string)(unsafe.Pointer(in.Config))
 	out.Volumes = *(*[]v1.Volume)(unsafe.Pointer(&in.Volumes))
+	// WARNING: in.WritableVolumes requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_unversioned_ContainerConfig_To_v1alpha1_ContainerConfig is an autogenerated conversion function.
-func Convert_unversioned_ContainerConfig_To_v1alpha1_ContainerConfig(in *unversioned.ContainerConfig, out *ContainerConfig, s conversion.Scope) error {
-	return autoConvert_unversioned_ContainerConfig_To_v1alpha1_ContainerConfig(in, out, s)
+func autoConvert_v1alpha1_EffectiveConfig_To_unversioned_EffectiveConfig(in *EffectiveConfig, out *unversioned.EffectiveConfig, s conversion.Scope) error {
+	if err := Convert_v1alpha1_ManagerConfig_To_unversioned_ManagerConfig(&in.Manager, &out.Manager, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha1_Components_To_unversioned_Components(&in.Components, &out.Components, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha1_EffectiveConfig_To_unversioned_EffectiveConfig is an autogenerated conversion function.
+func Convert_v1alpha1_EffectiveConfig_To_unversioned_EffectiveConfig(in *EffectiveConfig, out *unversioned.EffectiveConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha1_EffectiveConfig_To_unversioned_EffectiveConfig(in, out, s)
+}
+
+func autoConvert_unversioned_EffectiveConfig_To_v1alpha1_EffectiveConfig(in *unversioned.EffectiveConfig, out *EffectiveConfig, s conversion.Scope) error {
+	if err := Convert_unversioned_ManagerConfig_To_v1alpha1_ManagerConfig(&in.Manager, &out.Manager, s); err != nil {
+		return err
+	}
+	if err := Convert_unversioned_Components_To_v1alpha1_Components(&in.Components, &out.Components, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_unversioned_EffectiveConfig_To_v1alpha1_EffectiveConfig is an autogenerated conversion function.
+func Convert_unversioned_EffectiveConfig_To_v1alpha1_EffectiveConfig(in *unversioned.EffectiveConfig, out *EffectiveConfig, s conversion.Scope) error {
+	return autoConvert_unversioned_EffectiveConfig_To_v1alpha1_EffectiveConfig(in, out, s)
 }
 
 func autoConvert_v1alpha1_EraserConfig_To_unversioned_EraserConfig(in *EraserConfig, out *unversioned.EraserConfig, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha1_ManagerConfig_To_unversioned_ManagerConfig(&in.Manager, &out.Manager, s); err != nil {
 		return err
 	}
 	if err := Convert_v1alpha1_Components_To_unversioned_Components(&in.Components, &out.Components, s); err != nil {
 		return err
 	}
+	if err := Convert_v1alpha1_EraserConfigStatus_To_unversioned_EraserConfigStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -328,12 +418,16 @@ func Convert_v1alpha1_EraserConfig_To_unversioned_EraserConfig(in *EraserConfig,
 }
 
 func autoConvert_unversioned_EraserConfig_To_v1alpha1_EraserConfig(in *unversioned.EraserConfig, out *EraserConfig, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_unversioned_ManagerConfig_To_v1alpha1_ManagerConfig(&in.Manager, &out.Manager, s); err != nil {
 		return err
 	}
 	if err := Convert_unversioned_Components_To_v1alpha1_Components(&in.Components, &out.Components, s); err != nil {
 		return err
 	}
+	if err := Convert_unversioned_EraserConfigStatus_To_v1alpha1_EraserConfigStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -342,10 +436,98 @@ func Convert_unversioned_EraserConfig_To_v1alpha1_EraserConfig(in *unversioned.E
 	return autoConvert_unversioned_EraserConfig_To_v1alpha1_EraserConfig(in, out, s)
 }
 
+func autoConvert_v1alpha1_EraserConfigList_To_unversioned_EraserConfigList(in *EraserConfigList, out *unversioned.EraserConfigList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]unversioned.EraserConfig, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha1_EraserConfig_To_unversioned_EraserConfig(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha1_EraserConfigList_To_unversioned_EraserConfigList is an autogenerated conversion function.
+func Convert_v1alpha1_EraserConfigList_To_unversioned_EraserConfigList(in *EraserConfigList, out *unversioned.EraserConfigList, s conversion.Scope) error {
+	return autoConvert_v1alpha1_EraserConfigList_To_unversioned_EraserConfigList(in, out, s)
+}
+
+func autoConvert_unversioned_EraserConfigList_To_v1alpha1_EraserConfigList(in *unversioned.EraserConfigList, out *EraserConfigList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]EraserConfig, len(*in))
+		for i := range *in {
+			if err := Convert_unversioned_EraserConfig_To_v1alpha1_EraserConfig(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
+	return nil
+}
+
+// Convert_unversioned_EraserConfigList_To_v1alpha1_EraserConfigList is an autogenerated conversion function.
+func Convert_unversioned_EraserConfigList_To_v1alpha1_EraserConfigList(in *unversioned.EraserConfigList, out *EraserConfigList, s conversion.Scope) error {
+	return autoConvert_unversioned_EraserConfigList_To_v1alpha1_EraserConfigList(in, out, s)
+}
+
+func autoConvert_v1alpha1_EraserConfigStatus_To_unversioned_EraserConfigStatus(in *EraserConfigStatus, out *unversioned.EraserConfigStatus, s conversion.Scope) error {
+	out.ObservedGeneration = in.ObservedGeneration
+	out.LastLoadTime = (*metav1.Time)(unsafe.Pointer(in.LastLoadTime))
+	out.LastLoadError = in.LastLoadError
+	if in.Effective != nil {
+		in, out := &in.Effective, &out.Effective
+		*out = new(unversioned.EffectiveConfig)
+		if err := Convert_v1alpha1_EffectiveConfig_To_unversioned_EffectiveConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Effective = nil
+	}
+	out.NextScheduledRun = (*metav1.Time)(unsafe.Pointer(in.NextScheduledRun))
+	return nil
+}
+
+// Convert_v1alpha1_EraserConfigStatus_To_unversioned_EraserConfigStatus is an autogenerated conversion function.
+func Convert_v1alpha1_EraserConfigStatus_To_unversioned_EraserConfigStatus(in *EraserConfigStatus, out *unversioned.EraserConfigStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_EraserConfigStatus_To_unversioned_EraserConfigStatus(in, out, s)
+}
+
+func autoConvert_unversioned_EraserConfigStatus_To_v1alpha1_EraserConfigStatus(in *unversioned.EraserConfigStatus, out *EraserConfigStatus, s conversion.Scope) error {
+	out.ObservedGeneration = in.ObservedGeneration
+	out.LastLoadTime = (*metav1.Time)(unsafe.Pointer(in.LastLoadTime))
+	out.LastLoadError = in.LastLoadError
+	if in.Effective != nil {
+		in, out := &in.Effective, &out.Effective
+		*out = new(EffectiveConfig)
+		if err := Convert_unversioned_EffectiveConfig_To_v1alpha1_EffectiveConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Effective = nil
+	}
+	out.NextScheduledRun = (*metav1.Time)(unsafe.Pointer(in.NextScheduledRun))
+	return nil
+}
+
+// Convert_unversioned_EraserConfigStatus_To_v1alpha1_EraserConfigStatus is an autogenerated conversion function.
+func Convert_unversioned_EraserConfigStatus_To_v1alpha1_EraserConfigStatus(in *unversioned.EraserConfigStatus, out *EraserConfigStatus, s conversion.Scope) error {
+	return autoConvert_unversioned_EraserConfigStatus_To_v1alpha1_EraserConfigStatus(in, out, s)
+}
+
 func autoConvert_v1alpha1_Image_To_unversioned_Image(in *Image, out *unversioned.Image, s conversion.Scope) error {
 	out.ImageID = in.ImageID
 	out.Names = *(*[]string)(unsafe.Pointer(&in.Names))
 	out.Digests = *(*[]string)(unsafe.Pointer(&in.Digests))
+	out.Size = in.Size
+	out.Pinned = in.Pinned
 	return nil
 }
 
@@ -358,6 +540,8 @@ func autoConvert_unversioned_Image_To_v1alpha1_Image(in *unversioned.Image, out
 	out.ImageID = in.ImageID
 	out.Names = *(*[]string)(unsafe.Pointer(&in.Names))
 	out.Digests = *(*[]string)(unsafe.Pointer(&in.Digests))
+	out.Size = in.Size
+	out.Pinned = in.Pinned
 	return nil
 }
 
@@ -368,6 +552,9 @@ func Convert_unversioned_Image_To_v1alpha1_Image(in *unversioned.Image, out *Ima
 
 func autoConvert_v1alpha1_ImageJob_To_unversioned_ImageJob(in *ImageJob, out *unversioned.ImageJob, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_v1alpha1_ImageJobSpec_To_unversioned_ImageJobSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
 	if err := Convert_v1alpha1_ImageJobStatus_To_unversioned_ImageJobStatus(&in.Status, &out.Status, s); err != nil {
 		return err
 	}
@@ -381,6 +568,9 @@ func Convert_v1alpha1_ImageJob_To_unversioned_ImageJob(in *ImageJob, out *unvers
 
 func autoConvert_unversioned_ImageJob_To_v1alpha1_ImageJob(in *unversioned.ImageJob, out *ImageJob, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
+	if err := Convert_unversioned_ImageJobSpec_To_v1alpha1_ImageJobSpec(&in.Spec, &out.Spec, s); err != nil {
+		return err
+	}
 	if err := Convert_unversioned_ImageJobStatus_To_v1alpha1_ImageJobStatus(&in.Status, &out.Status, s); err != nil {
 		return err
 	}
@@ -432,14 +622,11 @@ func autoConvert_unversioned_ImageJobConfig_To_v1alpha1_ImageJobConfig(in *unver
 	if err := Convert_unversioned_ImageJobCleanupConfig_To_v1alpha1_ImageJobCleanupConfig(&in.Cleanup, &out.Cleanup, s); err != nil {
 		return err
 	}
+	// WARNING: in.Rollout requires manual conversion: does not exist in peer-type
+	// WARNING: in.AllowedImages requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_unversioned_ImageJobConfig_To_v1alpha1_ImageJobConfig is an autogenerated conversion function.
-func Convert_unversioned_ImageJobConfig_To_v1alpha1_ImageJobConfig(in *unversioned.ImageJobConfig, out *ImageJobConfig, s conversion.Scope) error {
-	return autoConvert_unversioned_ImageJobConfig_To_v1alpha1_ImageJobConfig(in, out, s)
-}
-
 func autoConvert_v1alpha1_ImageJobList_To_unversioned_ImageJobList(in *ImageJobList, out *unversioned.ImageJobList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
 	out.Items = *(*[]unversioned.ImageJob)(unsafe.Pointer(&in.Items))
@@ -462,6 +649,40 @@ func Convert_unversioned_ImageJobList_To_v1alpha1_ImageJobList(in *unversioned.I
 	return autoConvert_unversioned_ImageJobList_To_v1alpha1_ImageJobList(in, out, s)
 }
 
+func autoConvert_v1alpha1_ImageJobSpec_To_unversioned_ImageJobSpec(in *ImageJobSpec, out *unversioned.ImageJobSpec, s conversion.Scope) error {
+	out.Mode = unversioned.JobMode(in.Mode)
+	out.Images = *(*[]string)(unsafe.Pointer(&in.Images))
+	out.NodeFilter = (*unversioned.NodeFilter)(unsafe.Pointer(in.NodeFilter))
+	out.RemoverImage = in.RemoverImage
+	out.CollectorImage = in.CollectorImage
+	out.ScannerImage = in.ScannerImage
+	out.ScannerEnabled = in.ScannerEnabled
+	out.Concurrency = in.Concurrency
+	return nil
+}
+
+// Convert_v1alpha1_ImageJobSpec_To_unversioned_ImageJobSpec is an autogenerated conversion function.
+func Convert_v1alpha1_ImageJobSpec_To_unversioned_ImageJobSpec(in *ImageJobSpec, out *unversioned.ImageJobSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ImageJobSpec_To_unversioned_ImageJobSpec(in, out, s)
+}
+
+func autoConvert_unversioned_ImageJobSpec_To_v1alpha1_ImageJobSpec(in *unversioned.ImageJobSpec, out *ImageJobSpec, s conversion.Scope) error {
+	out.Mode = JobMode(in.Mode)
+	out.Images = *(*[]string)(unsafe.Pointer(&in.Images))
+	out.NodeFilter = (*NodeFilter)(unsafe.Pointer(in.NodeFilter))
+	out.RemoverImage = in.RemoverImage
+	out.CollectorImage = in.CollectorImage
+	out.ScannerImage = in.ScannerImage
+	out.ScannerEnabled = in.ScannerEnabled
+	out.Concurrency = in.Concurrency
+	return nil
+}
+
+// Convert_unversioned_ImageJobSpec_To_v1alpha1_ImageJobSpec is an autogenerated conversion function.
+func Convert_unversioned_ImageJobSpec_To_v1alpha1_ImageJobSpec(in *unversioned.ImageJobSpec, out *ImageJobSpec, s conversion.Scope) error {
+	return autoConvert_unversioned_ImageJobSpec_To_v1alpha1_ImageJobSpec(in, out, s)
+}
+
 func autoConvert_v1alpha1_ImageJobStatus_To_unversioned_ImageJobStatus(in *ImageJobStatus, out *unversioned.ImageJobStatus, s conversion.Scope) error {
 	out.Failed = in.Failed
 	out.Succeeded = in.Succeeded
@@ -469,6 +690,10 @@ func autoConvert_v1alpha1_ImageJobStatus_To_unversioned_ImageJobStatus(in *Image
 	out.Skipped = in.Skipped
 	out.Phase = unversioned.JobPhase(in.Phase)
 	out.DeleteAfter = (*metav1.Time)(unsafe.Pointer(in.DeleteAfter))
+	out.PendingNodes = *(*[]string)(unsafe.Pointer(&in.PendingNodes))
+	out.Stopped = in.Stopped
+	out.Nodes = *(*[]unversioned.NodeStatus)(unsafe.Pointer(&in.Nodes))
+	out.Conditions = *(*[]metav1.Condition)(unsafe.Pointer(&in.Conditions))
 	return nil
 }
 
@@ -484,6 +709,10 @@ func autoConvert_unversioned_ImageJobStatus_To_v1alpha1_ImageJobStatus(in *unver
 	out.Skipped = in.Skipped
 	out.Phase = JobPhase(in.Phase)
 	out.DeleteAfter = (*metav1.Time)(unsafe.Pointer(in.DeleteAfter))
+	out.PendingNodes = *(*[]string)(unsafe.Pointer(&in.PendingNodes))
+	out.Stopped = in.Stopped
+	out.Nodes = *(*[]NodeStatus)(unsafe.Pointer(&in.Nodes))
+	out.Conditions = *(*[]metav1.Condition)(unsafe.Pointer(&in.Conditions))
 	return nil
 }
 
@@ -571,6 +800,7 @@ func autoConvert_v1alpha1_ImageListStatus_To_unversioned_ImageListStatus(in *Ima
 	out.Success = in.Success
 	out.Failed = in.Failed
 	out.Skipped = in.Skipped
+	out.Conditions = *(*[]metav1.Condition)(unsafe.Pointer(&in.Conditions))
 	return nil
 }
 
@@ -584,6 +814,7 @@ func autoConvert_unversioned_ImageListStatus_To_v1alpha1_ImageListStatus(in *unv
 	out.Success = in.Success
 	out.Failed = in.Failed
 	out.Skipped = in.Skipped
+	out.Conditions = *(*[]metav1.Condition)(unsafe.Pointer(&in.Conditions))
 	return nil
 }
 
@@ -620,6 +851,8 @@ func autoConvert_unversioned_ManagerConfig_To_v1alpha1_ManagerConfig(in *unversi
 		return err
 	}
 	out.OTLPEndpoint = in.OTLPEndpoint
+	// WARNING: in.OTLP requires manual conversion: does not exist in peer-type
+	// WARNING: in.Tracing requires manual conversion: does not exist in peer-type
 	out.LogLevel = in.LogLevel
 	if err := Convert_unversioned_ScheduleConfig_To_v1alpha1_ScheduleConfig(&in.Scheduling, &out.Scheduling, s); err != nil {
 		return err
@@ -636,9 +869,32 @@ func autoConvert_unversioned_ManagerConfig_To_v1alpha1_ManagerConfig(in *unversi
 	}
 	out.PriorityClassName = in.PriorityClassName
 	// WARNING: in.AdditionalPodLabels requires manual conversion: does not exist in peer-type
+	// WARNING: in.Metrics requires manual conversion: does not exist in peer-type
 	return nil
 }
 
+func autoConvert_v1alpha1_NodeFilter_To_unversioned_NodeFilter(in *NodeFilter, out *unversioned.NodeFilter, s conversion.Scope) error {
+	out.Type = in.Type
+	out.Selectors = *(*[]string)(unsafe.Pointer(&in.Selectors))
+	return nil
+}
+
+// Convert_v1alpha1_NodeFilter_To_unversioned_NodeFilter is an autogenerated conversion function.
+func Convert_v1alpha1_NodeFilter_To_unversioned_NodeFilter(in *NodeFilter, out *unversioned.NodeFilter, s conversion.Scope) error {
+	return autoConvert_v1alpha1_NodeFilter_To_unversioned_NodeFilter(in, out, s)
+}
+
+func autoConvert_unversioned_NodeFilter_To_v1alpha1_NodeFilter(in *unversioned.NodeFilter, out *NodeFilter, s conversion.Scope) error {
+	out.Type = in.Type
+	out.Selectors = *(*[]string)(unsafe.Pointer(&in.Selectors))
+	return nil
+}
+
+// Convert_unversioned_NodeFilter_To_v1alpha1_NodeFilter is an autogenerated conversion function.
+func Convert_unversioned_NodeFilter_To_v1alpha1_NodeFilter(in *unversioned.NodeFilter, out *NodeFilter, s conversion.Scope) error {
+	return autoConvert_unversioned_NodeFilter_To_v1alpha1_NodeFilter(in, out, s)
+}
+
 func autoConvert_v1alpha1_NodeFilterConfig_To_unversioned_NodeFilterConfig(in *NodeFilterConfig, out *unversioned.NodeFilterConfig, s conversion.Scope) error {
 	out.Type = in.Type
 	out.Selectors = *(*[]string)(unsafe.Pointer(&in.Selectors))
@@ -661,6 +917,44 @@ func Convert_unversioned_NodeFilterConfig_To_v1alpha1_NodeFilterConfig(in *unver
 	return autoConvert_unversioned_NodeFilterConfig_To_v1alpha1_NodeFilterConfig(in, out, s)
 }
 
+func autoConvert_v1alpha1_NodeStatus_To_unversioned_NodeStatus(in *NodeStatus, out *unversioned.NodeStatus, s conversion.Scope) error {
+	out.Name = in.Name
+	out.PodName = in.PodName
+	out.Phase = v1.PodPhase(in.Phase)
+	out.StartTime = (*metav1.Time)(unsafe.Pointer(in.StartTime))
+	out.FinishTime = (*metav1.Time)(unsafe.Pointer(in.FinishTime))
+	out.Reason = in.Reason
+	out.Message = in.Message
+	out.ImagesRemoved = in.ImagesRemoved
+	out.ImagesProtected = in.ImagesProtected
+	out.ImagesFailed = in.ImagesFailed
+	return nil
+}
+
+// Convert_v1alpha1_NodeStatus_To_unversioned_NodeStatus is an autogenerated conversion function.
+func Convert_v1alpha1_NodeStatus_To_unversioned_NodeStatus(in *NodeStatus, out *unversioned.NodeStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha1_NodeStatus_To_unversioned_NodeStatus(in, out, s)
+}
+
+func autoConvert_unversioned_NodeStatus_To_v1alpha1_NodeStatus(in *unversioned.NodeStatus, out *NodeStatus, s conversion.Scope) error {
+	out.Name = in.Name
+	out.PodName = in.PodName
+	out.Phase = v1.PodPhase(in.Phase)
+	out.StartTime = (*metav1.Time)(unsafe.Pointer(in.StartTime))
+	out.FinishTime = (*metav1.Time)(unsafe.Pointer(in.FinishTime))
+	out.Reason = in.Reason
+	out.Message = in.Message
+	out.ImagesRemoved = in.ImagesRemoved
+	out.ImagesProtected = in.ImagesProtected
+	out.ImagesFailed = in.ImagesFailed
+	return nil
+}
+
+// Convert_unversioned_NodeStatus_To_v1alpha1_NodeStatus is an autogenerated conversion function.
+func Convert_unversioned_NodeStatus_To_v1alpha1_NodeStatus(in *unversioned.NodeStatus, out *NodeStatus, s conversion.Scope) error {
+	return autoConvert_unversioned_NodeStatus_To_v1alpha1_NodeStatus(in, out, s)
+}
+
 func autoConvert_v1alpha1_OptionalContainerConfig_To_unversioned_OptionalContainerConfig(in *OptionalContainerConfig, out *unversioned.OptionalContainerConfig, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	if err := Convert_v1alpha1_ContainerConfig_To_unversioned_ContainerConfig(&in.ContainerConfig, &out.ContainerConfig, s); err != nil {
@@ -767,10 +1061,9 @@ func Convert_v1alpha1_ScheduleConfig_To_unversioned_ScheduleConfig(in *ScheduleC
 func autoConvert_unversioned_ScheduleConfig_To_v1alpha1_ScheduleConfig(in *unversioned.ScheduleConfig, out *ScheduleConfig, s conversion.Scope) error {
 	out.RepeatInterval = Duration(in.RepeatInterval)
 	out.BeginImmediately = in.BeginImmediately
+	// WARNING: in.Cron requires manual conversion: does not exist in peer-type
+	// WARNING: in.TimeZone requires manual conversion: does not exist in peer-type
+	// WARNING: in.AllowedWindows requires manual conversion: does not exist in peer-type
+	// WARNING: in.BlockedWindows requires manual conversion: does not exist in peer-type
 	return nil
 }
-
-// Convert_unversioned_ScheduleConfig_To_v1alpha1_ScheduleConfig is an autogenerated conversion function.
-func Convert_unversioned_ScheduleConfig_To_v1alpha1_ScheduleConfig(in *unversioned.ScheduleConfig, out *ScheduleConfig, s conversion.Scope) error {
-	return autoConvert_unversioned_ScheduleConfig_To_v1alpha1_ScheduleConfig(in, out, s)
-}
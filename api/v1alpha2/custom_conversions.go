@@ -2,12 +2,19 @@ package v1alpha2
 
 import (
 	unversioned "github.com/eraser-dev/eraser/api/unversioned"
+	"github.com/eraser-dev/eraser/api/unversioned/config"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 )
 
 //nolint:revive
 func Convert_v1alpha2_ManagerConfig_To_unversioned_ManagerConfig(in *ManagerConfig, out *unversioned.ManagerConfig, s conversion.Scope) error {
-	return autoConvert_v1alpha2_ManagerConfig_To_unversioned_ManagerConfig(in, out, s)
+	if err := autoConvert_v1alpha2_ManagerConfig_To_unversioned_ManagerConfig(in, out, s); err != nil {
+		return err
+	}
+
+	// v1alpha2 has no exporter options, so the exporter keeps the defaults
+	out.OTLP = config.Default().Manager.OTLP
+	return nil
 }
 
 //nolint:revive
@@ -43,3 +50,18 @@ func manualConvert_unversioned_RuntimeSpec_To_v1alpha2_Runtime(in *unversioned.R
 func Convert_unversioned_RuntimeSpec_To_v1alpha2_Runtime(in *unversioned.RuntimeSpec, out *Runtime, s conversion.Scope) error {
 	return manualConvert_unversioned_RuntimeSpec_To_v1alpha2_Runtime(in, out, s)
 }
+
+//nolint:revive
+func Convert_unversioned_ContainerConfig_To_v1alpha2_ContainerConfig(in *unversioned.ContainerConfig, out *ContainerConfig, s conversion.Scope) error {
+	return autoConvert_unversioned_ContainerConfig_To_v1alpha2_ContainerConfig(in, out, s)
+}
+
+//nolint:revive
+func Convert_unversioned_ScheduleConfig_To_v1alpha2_ScheduleConfig(in *unversioned.ScheduleConfig, out *ScheduleConfig, s conversion.Scope) error {
+	return autoConvert_unversioned_ScheduleConfig_To_v1alpha2_ScheduleConfig(in, out, s)
+}
+
+//nolint:revive
+func Convert_unversioned_ImageJobConfig_To_v1alpha2_ImageJobConfig(in *unversioned.ImageJobConfig, out *ImageJobConfig, s conversion.Scope) error {
+	return autoConvert_unversioned_ImageJobConfig_To_v1alpha2_ImageJobConfig(in, out, s)
+}
@@ -25,6 +25,7 @@ import (
 
 	unversioned "github.com/eraser-dev/eraser/api/unversioned"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -51,8 +52,13 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*unversioned.ContainerConfig)(nil), (*ContainerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_unversioned_ContainerConfig_To_v1alpha2_ContainerConfig(a.(*unversioned.ContainerConfig), b.(*ContainerConfig), scope)
+	if err := s.AddGeneratedConversionFunc((*EffectiveConfig)(nil), (*unversioned.EffectiveConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_EffectiveConfig_To_unversioned_EffectiveConfig(a.(*EffectiveConfig), b.(*unversioned.EffectiveConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*unversioned.EffectiveConfig)(nil), (*EffectiveConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_unversioned_EffectiveConfig_To_v1alpha2_EffectiveConfig(a.(*unversioned.EffectiveConfig), b.(*EffectiveConfig), scope)
 	}); err != nil {
 		return err
 	}
@@ -66,6 +72,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*EraserConfigList)(nil), (*unversioned.EraserConfigList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_EraserConfigList_To_unversioned_EraserConfigList(a.(*EraserConfigList), b.(*unversioned.EraserConfigList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*unversioned.EraserConfigList)(nil), (*EraserConfigList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_unversioned_EraserConfigList_To_v1alpha2_EraserConfigList(a.(*unversioned.EraserConfigList), b.(*EraserConfigList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*EraserConfigStatus)(nil), (*unversioned.EraserConfigStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha2_EraserConfigStatus_To_unversioned_EraserConfigStatus(a.(*EraserConfigStatus), b.(*unversioned.EraserConfigStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*unversioned.EraserConfigStatus)(nil), (*EraserConfigStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_unversioned_EraserConfigStatus_To_v1alpha2_EraserConfigStatus(a.(*unversioned.EraserConfigStatus), b.(*EraserConfigStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ImageJobCleanupConfig)(nil), (*unversioned.ImageJobCleanupConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_ImageJobCleanupConfig_To_unversioned_ImageJobCleanupConfig(a.(*ImageJobCleanupConfig), b.(*unversioned.ImageJobCleanupConfig), scope)
 	}); err != nil {
@@ -81,11 +107,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*unversioned.ImageJobConfig)(nil), (*ImageJobConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_unversioned_ImageJobConfig_To_v1alpha2_ImageJobConfig(a.(*unversioned.ImageJobConfig), b.(*ImageJobConfig), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*NodeFilterConfig)(nil), (*unversioned.NodeFilterConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_NodeFilterConfig_To_unversioned_NodeFilterConfig(a.(*NodeFilterConfig), b.(*unversioned.NodeFilterConfig), scope)
 	}); err != nil {
@@ -141,8 +162,13 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*unversioned.ScheduleConfig)(nil), (*ScheduleConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_unversioned_ScheduleConfig_To_v1alpha2_ScheduleConfig(a.(*unversioned.ScheduleConfig), b.(*ScheduleConfig), scope)
+	if err := s.AddConversionFunc((*unversioned.ContainerConfig)(nil), (*ContainerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_unversioned_ContainerConfig_To_v1alpha2_ContainerConfig(a.(*unversioned.ContainerConfig), b.(*ContainerConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddConversionFunc((*unversioned.ImageJobConfig)(nil), (*ImageJobConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_unversioned_ImageJobConfig_To_v1alpha2_ImageJobConfig(a.(*unversioned.ImageJobConfig), b.(*ImageJobConfig), scope)
 	}); err != nil {
 		return err
 	}
@@ -156,6 +182,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*unversioned.ScheduleConfig)(nil), (*ScheduleConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_unversioned_ScheduleConfig_To_v1alpha2_ScheduleConfig(a.(*unversioned.ScheduleConfig), b.(*ScheduleConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*ManagerConfig)(nil), (*unversioned.ManagerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha2_ManagerConfig_To_unversioned_ManagerConfig(a.(*ManagerConfig), b.(*unversioned.ManagerConfig), scope)
 	}); err != nil {
@@ -237,21 +268,51 @@ func autoConvert_unversioned_ContainerConfig_To_v1alpha2_ContainerConfig(in *unv
 	}
 	out.Config = (*string)(unsafe.Pointer(in.Config))
 	out.Volumes = *(*[]v1.Volume)(unsafe.Pointer(&in.Volumes))
+	// WARNING: in.WritableVolumes requires manual conversion: does not exist in peer-type
+	return nil
+}
+
+func autoConvert_v1alpha2_EffectiveConfig_To_unversioned_EffectiveConfig(in *EffectiveConfig, out *unversioned.EffectiveConfig, s conversion.Scope) error {
+	if err := Convert_v1alpha2_ManagerConfig_To_unversioned_ManagerConfig(&in.Manager, &out.Manager, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha2_Components_To_unversioned_Components(&in.Components, &out.Components, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha2_EffectiveConfig_To_unversioned_EffectiveConfig is an autogenerated conversion function.
+func Convert_v1alpha2_EffectiveConfig_To_unversioned_EffectiveConfig(in *EffectiveConfig, out *unversioned.EffectiveConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha2_EffectiveConfig_To_unversioned_EffectiveConfig(in, out, s)
+}
+
+func autoConvert_unversioned_EffectiveConfig_To_v1alpha2_EffectiveConfig(in *unversioned.EffectiveConfig, out *EffectiveConfig, s conversion.Scope) error {
+	if err := Convert_unversioned_ManagerConfig_To_v1alpha2_ManagerConfig(&in.Manager, &out.Manager, s); err != nil {
+		return err
+	}
+	if err := Convert_unversioned_Components_To_v1alpha2_Components(&in.Components, &out.Components, s); err != nil {
+		return err
+	}
 	return nil
 }
 
-// Convert_unversioned_ContainerConfig_To_v1alpha2_ContainerConfig is an autogenerated conversion function.
-func Convert_unversioned_ContainerConfig_To_v1alpha2_ContainerConfig(in *unversioned.ContainerConfig, out *ContainerConfig, s conversion.Scope) error {
-	return autoConvert_unversioned_ContainerConfig_To_v1alpha2_ContainerConfig(in, out, s)
+// Convert_unversioned_EffectiveConfig_To_v1alpha2_EffectiveConfig is an autogenerated conversion function.
+func Convert_unversioned_EffectiveConfig_To_v1alpha2_EffectiveConfig(in *unversioned.EffectiveConfig, out *EffectiveConfig, s conversion.Scope) error {
+	return autoConvert_unversioned_EffectiveConfig_To_v1alpha2_EffectiveConfig(in, out, s)
 }
 
 func autoConvert_v1alpha2_EraserConfig_To_unversioned_EraserConfig(in *EraserConfig, out *unversioned.EraserConfig, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha2_ManagerConfig_To_unversioned_ManagerConfig(&in.Manager, &out.Manager, s); err != nil {
 		return err
 	}
 	if err := Convert_v1alpha2_Components_To_unversioned_Components(&in.Components, &out.Components, s); err != nil {
 		return err
 	}
+	if err := Convert_v1alpha2_EraserConfigStatus_To_unversioned_EraserConfigStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -261,12 +322,16 @@ func Convert_v1alpha2_EraserConfig_To_unversioned_EraserConfig(in *EraserConfig,
 }
 
 func autoConvert_unversioned_EraserConfig_To_v1alpha2_EraserConfig(in *unversioned.EraserConfig, out *EraserConfig, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_unversioned_ManagerConfig_To_v1alpha2_ManagerConfig(&in.Manager, &out.Manager, s); err != nil {
 		return err
 	}
 	if err := Convert_unversioned_Components_To_v1alpha2_Components(&in.Components, &out.Components, s); err != nil {
 		return err
 	}
+	if err := Convert_unversioned_EraserConfigStatus_To_v1alpha2_EraserConfigStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -275,6 +340,92 @@ func Convert_unversioned_EraserConfig_To_v1alpha2_EraserConfig(in *unversioned.E
 	return autoConvert_unversioned_EraserConfig_To_v1alpha2_EraserConfig(in, out, s)
 }
 
+func autoConvert_v1alpha2_EraserConfigList_To_unversioned_EraserConfigList(in *EraserConfigList, out *unversioned.EraserConfigList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]unversioned.EraserConfig, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha2_EraserConfig_To_unversioned_EraserConfig(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha2_EraserConfigList_To_unversioned_EraserConfigList is an autogenerated conversion function.
+func Convert_v1alpha2_EraserConfigList_To_unversioned_EraserConfigList(in *EraserConfigList, out *unversioned.EraserConfigList, s conversion.Scope) error {
+	return autoConvert_v1alpha2_EraserConfigList_To_unversioned_EraserConfigList(in, out, s)
+}
+
+func autoConvert_unversioned_EraserConfigList_To_v1alpha2_EraserConfigList(in *unversioned.EraserConfigList, out *EraserConfigList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]EraserConfig, len(*in))
+		for i := range *in {
+			if err := Convert_unversioned_EraserConfig_To_v1alpha2_EraserConfig(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
+	return nil
+}
+
+// Convert_unversioned_EraserConfigList_To_v1alpha2_EraserConfigList is an autogenerated conversion function.
+func Convert_unversioned_EraserConfigList_To_v1alpha2_EraserConfigList(in *unversioned.EraserConfigList, out *EraserConfigList, s conversion.Scope) error {
+	return autoConvert_unversioned_EraserConfigList_To_v1alpha2_EraserConfigList(in, out, s)
+}
+
+func autoConvert_v1alpha2_EraserConfigStatus_To_unversioned_EraserConfigStatus(in *EraserConfigStatus, out *unversioned.EraserConfigStatus, s conversion.Scope) error {
+	out.ObservedGeneration = in.ObservedGeneration
+	out.LastLoadTime = (*metav1.Time)(unsafe.Pointer(in.LastLoadTime))
+	out.LastLoadError = in.LastLoadError
+	if in.Effective != nil {
+		in, out := &in.Effective, &out.Effective
+		*out = new(unversioned.EffectiveConfig)
+		if err := Convert_v1alpha2_EffectiveConfig_To_unversioned_EffectiveConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Effective = nil
+	}
+	out.NextScheduledRun = (*metav1.Time)(unsafe.Pointer(in.NextScheduledRun))
+	return nil
+}
+
+// Convert_v1alpha2_EraserConfigStatus_To_unversioned_EraserConfigStatus is an autogenerated conversion function.
+func Convert_v1alpha2_EraserConfigStatus_To_unversioned_EraserConfigStatus(in *EraserConfigStatus, out *unversioned.EraserConfigStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha2_EraserConfigStatus_To_unversioned_EraserConfigStatus(in, out, s)
+}
+
+func autoConvert_unversioned_EraserConfigStatus_To_v1alpha2_EraserConfigStatus(in *unversioned.EraserConfigStatus, out *EraserConfigStatus, s conversion.Scope) error {
+	out.ObservedGeneration = in.ObservedGeneration
+	out.LastLoadTime = (*metav1.Time)(unsafe.Pointer(in.LastLoadTime))
+	out.LastLoadError = in.LastLoadError
+	if in.Effective != nil {
+		in, out := &in.Effective, &out.Effective
+		*out = new(EffectiveConfig)
+		if err := Convert_unversioned_EffectiveConfig_To_v1alpha2_EffectiveConfig(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Effective = nil
+	}
+	out.NextScheduledRun = (*metav1.Time)(unsafe.Pointer(in.NextScheduledRun))
+	return nil
+}
+
+// Convert_unversioned_EraserConfigStatus_To_v1alpha2_EraserConfigStatus is an autogenerated conversion function.
+func Convert_unversioned_EraserConfigStatus_To_v1alpha2_EraserConfigStatus(in *unversioned.EraserConfigStatus, out *EraserConfigStatus, s conversion.Scope) error {
+	return autoConvert_unversioned_EraserConfigStatus_To_v1alpha2_EraserConfigStatus(in, out, s)
+}
+
 func autoConvert_v1alpha2_ImageJobCleanupConfig_To_unversioned_ImageJobCleanupConfig(in *ImageJobCleanupConfig, out *unversioned.ImageJobCleanupConfig, s conversion.Scope) error {
 	out.DelayOnSuccess = unversioned.Duration(in.DelayOnSuccess)
 	out.DelayOnFailure = unversioned.Duration(in.DelayOnFailure)
@@ -315,14 +466,11 @@ func autoConvert_unversioned_ImageJobConfig_To_v1alpha2_ImageJobConfig(in *unver
 	if err := Convert_unversioned_ImageJobCleanupConfig_To_v1alpha2_ImageJobCleanupConfig(&in.Cleanup, &out.Cleanup, s); err != nil {
 		return err
 	}
+	// WARNING: in.Rollout requires manual conversion: does not exist in peer-type
+	// WARNING: in.AllowedImages requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_unversioned_ImageJobConfig_To_v1alpha2_ImageJobConfig is an autogenerated conversion function.
-func Convert_unversioned_ImageJobConfig_To_v1alpha2_ImageJobConfig(in *unversioned.ImageJobConfig, out *ImageJobConfig, s conversion.Scope) error {
-	return autoConvert_unversioned_ImageJobConfig_To_v1alpha2_ImageJobConfig(in, out, s)
-}
-
 func autoConvert_v1alpha2_ManagerConfig_To_unversioned_ManagerConfig(in *ManagerConfig, out *unversioned.ManagerConfig, s conversion.Scope) error {
 	if err := Convert_v1alpha2_Runtime_To_unversioned_RuntimeSpec(&in.Runtime, &out.Runtime, s); err != nil {
 		return err
@@ -351,6 +499,8 @@ func autoConvert_unversioned_ManagerConfig_To_v1alpha2_ManagerConfig(in *unversi
 		return err
 	}
 	out.OTLPEndpoint = in.OTLPEndpoint
+	// WARNING: in.OTLP requires manual conversion: does not exist in peer-type
+	// WARNING: in.Tracing requires manual conversion: does not exist in peer-type
 	out.LogLevel = in.LogLevel
 	if err := Convert_unversioned_ScheduleConfig_To_v1alpha2_ScheduleConfig(&in.Scheduling, &out.Scheduling, s); err != nil {
 		return err
@@ -367,6 +517,7 @@ func autoConvert_unversioned_ManagerConfig_To_v1alpha2_ManagerConfig(in *unversi
 	}
 	out.PriorityClassName = in.PriorityClassName
 	// WARNING: in.AdditionalPodLabels requires manual conversion: does not exist in peer-type
+	// WARNING: in.Metrics requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -498,10 +649,9 @@ func Convert_v1alpha2_ScheduleConfig_To_unversioned_ScheduleConfig(in *ScheduleC
 func autoConvert_unversioned_ScheduleConfig_To_v1alpha2_ScheduleConfig(in *unversioned.ScheduleConfig, out *ScheduleConfig, s conversion.Scope) error {
 	out.RepeatInterval = Duration(in.RepeatInterval)
 	out.BeginImmediately = in.BeginImmediately
+	// WARNING: in.Cron requires manual conversion: does not exist in peer-type
+	// WARNING: in.TimeZone requires manual conversion: does not exist in peer-type
+	// WARNING: in.AllowedWindows requires manual conversion: does not exist in peer-type
+	// WARNING: in.BlockedWindows requires manual conversion: does not exist in peer-type
 	return nil
 }
-
-// Convert_unversioned_ScheduleConfig_To_v1alpha2_ScheduleConfig is an autogenerated conversion function.
-func Convert_unversioned_ScheduleConfig_To_v1alpha2_ScheduleConfig(in *unversioned.ScheduleConfig, out *ScheduleConfig, s conversion.Scope) error {
-	return autoConvert_unversioned_ScheduleConfig_To_v1alpha2_ScheduleConfig(in, out, s)
-}
@@ -41,7 +41,17 @@ func Default() *v1alpha3.EraserConfig {
 				Address: "unix:///run/containerd/containerd.sock",
 			},
 			OTLPEndpoint: "",
-			LogLevel:     "info",
+			OTLP: v1alpha3.OTLPConfig{
+				Protocol:       "http/protobuf",
+				Insecure:       true,
+				Compression:    "none",
+				ExportInterval: v1alpha3.Duration(time.Minute),
+			},
+			Tracing: v1alpha3.TracingConfig{
+				Enabled:     false,
+				SampleRatio: 1.0,
+			},
+			LogLevel: "info",
 			Scheduling: v1alpha3.ScheduleConfig{
 				RepeatInterval:   v1alpha3.Duration(oneDay),
 				BeginImmediately: true,
@@ -65,6 +75,10 @@ func Default() *v1alpha3.EraserConfig {
 				},
 			},
 			AdditionalPodLabels: map[string]string{},
+			Metrics: v1alpha3.MetricsConfig{
+				RepositoryLabels: false,
+				MaxRepositories:  10,
+			},
 		},
 		Components: v1alpha3.Components{
 			Collector: v1alpha3.OptionalContainerConfig{
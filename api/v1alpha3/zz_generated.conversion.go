@@ -25,6 +25,7 @@ import (
 
 	unversioned "github.com/eraser-dev/eraser/api/unversioned"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	conversion "k8s.io/apimachinery/pkg/conversion"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -56,6 +57,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*EffectiveConfig)(nil), (*unversioned.EffectiveConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_EffectiveConfig_To_unversioned_EffectiveConfig(a.(*EffectiveConfig), b.(*unversioned.EffectiveConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*unversioned.EffectiveConfig)(nil), (*EffectiveConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_unversioned_EffectiveConfig_To_v1alpha3_EffectiveConfig(a.(*unversioned.EffectiveConfig), b.(*EffectiveConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*EraserConfig)(nil), (*unversioned.EraserConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_EraserConfig_To_unversioned_EraserConfig(a.(*EraserConfig), b.(*unversioned.EraserConfig), scope)
 	}); err != nil {
@@ -66,6 +77,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*EraserConfigList)(nil), (*unversioned.EraserConfigList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_EraserConfigList_To_unversioned_EraserConfigList(a.(*EraserConfigList), b.(*unversioned.EraserConfigList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*unversioned.EraserConfigList)(nil), (*EraserConfigList)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_unversioned_EraserConfigList_To_v1alpha3_EraserConfigList(a.(*unversioned.EraserConfigList), b.(*EraserConfigList), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*EraserConfigStatus)(nil), (*unversioned.EraserConfigStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_EraserConfigStatus_To_unversioned_EraserConfigStatus(a.(*EraserConfigStatus), b.(*unversioned.EraserConfigStatus), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*unversioned.EraserConfigStatus)(nil), (*EraserConfigStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_unversioned_EraserConfigStatus_To_v1alpha3_EraserConfigStatus(a.(*unversioned.EraserConfigStatus), b.(*EraserConfigStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ImageJobCleanupConfig)(nil), (*unversioned.ImageJobCleanupConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_ImageJobCleanupConfig_To_unversioned_ImageJobCleanupConfig(a.(*ImageJobCleanupConfig), b.(*unversioned.ImageJobCleanupConfig), scope)
 	}); err != nil {
@@ -86,6 +117,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*MaintenanceWindow)(nil), (*unversioned.MaintenanceWindow)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_MaintenanceWindow_To_unversioned_MaintenanceWindow(a.(*MaintenanceWindow), b.(*unversioned.MaintenanceWindow), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*unversioned.MaintenanceWindow)(nil), (*MaintenanceWindow)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_unversioned_MaintenanceWindow_To_v1alpha3_MaintenanceWindow(a.(*unversioned.MaintenanceWindow), b.(*MaintenanceWindow), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*ManagerConfig)(nil), (*unversioned.ManagerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_ManagerConfig_To_unversioned_ManagerConfig(a.(*ManagerConfig), b.(*unversioned.ManagerConfig), scope)
 	}); err != nil {
@@ -96,6 +137,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*MetricsConfig)(nil), (*unversioned.MetricsConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_MetricsConfig_To_unversioned_MetricsConfig(a.(*MetricsConfig), b.(*unversioned.MetricsConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*unversioned.MetricsConfig)(nil), (*MetricsConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_unversioned_MetricsConfig_To_v1alpha3_MetricsConfig(a.(*unversioned.MetricsConfig), b.(*MetricsConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NodeFilterConfig)(nil), (*unversioned.NodeFilterConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_NodeFilterConfig_To_unversioned_NodeFilterConfig(a.(*NodeFilterConfig), b.(*unversioned.NodeFilterConfig), scope)
 	}); err != nil {
@@ -106,6 +157,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*OTLPConfig)(nil), (*unversioned.OTLPConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_OTLPConfig_To_unversioned_OTLPConfig(a.(*OTLPConfig), b.(*unversioned.OTLPConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*unversioned.OTLPConfig)(nil), (*OTLPConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_unversioned_OTLPConfig_To_v1alpha3_OTLPConfig(a.(*unversioned.OTLPConfig), b.(*OTLPConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*OptionalContainerConfig)(nil), (*unversioned.OptionalContainerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_OptionalContainerConfig_To_unversioned_OptionalContainerConfig(a.(*OptionalContainerConfig), b.(*unversioned.OptionalContainerConfig), scope)
 	}); err != nil {
@@ -146,6 +207,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*RolloutConfig)(nil), (*unversioned.RolloutConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_RolloutConfig_To_unversioned_RolloutConfig(a.(*RolloutConfig), b.(*unversioned.RolloutConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*unversioned.RolloutConfig)(nil), (*RolloutConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_unversioned_RolloutConfig_To_v1alpha3_RolloutConfig(a.(*unversioned.RolloutConfig), b.(*RolloutConfig), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*RuntimeSpec)(nil), (*unversioned.RuntimeSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_RuntimeSpec_To_unversioned_RuntimeSpec(a.(*RuntimeSpec), b.(*unversioned.RuntimeSpec), scope)
 	}); err != nil {
@@ -166,6 +237,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*TracingConfig)(nil), (*unversioned.TracingConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_TracingConfig_To_unversioned_TracingConfig(a.(*TracingConfig), b.(*unversioned.TracingConfig), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*unversioned.TracingConfig)(nil), (*TracingConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_unversioned_TracingConfig_To_v1alpha3_TracingConfig(a.(*unversioned.TracingConfig), b.(*TracingConfig), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -217,6 +298,7 @@ func autoConvert_v1alpha3_ContainerConfig_To_unversioned_ContainerConfig(in *Con
 	}
 	out.Config = (*string)(unsafe.Pointer(in.Config))
 	out.Volumes = *(*[]v1.Volume)(unsafe.Pointer(&in.Volumes))
+	out.WritableVolumes = *(*[]v1.Volume)(unsafe.Pointer(&in.WritableVolumes))
 	return nil
 }
 
@@ -237,6 +319,7 @@ func autoConvert_unversioned_ContainerConfig_To_v1alpha3_ContainerConfig(in *unv
 	}
 	out.Config = (*string)(unsafe.Pointer(in.Config))
 	out.Volumes = *(*[]v1.Volume)(unsafe.Pointer(&in.Volumes))
+	out.WritableVolumes = *(*[]v1.Volume)(unsafe.Pointer(&in.WritableVolumes))
 	return nil
 }
 
@@ -245,13 +328,47 @@ func Convert_unversioned_ContainerConfig_To_v1alpha3_ContainerConfig(in *unversi
 	return autoConvert_unversioned_ContainerConfig_To_v1alpha3_ContainerConfig(in, out, s)
 }
 
+func autoConvert_v1alpha3_EffectiveConfig_To_unversioned_EffectiveConfig(in *EffectiveConfig, out *unversioned.EffectiveConfig, s conversion.Scope) error {
+	if err := Convert_v1alpha3_ManagerConfig_To_unversioned_ManagerConfig(&in.Manager, &out.Manager, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha3_Components_To_unversioned_Components(&in.Components, &out.Components, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_v1alpha3_EffectiveConfig_To_unversioned_EffectiveConfig is an autogenerated conversion function.
+func Convert_v1alpha3_EffectiveConfig_To_unversioned_EffectiveConfig(in *EffectiveConfig, out *unversioned.EffectiveConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha3_EffectiveConfig_To_unversioned_EffectiveConfig(in, out, s)
+}
+
+func autoConvert_unversioned_EffectiveConfig_To_v1alpha3_EffectiveConfig(in *unversioned.EffectiveConfig, out *EffectiveConfig, s conversion.Scope) error {
+	if err := Convert_unversioned_ManagerConfig_To_v1alpha3_ManagerConfig(&in.Manager, &out.Manager, s); err != nil {
+		return err
+	}
+	if err := Convert_unversioned_Components_To_v1alpha3_Components(&in.Components, &out.Components, s); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Convert_unversioned_EffectiveConfig_To_v1alpha3_EffectiveConfig is an autogenerated conversion function.
+func Convert_unversioned_EffectiveConfig_To_v1alpha3_EffectiveConfig(in *unversioned.EffectiveConfig, out *EffectiveConfig, s conversion.Scope) error {
+	return autoConvert_unversioned_EffectiveConfig_To_v1alpha3_EffectiveConfig(in, out, s)
+}
+
 func autoConvert_v1alpha3_EraserConfig_To_unversioned_EraserConfig(in *EraserConfig, out *unversioned.EraserConfig, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha3_ManagerConfig_To_unversioned_ManagerConfig(&in.Manager, &out.Manager, s); err != nil {
 		return err
 	}
 	if err := Convert_v1alpha3_Components_To_unversioned_Components(&in.Components, &out.Components, s); err != nil {
 		return err
 	}
+	if err := Convert_v1alpha3_EraserConfigStatus_To_unversioned_EraserConfigStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -261,12 +378,16 @@ func Convert_v1alpha3_EraserConfig_To_unversioned_EraserConfig(in *EraserConfig,
 }
 
 func autoConvert_unversioned_EraserConfig_To_v1alpha3_EraserConfig(in *unversioned.EraserConfig, out *EraserConfig, s conversion.Scope) error {
+	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_unversioned_ManagerConfig_To_v1alpha3_ManagerConfig(&in.Manager, &out.Manager, s); err != nil {
 		return err
 	}
 	if err := Convert_unversioned_Components_To_v1alpha3_Components(&in.Components, &out.Components, s); err != nil {
 		return err
 	}
+	if err := Convert_unversioned_EraserConfigStatus_To_v1alpha3_EraserConfigStatus(&in.Status, &out.Status, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -275,6 +396,56 @@ func Convert_unversioned_EraserConfig_To_v1alpha3_EraserConfig(in *unversioned.E
 	return autoConvert_unversioned_EraserConfig_To_v1alpha3_EraserConfig(in, out, s)
 }
 
+func autoConvert_v1alpha3_EraserConfigList_To_unversioned_EraserConfigList(in *EraserConfigList, out *unversioned.EraserConfigList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = *(*[]unversioned.EraserConfig)(unsafe.Pointer(&in.Items))
+	return nil
+}
+
+// Convert_v1alpha3_EraserConfigList_To_unversioned_EraserConfigList is an autogenerated conversion function.
+func Convert_v1alpha3_EraserConfigList_To_unversioned_EraserConfigList(in *EraserConfigList, out *unversioned.EraserConfigList, s conversion.Scope) error {
+	return autoConvert_v1alpha3_EraserConfigList_To_unversioned_EraserConfigList(in, out, s)
+}
+
+func autoConvert_unversioned_EraserConfigList_To_v1alpha3_EraserConfigList(in *unversioned.EraserConfigList, out *EraserConfigList, s conversion.Scope) error {
+	out.ListMeta = in.ListMeta
+	out.Items = *(*[]EraserConfig)(unsafe.Pointer(&in.Items))
+	return nil
+}
+
+// Convert_unversioned_EraserConfigList_To_v1alpha3_EraserConfigList is an autogenerated conversion function.
+func Convert_unversioned_EraserConfigList_To_v1alpha3_EraserConfigList(in *unversioned.EraserConfigList, out *EraserConfigList, s conversion.Scope) error {
+	return autoConvert_unversioned_EraserConfigList_To_v1alpha3_EraserConfigList(in, out, s)
+}
+
+func autoConvert_v1alpha3_EraserConfigStatus_To_unversioned_EraserConfigStatus(in *EraserConfigStatus, out *unversioned.EraserConfigStatus, s conversion.Scope) error {
+	out.ObservedGeneration = in.ObservedGeneration
+	out.LastLoadTime = (*metav1.Time)(unsafe.Pointer(in.LastLoadTime))
+	out.LastLoadError = in.LastLoadError
+	out.Effective = (*unversioned.EffectiveConfig)(unsafe.Pointer(in.Effective))
+	out.NextScheduledRun = (*metav1.Time)(unsafe.Pointer(in.NextScheduledRun))
+	return nil
+}
+
+// Convert_v1alpha3_EraserConfigStatus_To_unversioned_EraserConfigStatus is an autogenerated conversion function.
+func Convert_v1alpha3_EraserConfigStatus_To_unversioned_EraserConfigStatus(in *EraserConfigStatus, out *unversioned.EraserConfigStatus, s conversion.Scope) error {
+	return autoConvert_v1alpha3_EraserConfigStatus_To_unversioned_EraserConfigStatus(in, out, s)
+}
+
+func autoConvert_unversioned_EraserConfigStatus_To_v1alpha3_EraserConfigStatus(in *unversioned.EraserConfigStatus, out *EraserConfigStatus, s conversion.Scope) error {
+	out.ObservedGeneration = in.ObservedGeneration
+	out.LastLoadTime = (*metav1.Time)(unsafe.Pointer(in.LastLoadTime))
+	out.LastLoadError = in.LastLoadError
+	out.Effective = (*EffectiveConfig)(unsafe.Pointer(in.Effective))
+	out.NextScheduledRun = (*metav1.Time)(unsafe.Pointer(in.NextScheduledRun))
+	return nil
+}
+
+// Convert_unversioned_EraserConfigStatus_To_v1alpha3_EraserConfigStatus is an autogenerated conversion function.
+func Convert_unversioned_EraserConfigStatus_To_v1alpha3_EraserConfigStatus(in *unversioned.EraserConfigStatus, out *EraserConfigStatus, s conversion.Scope) error {
+	return autoConvert_unversioned_EraserConfigStatus_To_v1alpha3_EraserConfigStatus(in, out, s)
+}
+
 func autoConvert_v1alpha3_ImageJobCleanupConfig_To_unversioned_ImageJobCleanupConfig(in *ImageJobCleanupConfig, out *unversioned.ImageJobCleanupConfig, s conversion.Scope) error {
 	out.DelayOnSuccess = unversioned.Duration(in.DelayOnSuccess)
 	out.DelayOnFailure = unversioned.Duration(in.DelayOnFailure)
@@ -302,6 +473,10 @@ func autoConvert_v1alpha3_ImageJobConfig_To_unversioned_ImageJobConfig(in *Image
 	if err := Convert_v1alpha3_ImageJobCleanupConfig_To_unversioned_ImageJobCleanupConfig(&in.Cleanup, &out.Cleanup, s); err != nil {
 		return err
 	}
+	if err := Convert_v1alpha3_RolloutConfig_To_unversioned_RolloutConfig(&in.Rollout, &out.Rollout, s); err != nil {
+		return err
+	}
+	out.AllowedImages = *(*[]string)(unsafe.Pointer(&in.AllowedImages))
 	return nil
 }
 
@@ -315,6 +490,10 @@ func autoConvert_unversioned_ImageJobConfig_To_v1alpha3_ImageJobConfig(in *unver
 	if err := Convert_unversioned_ImageJobCleanupConfig_To_v1alpha3_ImageJobCleanupConfig(&in.Cleanup, &out.Cleanup, s); err != nil {
 		return err
 	}
+	if err := Convert_unversioned_RolloutConfig_To_v1alpha3_RolloutConfig(&in.Rollout, &out.Rollout, s); err != nil {
+		return err
+	}
+	out.AllowedImages = *(*[]string)(unsafe.Pointer(&in.AllowedImages))
 	return nil
 }
 
@@ -323,11 +502,39 @@ func Convert_unversioned_ImageJobConfig_To_v1alpha3_ImageJobConfig(in *unversion
 	return autoConvert_unversioned_ImageJobConfig_To_v1alpha3_ImageJobConfig(in, out, s)
 }
 
+func autoConvert_v1alpha3_MaintenanceWindow_To_unversioned_MaintenanceWindow(in *MaintenanceWindow, out *unversioned.MaintenanceWindow, s conversion.Scope) error {
+	out.Cron = in.Cron
+	out.Duration = unversioned.Duration(in.Duration)
+	return nil
+}
+
+// Convert_v1alpha3_MaintenanceWindow_To_unversioned_MaintenanceWindow is an autogenerated conversion function.
+func Convert_v1alpha3_MaintenanceWindow_To_unversioned_MaintenanceWindow(in *MaintenanceWindow, out *unversioned.MaintenanceWindow, s conversion.Scope) error {
+	return autoConvert_v1alpha3_MaintenanceWindow_To_unversioned_MaintenanceWindow(in, out, s)
+}
+
+func autoConvert_unversioned_MaintenanceWindow_To_v1alpha3_MaintenanceWindow(in *unversioned.MaintenanceWindow, out *MaintenanceWindow, s conversion.Scope) error {
+	out.Cron = in.Cron
+	out.Duration = Duration(in.Duration)
+	return nil
+}
+
+// Convert_unversioned_MaintenanceWindow_To_v1alpha3_MaintenanceWindow is an autogenerated conversion function.
+func Convert_unversioned_MaintenanceWindow_To_v1alpha3_MaintenanceWindow(in *unversioned.MaintenanceWindow, out *MaintenanceWindow, s conversion.Scope) error {
+	return autoConvert_unversioned_MaintenanceWindow_To_v1alpha3_MaintenanceWindow(in, out, s)
+}
+
 func autoConvert_v1alpha3_ManagerConfig_To_unversioned_ManagerConfig(in *ManagerConfig, out *unversioned.ManagerConfig, s conversion.Scope) error {
 	if err := Convert_v1alpha3_RuntimeSpec_To_unversioned_RuntimeSpec(&in.Runtime, &out.Runtime, s); err != nil {
 		return err
 	}
 	out.OTLPEndpoint = in.OTLPEndpoint
+	if err := Convert_v1alpha3_OTLPConfig_To_unversioned_OTLPConfig(&in.OTLP, &out.OTLP, s); err != nil {
+		return err
+	}
+	if err := Convert_v1alpha3_TracingConfig_To_unversioned_TracingConfig(&in.Tracing, &out.Tracing, s); err != nil {
+		return err
+	}
 	out.LogLevel = in.LogLevel
 	if err := Convert_v1alpha3_ScheduleConfig_To_unversioned_ScheduleConfig(&in.Scheduling, &out.Scheduling, s); err != nil {
 		return err
@@ -344,6 +551,9 @@ func autoConvert_v1alpha3_ManagerConfig_To_unversioned_ManagerConfig(in *Manager
 	}
 	out.PriorityClassName = in.PriorityClassName
 	out.AdditionalPodLabels = *(*map[string]string)(unsafe.Pointer(&in.AdditionalPodLabels))
+	if err := Convert_v1alpha3_MetricsConfig_To_unversioned_MetricsConfig(&in.Metrics, &out.Metrics, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -357,6 +567,12 @@ func autoConvert_unversioned_ManagerConfig_To_v1alpha3_ManagerConfig(in *unversi
 		return err
 	}
 	out.OTLPEndpoint = in.OTLPEndpoint
+	if err := Convert_unversioned_OTLPConfig_To_v1alpha3_OTLPConfig(&in.OTLP, &out.OTLP, s); err != nil {
+		return err
+	}
+	if err := Convert_unversioned_TracingConfig_To_v1alpha3_TracingConfig(&in.Tracing, &out.Tracing, s); err != nil {
+		return err
+	}
 	out.LogLevel = in.LogLevel
 	if err := Convert_unversioned_ScheduleConfig_To_v1alpha3_ScheduleConfig(&in.Scheduling, &out.Scheduling, s); err != nil {
 		return err
@@ -373,6 +589,9 @@ func autoConvert_unversioned_ManagerConfig_To_v1alpha3_ManagerConfig(in *unversi
 	}
 	out.PriorityClassName = in.PriorityClassName
 	out.AdditionalPodLabels = *(*map[string]string)(unsafe.Pointer(&in.AdditionalPodLabels))
+	if err := Convert_unversioned_MetricsConfig_To_v1alpha3_MetricsConfig(&in.Metrics, &out.Metrics, s); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -381,6 +600,28 @@ func Convert_unversioned_ManagerConfig_To_v1alpha3_ManagerConfig(in *unversioned
 	return autoConvert_unversioned_ManagerConfig_To_v1alpha3_ManagerConfig(in, out, s)
 }
 
+func autoConvert_v1alpha3_MetricsConfig_To_unversioned_MetricsConfig(in *MetricsConfig, out *unversioned.MetricsConfig, s conversion.Scope) error {
+	out.RepositoryLabels = in.RepositoryLabels
+	out.MaxRepositories = in.MaxRepositories
+	return nil
+}
+
+// Convert_v1alpha3_MetricsConfig_To_unversioned_MetricsConfig is an autogenerated conversion function.
+func Convert_v1alpha3_MetricsConfig_To_unversioned_MetricsConfig(in *MetricsConfig, out *unversioned.MetricsConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha3_MetricsConfig_To_unversioned_MetricsConfig(in, out, s)
+}
+
+func autoConvert_unversioned_MetricsConfig_To_v1alpha3_MetricsConfig(in *unversioned.MetricsConfig, out *MetricsConfig, s conversion.Scope) error {
+	out.RepositoryLabels = in.RepositoryLabels
+	out.MaxRepositories = in.MaxRepositories
+	return nil
+}
+
+// Convert_unversioned_MetricsConfig_To_v1alpha3_MetricsConfig is an autogenerated conversion function.
+func Convert_unversioned_MetricsConfig_To_v1alpha3_MetricsConfig(in *unversioned.MetricsConfig, out *MetricsConfig, s conversion.Scope) error {
+	return autoConvert_unversioned_MetricsConfig_To_v1alpha3_MetricsConfig(in, out, s)
+}
+
 func autoConvert_v1alpha3_NodeFilterConfig_To_unversioned_NodeFilterConfig(in *NodeFilterConfig, out *unversioned.NodeFilterConfig, s conversion.Scope) error {
 	out.Type = in.Type
 	out.Selectors = *(*[]string)(unsafe.Pointer(&in.Selectors))
@@ -403,6 +644,36 @@ func Convert_unversioned_NodeFilterConfig_To_v1alpha3_NodeFilterConfig(in *unver
 	return autoConvert_unversioned_NodeFilterConfig_To_v1alpha3_NodeFilterConfig(in, out, s)
 }
 
+func autoConvert_v1alpha3_OTLPConfig_To_unversioned_OTLPConfig(in *OTLPConfig, out *unversioned.OTLPConfig, s conversion.Scope) error {
+	out.Protocol = in.Protocol
+	out.Insecure = in.Insecure
+	out.TLSSecretName = in.TLSSecretName
+	out.HeadersSecretName = in.HeadersSecretName
+	out.Compression = in.Compression
+	out.ExportInterval = unversioned.Duration(in.ExportInterval)
+	return nil
+}
+
+// Convert_v1alpha3_OTLPConfig_To_unversioned_OTLPConfig is an autogenerated conversion function.
+func Convert_v1alpha3_OTLPConfig_To_unversioned_OTLPConfig(in *OTLPConfig, out *unversioned.OTLPConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha3_OTLPConfig_To_unversioned_OTLPConfig(in, out, s)
+}
+
+func autoConvert_unversioned_OTLPConfig_To_v1alpha3_OTLPConfig(in *unversioned.OTLPConfig, out *OTLPConfig, s conversion.Scope) error {
+	out.Protocol = in.Protocol
+	out.Insecure = in.Insecure
+	out.TLSSecretName = in.TLSSecretName
+	out.HeadersSecretName = in.HeadersSecretName
+	out.Compression = in.Compression
+	out.ExportInterval = Duration(in.ExportInterval)
+	return nil
+}
+
+// Convert_unversioned_OTLPConfig_To_v1alpha3_OTLPConfig is an autogenerated conversion function.
+func Convert_unversioned_OTLPConfig_To_v1alpha3_OTLPConfig(in *unversioned.OTLPConfig, out *OTLPConfig, s conversion.Scope) error {
+	return autoConvert_unversioned_OTLPConfig_To_v1alpha3_OTLPConfig(in, out, s)
+}
+
 func autoConvert_v1alpha3_OptionalContainerConfig_To_unversioned_OptionalContainerConfig(in *OptionalContainerConfig, out *unversioned.OptionalContainerConfig, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	if err := Convert_v1alpha3_ContainerConfig_To_unversioned_ContainerConfig(&in.ContainerConfig, &out.ContainerConfig, s); err != nil {
@@ -495,6 +766,34 @@ func Convert_unversioned_ResourceRequirements_To_v1alpha3_ResourceRequirements(i
 	return autoConvert_unversioned_ResourceRequirements_To_v1alpha3_ResourceRequirements(in, out, s)
 }
 
+func autoConvert_v1alpha3_RolloutConfig_To_unversioned_RolloutConfig(in *RolloutConfig, out *unversioned.RolloutConfig, s conversion.Scope) error {
+	out.BatchSize = in.BatchSize
+	out.PauseBetweenBatches = unversioned.Duration(in.PauseBetweenBatches)
+	out.NodePoolLabel = in.NodePoolLabel
+	out.NodePoolConcurrency = in.NodePoolConcurrency
+	out.StopOnFailureRatio = in.StopOnFailureRatio
+	return nil
+}
+
+// Convert_v1alpha3_RolloutConfig_To_unversioned_RolloutConfig is an autogenerated conversion function.
+func Convert_v1alpha3_RolloutConfig_To_unversioned_RolloutConfig(in *RolloutConfig, out *unversioned.RolloutConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha3_RolloutConfig_To_unversioned_RolloutConfig(in, out, s)
+}
+
+func autoConvert_unversioned_RolloutConfig_To_v1alpha3_RolloutConfig(in *unversioned.RolloutConfig, out *RolloutConfig, s conversion.Scope) error {
+	out.BatchSize = in.BatchSize
+	out.PauseBetweenBatches = Duration(in.PauseBetweenBatches)
+	out.NodePoolLabel = in.NodePoolLabel
+	out.NodePoolConcurrency = in.NodePoolConcurrency
+	out.StopOnFailureRatio = in.StopOnFailureRatio
+	return nil
+}
+
+// Convert_unversioned_RolloutConfig_To_v1alpha3_RolloutConfig is an autogenerated conversion function.
+func Convert_unversioned_RolloutConfig_To_v1alpha3_RolloutConfig(in *unversioned.RolloutConfig, out *RolloutConfig, s conversion.Scope) error {
+	return autoConvert_unversioned_RolloutConfig_To_v1alpha3_RolloutConfig(in, out, s)
+}
+
 func autoConvert_v1alpha3_RuntimeSpec_To_unversioned_RuntimeSpec(in *RuntimeSpec, out *unversioned.RuntimeSpec, s conversion.Scope) error {
 	out.Name = unversioned.Runtime(in.Name)
 	out.Address = in.Address
@@ -520,6 +819,10 @@ func Convert_unversioned_RuntimeSpec_To_v1alpha3_RuntimeSpec(in *unversioned.Run
 func autoConvert_v1alpha3_ScheduleConfig_To_unversioned_ScheduleConfig(in *ScheduleConfig, out *unversioned.ScheduleConfig, s conversion.Scope) error {
 	out.RepeatInterval = unversioned.Duration(in.RepeatInterval)
 	out.BeginImmediately = in.BeginImmediately
+	out.Cron = in.Cron
+	out.TimeZone = in.TimeZone
+	out.AllowedWindows = *(*[]unversioned.MaintenanceWindow)(unsafe.Pointer(&in.AllowedWindows))
+	out.BlockedWindows = *(*[]unversioned.MaintenanceWindow)(unsafe.Pointer(&in.BlockedWindows))
 	return nil
 }
 
@@ -531,6 +834,10 @@ func Convert_v1alpha3_ScheduleConfig_To_unversioned_ScheduleConfig(in *ScheduleC
 func autoConvert_unversioned_ScheduleConfig_To_v1alpha3_ScheduleConfig(in *unversioned.ScheduleConfig, out *ScheduleConfig, s conversion.Scope) error {
 	out.RepeatInterval = Duration(in.RepeatInterval)
 	out.BeginImmediately = in.BeginImmediately
+	out.Cron = in.Cron
+	out.TimeZone = in.TimeZone
+	out.AllowedWindows = *(*[]MaintenanceWindow)(unsafe.Pointer(&in.AllowedWindows))
+	out.BlockedWindows = *(*[]MaintenanceWindow)(unsafe.Pointer(&in.BlockedWindows))
 	return nil
 }
 
@@ -538,3 +845,25 @@ func autoConvert_unversioned_ScheduleConfig_To_v1alpha3_ScheduleConfig(in *unver
 func Convert_unversioned_ScheduleConfig_To_v1alpha3_ScheduleConfig(in *unversioned.ScheduleConfig, out *ScheduleConfig, s conversion.Scope) error {
 	return autoConvert_unversioned_ScheduleConfig_To_v1alpha3_ScheduleConfig(in, out, s)
 }
+
+func autoConvert_v1alpha3_TracingConfig_To_unversioned_TracingConfig(in *TracingConfig, out *unversioned.TracingConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.SampleRatio = in.SampleRatio
+	return nil
+}
+
+// Convert_v1alpha3_TracingConfig_To_unversioned_TracingConfig is an autogenerated conversion function.
+func Convert_v1alpha3_TracingConfig_To_unversioned_TracingConfig(in *TracingConfig, out *unversioned.TracingConfig, s conversion.Scope) error {
+	return autoConvert_v1alpha3_TracingConfig_To_unversioned_TracingConfig(in, out, s)
+}
+
+func autoConvert_unversioned_TracingConfig_To_v1alpha3_TracingConfig(in *unversioned.TracingConfig, out *TracingConfig, s conversion.Scope) error {
+	out.Enabled = in.Enabled
+	out.SampleRatio = in.SampleRatio
+	return nil
+}
+
+// Convert_unversioned_TracingConfig_To_v1alpha3_TracingConfig is an autogenerated conversion function.
+func Convert_unversioned_TracingConfig_To_v1alpha3_TracingConfig(in *unversioned.TracingConfig, out *TracingConfig, s conversion.Scope) error {
+	return autoConvert_unversioned_TracingConfig_To_v1alpha3_TracingConfig(in, out, s)
+}
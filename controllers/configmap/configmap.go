@@ -102,8 +102,17 @@ func newReconciler(mgr manager.Manager, cfg *config.Manager) (reconcile.Reconcil
 		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 		defer cancel()
 
-		_, _, provider = metrics.ConfigureMetrics(ctx, log, otlpEndpoint)
-		global.SetMeterProvider(provider)
+		exporterCfg, err := controllerUtils.GetOTLPExporterConfig(ctx, mgr.GetAPIReader(), &c.Manager)
+		if err != nil {
+			return nil, err
+		}
+
+		// the controller runs without exporting metrics rather than not at all
+		if _, _, provider, err = metrics.ConfigureMetrics(ctx, exporterCfg); err != nil {
+			log.Error(err, "error configuring metrics")
+		} else {
+			global.SetMeterProvider(provider)
+		}
 	}
 
 	rec := &Reconciler{
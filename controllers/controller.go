@@ -1,13 +1,18 @@
 package controllers
 
 import (
+	"context"
 	"errors"
 
 	"github.com/eraser-dev/eraser/api/unversioned/config"
 	"github.com/eraser-dev/eraser/controllers/configmap"
+	"github.com/eraser-dev/eraser/controllers/eraserconfig"
 	"github.com/eraser-dev/eraser/controllers/imagecollector"
 	"github.com/eraser-dev/eraser/controllers/imagejob"
 	"github.com/eraser-dev/eraser/controllers/imagelist"
+	"github.com/eraser-dev/eraser/controllers/util"
+	"github.com/eraser-dev/eraser/controllers/webhooks"
+	"github.com/eraser-dev/eraser/pkg/tracing"
 	"k8s.io/apimachinery/pkg/api/meta"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -23,11 +28,17 @@ var (
 		imagejob.Add,
 		imagecollector.Add,
 		configmap.Add,
+		eraserconfig.Add,
+		webhooks.Add,
 	}
 )
 
 func SetupWithManager(m manager.Manager, cfg *config.Manager) error {
 	controllerLog.Info("set up with manager")
+	if err := setupTracing(m, cfg); err != nil {
+		return err
+	}
+
 	for _, f := range controllerAddFuncs {
 		if err := f(m, cfg); err != nil {
 			var kindMatchErr *meta.NoKindMatchError
@@ -40,3 +51,32 @@ func SetupWithManager(m manager.Manager, cfg *config.Manager) error {
 	}
 	return nil
 }
+
+// setupTracing exports the manager's spans if tracing is enabled, and
+// flushes them when the manager stops.
+func setupTracing(m manager.Manager, cfg *config.Manager) error {
+	c, err := cfg.Read()
+	if err != nil {
+		return err
+	}
+
+	if !c.Manager.Tracing.Enabled || c.Manager.OTLPEndpoint == "" {
+		return nil
+	}
+
+	ctx := context.Background()
+	exporterCfg, err := util.GetOTLPExporterConfig(ctx, m.GetAPIReader(), &c.Manager)
+	if err != nil {
+		return err
+	}
+
+	shutdown, err := tracing.Configure(ctx, exporterCfg, c.Manager.Tracing.SampleRatio)
+	if err != nil {
+		return err
+	}
+
+	return m.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return shutdown(context.Background())
+	}))
+}
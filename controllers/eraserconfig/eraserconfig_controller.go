@@ -0,0 +1,154 @@
+// Package eraserconfig reconciles the EraserConfig resource, which takes
+// precedence over the manager's configuration file while it exists.
+package eraserconfig
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+	"github.com/eraser-dev/eraser/api/unversioned/config"
+	"github.com/eraser-dev/eraser/api/v1alpha3"
+	"github.com/eraser-dev/eraser/controllers/util"
+	"github.com/eraser-dev/eraser/pkg/configfile"
+)
+
+// Name is the name of the EraserConfig resource which the manager uses.
+// EraserConfigs with other names are rejected by the webhook.
+const Name = "eraser-config"
+
+var log = logf.Log.WithName("controller").WithValues("process", "eraserconfig-controller")
+
+// Reconciler loads the EraserConfig resource into the manager's
+// configuration, and reports the outcome in its status.
+type Reconciler struct {
+	client.Client
+	apiReader    client.Reader
+	eraserConfig *config.Manager
+	recorder     record.EventRecorder
+}
+
+func Add(mgr manager.Manager, cfg *config.Manager) error {
+	r := &Reconciler{
+		Client:       mgr.GetClient(),
+		apiReader:    mgr.GetAPIReader(),
+		eraserConfig: cfg,
+		recorder:     mgr.GetEventRecorderFor("eraserconfig-controller"),
+	}
+
+	// status updates do not change the generation, so they are not
+	// reconciled again
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("eraserconfig-controller").
+		For(&v1alpha3.EraserConfig{}, builder.WithPredicates(
+			predicate.GenerationChangedPredicate{},
+			predicate.NewPredicateFuncs(func(obj client.Object) bool { return obj.GetName() == Name }),
+		)).
+		Complete(r)
+}
+
+// Load returns the configuration in the EraserConfig resource named Name,
+// with defaults filled in, or nil if there is none.
+func Load(ctx context.Context, reader client.Reader) (*unversioned.EraserConfig, error) {
+	// the resource is read as unstructured so that fields which are set to
+	// their zero values are not mistaken for unset ones, which are defaulted
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(v1alpha3.GroupVersion.WithKind("EraserConfig"))
+	if err := reader.Get(ctx, types.NamespacedName{Name: Name}, u); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	b, err := u.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := configfile.Parse(b)
+	if err != nil {
+		return nil, err
+	}
+
+	if errs := config.Validate(cfg); len(errs) > 0 {
+		return nil, errs.ToAggregate()
+	}
+
+	// only the configuration itself is used
+	cfg.ObjectMeta = metav1.ObjectMeta{}
+	cfg.Status = unversioned.EraserConfigStatus{}
+	return cfg, nil
+}
+
+//+kubebuilder:rbac:groups=eraser.sh,resources=eraserconfigs,verbs=get;list;watch
+//+kubebuilder:rbac:groups=eraser.sh,resources=eraserconfigs/status,verbs=get;update;patch
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	eraserConfig := &v1alpha3.EraserConfig{}
+	if err := r.Get(ctx, req.NamespacedName, eraserConfig); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+
+		log.Info("EraserConfig was deleted, using the configuration file", "name", req.Name)
+		return ctrl.Result{}, r.eraserConfig.ClearOverride()
+	}
+
+	cfg, err := Load(ctx, r.apiReader)
+	switch {
+	case err != nil:
+		// the configuration which was used before stays in use
+		log.Error(err, "unable to load EraserConfig", "name", eraserConfig.Name, "generation", eraserConfig.Generation)
+		r.recorder.Eventf(eraserConfig, corev1.EventTypeWarning, util.ReasonConfigInvalid,
+			"Unable to load generation %d, the previous configuration stays in use: %v", eraserConfig.Generation, err)
+		eraserConfig.Status.LastLoadError = err.Error()
+	case cfg == nil:
+		// it was deleted since it was read from the cache
+		return ctrl.Result{}, r.eraserConfig.ClearOverride()
+	default:
+		if err := r.eraserConfig.Override(cfg); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		log.Info("loaded EraserConfig", "name", eraserConfig.Name, "generation", eraserConfig.Generation)
+		r.recorder.Eventf(eraserConfig, corev1.EventTypeNormal, util.ReasonConfigLoaded, "Loaded generation %d", eraserConfig.Generation)
+		eraserConfig.Status.LastLoadError = ""
+	}
+
+	effective, err := r.eraserConfig.Read()
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	status := &eraserConfig.Status
+	status.Effective = &v1alpha3.EffectiveConfig{}
+	if err := v1alpha3.Convert_unversioned_EffectiveConfig_To_v1alpha3_EffectiveConfig(
+		&unversioned.EffectiveConfig{Manager: effective.Manager, Components: effective.Components}, status.Effective, nil); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !effective.Components.Collector.Enabled {
+		// only the collector runs on a schedule
+		status.NextScheduledRun = nil
+	}
+
+	now := metav1.Now()
+	status.ObservedGeneration = eraserConfig.Generation
+	status.LastLoadTime = &now
+
+	return ctrl.Result{}, r.Status().Update(ctx, eraserConfig)
+}
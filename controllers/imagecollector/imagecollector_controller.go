@@ -22,13 +22,15 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
-	"path/filepath"
-	"strconv"
 	"syscall"
 	"time"
 
 	"go.opentelemetry.io/otel/metric/global"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
@@ -38,7 +40,8 @@ import (
 
 	"github.com/eraser-dev/eraser/api/unversioned/config"
 	eraserv1 "github.com/eraser-dev/eraser/api/v1"
-	eraserv1alpha1 "github.com/eraser-dev/eraser/api/v1alpha1"
+	"github.com/eraser-dev/eraser/api/v1alpha3"
+	"github.com/eraser-dev/eraser/controllers/eraserconfig"
 	"github.com/eraser-dev/eraser/controllers/util"
 
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -46,19 +49,17 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
-	"github.com/eraser-dev/eraser/pkg/logger"
 	"github.com/eraser-dev/eraser/pkg/metrics"
+	"github.com/eraser-dev/eraser/pkg/schedule"
+	"github.com/eraser-dev/eraser/pkg/tracing"
 	eraserUtils "github.com/eraser-dev/eraser/pkg/utils"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
-	"k8s.io/apimachinery/pkg/types"
 )
 
 const (
-	ownerLabelValue  = "imagecollector"
-	configVolumeName = "eraser-config"
+	ownerLabelValue = "imagecollector"
 )
 
 var (
@@ -85,6 +86,10 @@ type Reconciler struct {
 	client.Client
 	Scheme       *runtime.Scheme
 	eraserConfig *config.Manager
+	// nextRun is when the next collector ImageJob is due. Reconciles are
+	// never concurrent, so it needs no lock.
+	nextRun  time.Time
+	recorder record.EventRecorder
 }
 
 func Add(mgr manager.Manager, cfg *config.Manager) error {
@@ -119,14 +124,24 @@ func newReconciler(mgr manager.Manager, cfg *config.Manager) (*Reconciler, error
 		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 		defer cancel()
 
-		exporter, reader, provider = metrics.ConfigureMetrics(ctx, log, otlpEndpoint)
-		global.SetMeterProvider(provider)
+		exporterCfg, err := util.GetOTLPExporterConfig(ctx, mgr.GetAPIReader(), &c.Manager)
+		if err != nil {
+			return nil, err
+		}
+
+		// the controller runs without exporting metrics rather than not at all
+		if exporter, reader, provider, err = metrics.ConfigureMetrics(ctx, exporterCfg); err != nil {
+			log.Error(err, "error configuring metrics")
+		} else {
+			global.SetMeterProvider(provider)
+		}
 	}
 
 	rec := &Reconciler{
 		Client:       mgr.GetClient(),
 		Scheme:       mgr.GetScheme(),
 		eraserConfig: cfg,
+		recorder:     mgr.GetEventRecorderFor("imagecollector-controller"),
 	}
 
 	return rec, nil
@@ -162,6 +177,22 @@ func add(mgr manager.Manager, r *Reconciler) error {
 		return err
 	}
 
+	// a newly created EraserConfig resource needs the next run in its status
+	err = c.Watch(
+		&source.Kind{Type: &v1alpha3.EraserConfig{}},
+		&handler.EnqueueRequestForObject{}, predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool {
+				return e.Object.GetName() == eraserconfig.Name
+			},
+			UpdateFunc:  func(event.UpdateEvent) bool { return false },
+			DeleteFunc:  util.NeverOnDelete,
+			GenericFunc: util.NeverOnGeneric,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
 	ch := make(chan event.GenericEvent)
 	err = c.Watch(&source.Channel{
 		Source: ch,
@@ -175,16 +206,21 @@ func add(mgr manager.Manager, r *Reconciler) error {
 		return err
 	}
 
-	scheduleCfg := eraserConfig.Manager.Scheduling
-	delay := time.Duration(scheduleCfg.RepeatInterval)
-	if scheduleCfg.BeginImmediately {
-		delay = 0 * time.Second
+	sched, err := schedule.New(&eraserConfig.Manager.Scheduling)
+	if err != nil {
+		return err
 	}
 
-	log.V(1).Info("delay", "delay", delay)
+	r.nextRun, err = sched.First(time.Now(), eraserConfig.Manager.Scheduling.BeginImmediately)
+	if err != nil {
+		return err
+	}
 
-	// runs the provided function after the specified delay
-	_ = time.AfterFunc(delay, func() {
+	log.V(1).Info("first run", "time", r.nextRun)
+
+	// the first reconcile waits for the first run, after recording it in the
+	// EraserConfig status
+	go func() {
 		log.Info("Queueing first ImageCollector reconcile...")
 		ch <- event.GenericEvent{
 			Object: &eraserv1.ImageJob{
@@ -193,16 +229,21 @@ func add(mgr manager.Manager, r *Reconciler) error {
 				},
 			},
 		}
-	})
+	}()
 
 	return nil
 }
 
 //+kubebuilder:rbac:groups=eraser.sh,resources=imagelists,verbs=get;list;watch
-//+kubebuilder:rbac:groups="",namespace="system",resources=podtemplates,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=eraser.sh,resources=imagelists/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=eraser.sh,resources=imagepolicies,verbs=get;list;watch
+//+kubebuilder:rbac:groups=eraser.sh,resources=eraserconfigs,verbs=get;list;watch
+//+kubebuilder:rbac:groups=eraser.sh,resources=eraserconfigs/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",namespace="system",resources=pods,verbs=get;list;watch;update;create;delete
+//+kubebuilder:rbac:groups="",namespace="system",resources=secrets,verbs=get
+//+kubebuilder:rbac:groups="",namespace="system",resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -213,17 +254,28 @@ func add(mgr manager.Manager, r *Reconciler) error {
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.11.2/pkg/reconcile
-func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) {
 	log.Info("ImageCollector Reconcile")
 	defer log.Info("done reconcile")
 
+	ctx, span := tracing.Tracer().Start(ctx, "imagecollector.Reconcile")
+	defer func() { tracing.End(span, err) }()
+
+	if req.Name == eraserconfig.Name {
+		r.recordNextRun(ctx)
+		return ctrl.Result{}, nil
+	}
+
+	// ImageLists have ImageJobs of their own
 	imageJobList := &eraserv1.ImageJobList{}
-	if err := r.List(ctx, imageJobList); err != nil {
+	if err := r.List(ctx, imageJobList, client.MatchingLabelsSelector{Selector: ownerLabel}); err != nil {
 		log.Info("could not list imagejobs")
 		return ctrl.Result{}, err
 	}
 
 	if req.Name == "first-reconcile" {
+		r.recordNextRun(ctx)
+		r.recordNextRunEvent(ctx)
 		for idx := range imageJobList.Items {
 			if err := r.Delete(ctx, &imageJobList.Items[idx]); err != nil {
 				log.Info("error cleaning up previous imagejobs")
@@ -253,28 +305,13 @@ func (r *Reconciler) handleJobDeletion(ctx context.Context, job *eraserv1.ImageJ
 		return ctrl.Result{RequeueAfter: until}, nil
 	}
 
+	// the job's pods are owned by it, and deleted with it
 	log.Info("Deleting imagejob", "job", job.Name)
 	err := r.Delete(ctx, job)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	template := corev1.PodTemplate{}
-	if err := r.Get(ctx,
-		types.NamespacedName{
-			Namespace: eraserUtils.GetNamespace(),
-			Name:      job.GetName(),
-		},
-		&template,
-	); err != nil {
-		return ctrl.Result{}, err
-	}
-
-	log.Info("Deleting pod template", "template", template.Name)
-	if err := r.Delete(ctx, &template); err != nil {
-		return ctrl.Result{}, err
-	}
-
 	log.Info("end job deletion")
 	return ctrl.Result{}, nil
 }
@@ -285,259 +322,73 @@ func (r *Reconciler) createImageJob(ctx context.Context) (ctrl.Result, error) {
 		return ctrl.Result{}, err
 	}
 
-	mgrCfg := eraserConfig.Manager
-	compCfg := eraserConfig.Components
-
-	scanCfg := compCfg.Scanner
-	collectorCfg := compCfg.Collector
-	eraserCfg := compCfg.Remover
-
-	scanDisabled := !scanCfg.Enabled
-	startTime = time.Now()
-
-	removerImg := *util.RemoverImage
-	if removerImg == "" {
-		iCfg := eraserCfg.Image
-		removerImg = fmt.Sprintf("%s:%s", iCfg.Repo, iCfg.Tag)
+	if wait := time.Until(r.nextRun); wait > 0 {
+		return ctrl.Result{RequeueAfter: wait}, nil
 	}
 
-	log.V(1).Info("removerImg", "removerImg", removerImg)
-
-	iCfg := collectorCfg.Image
-	collectorImg := fmt.Sprintf("%s:%s", iCfg.Repo, iCfg.Tag)
-
-	profileConfig := eraserConfig.Manager.Profile
-	profileArgs := []string{
-		"--enable-pprof=" + strconv.FormatBool(profileConfig.Enabled),
-		fmt.Sprintf("--pprof-port=%d", profileConfig.Port),
+	sched, err := schedule.New(&eraserConfig.Manager.Scheduling)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
 
-	collArgs := []string{"--scan-disabled=" + strconv.FormatBool(scanDisabled)}
-	collArgs = append(collArgs, profileArgs...)
-
-	removerArgs := []string{"--log-level=" + logger.GetLevel()}
-	removerArgs = append(removerArgs, profileArgs...)
-
-	pullSecrets := []corev1.LocalObjectReference{}
-	for _, secret := range eraserConfig.Manager.PullSecrets {
-		pullSecrets = append(pullSecrets, corev1.LocalObjectReference{Name: secret})
+	// the maintenance windows may have changed since the run was scheduled
+	now := time.Now()
+	next, err := sched.First(now, true)
+	if err != nil {
+		return ctrl.Result{}, err
 	}
-
-	jobTemplate := corev1.PodTemplateSpec{
-		Spec: corev1.PodSpec{
-			Volumes: []corev1.Volume{
-				{
-					// EmptyDir default
-					Name: "shared-data",
-				},
-				{
-					Name: configVolumeName,
-					VolumeSource: corev1.VolumeSource{
-						ConfigMap: &corev1.ConfigMapVolumeSource{
-							LocalObjectReference: corev1.LocalObjectReference{
-								Name: util.EraserConfigmapName,
-							},
-						},
-					},
-				},
-			},
-			ImagePullSecrets:  pullSecrets,
-			RestartPolicy:     corev1.RestartPolicyNever,
-			PriorityClassName: eraserConfig.Manager.PriorityClassName,
-			Containers: []corev1.Container{
-				{
-					Name:            "collector",
-					Image:           collectorImg,
-					ImagePullPolicy: corev1.PullIfNotPresent,
-					Args:            collArgs,
-					VolumeMounts: []corev1.VolumeMount{
-						{MountPath: "/run/eraser.sh/shared-data", Name: "shared-data"},
-					},
-					Resources: corev1.ResourceRequirements{
-						Requests: corev1.ResourceList{
-							"cpu":    collectorCfg.Request.CPU,
-							"memory": collectorCfg.Request.Mem,
-						},
-						Limits: corev1.ResourceList{
-							"memory": collectorCfg.Limit.Mem,
-						},
-					},
-				},
-				{
-					Name:            "remover",
-					Image:           removerImg,
-					ImagePullPolicy: corev1.PullIfNotPresent,
-					Args:            removerArgs,
-					VolumeMounts: []corev1.VolumeMount{
-						{MountPath: "/run/eraser.sh/shared-data", Name: "shared-data"},
-					},
-					Resources: corev1.ResourceRequirements{
-						Requests: corev1.ResourceList{
-							"cpu":    eraserCfg.Request.CPU,
-							"memory": eraserCfg.Request.Mem,
-						},
-						Limits: corev1.ResourceList{
-							"memory": eraserCfg.Limit.Mem,
-						},
-					},
-					SecurityContext: eraserUtils.SharedSecurityContext,
-					Env: []corev1.EnvVar{
-						{
-							Name:  "OTEL_EXPORTER_OTLP_ENDPOINT",
-							Value: mgrCfg.OTLPEndpoint,
-						},
-						{
-							Name:  "OTEL_SERVICE_NAME",
-							Value: "remover",
-						},
-					},
-				},
-			},
-			ServiceAccountName: "eraser-imagejob-pods",
-		},
+	if next.After(now) {
+		log.Info("Postponing collector ImageJob for the maintenance windows", "next", next)
+		r.setNextRun(ctx, next)
+		return ctrl.Result{RequeueAfter: time.Until(next)}, nil
 	}
 
-	job := &eraserv1alpha1.ImageJob{
+	startTime = now
+
+	// the imagejob controller renders the job's pods from its spec
+	job := &eraserv1.ImageJob{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: "imagejob-",
 			Labels: map[string]string{
 				util.ImageJobOwnerLabelKey: ownerLabelValue,
 			},
 		},
+		Spec: eraserv1.ImageJobSpec{
+			Mode:           eraserv1.ModeCollect,
+			ScannerEnabled: eraserConfig.Components.Scanner.Enabled,
+		},
 	}
 
-	if !scanDisabled {
-		iCfg := scanCfg.Image
-		scannerImg := fmt.Sprintf("%s:%s", iCfg.Repo, iCfg.Tag)
-
-		cfgDirname := "/config"
-		cfgFilename := filepath.Join(cfgDirname, "controller_manager_config.yaml")
-		scannerArgs := []string{fmt.Sprintf("--config=%s", cfgFilename)}
-		scannerArgs = append(scannerArgs, profileArgs...)
-
-		scannerContainer := corev1.Container{
-			Name:  "trivy-scanner",
-			Image: scannerImg,
-			Args:  scannerArgs,
-			VolumeMounts: []corev1.VolumeMount{
-				{MountPath: "/run/eraser.sh/shared-data", Name: "shared-data"},
-				{MountPath: cfgDirname, Name: configVolumeName},
-			},
-			Resources: corev1.ResourceRequirements{
-				Requests: corev1.ResourceList{
-					"memory": scanCfg.Request.Mem,
-					"cpu":    scanCfg.Request.CPU,
-				},
-				Limits: corev1.ResourceList{
-					"memory": scanCfg.Limit.Mem,
-				},
-			},
-			// env vars for exporting metrics
-			Env: []corev1.EnvVar{
-				{
-					Name:  "OTEL_EXPORTER_OTLP_ENDPOINT",
-					Value: mgrCfg.OTLPEndpoint,
-				},
-				{
-					Name:  "OTEL_SERVICE_NAME",
-					Value: "trivy-scanner",
-				},
-				{
-					Name:  "ERASER_RUNTIME_NAME",
-					Value: string(mgrCfg.Runtime.Name),
-				},
-			},
-		}
-
-		log.Info("extra mount for scanner starts")
-		scannerVolumes := compCfg.Scanner.Volumes
-		if len(scannerVolumes) != 0 {
-			jobTemplate.Spec.Volumes = append(jobTemplate.Spec.Volumes, scannerVolumes...)
-			scannerVolumeMounts := []corev1.VolumeMount{}
-			for idx := range scannerVolumes {
-				volume := scannerVolumes[idx]
-				if volume.HostPath == nil {
-					log.Error(fmt.Errorf("volume hostPath is nil"), "invalid volume", "volumeName", volume.Name)
-					continue
-				}
-				scannerVolumeMounts = append(scannerVolumeMounts, corev1.VolumeMount{
-					Name:      volume.Name,
-					MountPath: volume.HostPath.Path,
-					ReadOnly:  true,
-				})
-			}
-			scannerContainer.VolumeMounts = append(scannerContainer.VolumeMounts, scannerVolumeMounts...)
-		}
-
-		jobTemplate.Spec.Containers = append(jobTemplate.Spec.Containers, scannerContainer)
-	}
-
-	configmapList := &corev1.ConfigMapList{}
-	if err := r.List(ctx, configmapList, client.InNamespace(eraserUtils.GetNamespace())); err != nil {
-		log.Info("Could not get list of configmaps")
-		return reconcile.Result{}, err
-	}
-
-	exclusionMount, exclusionVolume, err := util.GetExclusionVolume(configmapList)
-	if err != nil {
-		log.Info("Could not get exclusion mounts and volumes")
-		return reconcile.Result{}, err
-	}
-
-	for i := range jobTemplate.Spec.Containers {
-		jobTemplate.Spec.Containers[i].VolumeMounts = append(jobTemplate.Spec.Containers[i].VolumeMounts, exclusionMount...)
+	// the imagejob controller continues the trace from here
+	if traceparent := tracing.Traceparent(ctx); traceparent != "" {
+		job.Annotations = map[string]string{tracing.TraceparentAnnotation: traceparent}
 	}
 
-	jobTemplate.Spec.Volumes = append(jobTemplate.Spec.Volumes, exclusionVolume...)
-
 	err = r.Create(ctx, job)
 	if err != nil {
 		log.Info("Could not create collector ImageJob")
 		return reconcile.Result{}, err
 	}
 
-	// get manager pod with label control-plane=controller-manager
-	podList := corev1.PodList{}
-	if err := r.List(ctx, &podList, client.InNamespace(eraserUtils.GetNamespace()), client.MatchingLabels{"control-plane": "controller-manager"}); err != nil {
-		log.Info("Unable to list controller-manager pod")
-	}
-	if len(podList.Items) != 1 {
-		log.Info("Incorrect number of controller-manager pods", "number of pods", len(podList.Items))
-	}
-	managerPod := &podList.Items[0]
-
-	namespace := eraserUtils.GetNamespace()
-	template := corev1.PodTemplate{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      job.GetName(),
-			Namespace: namespace,
-			OwnerReferences: []metav1.OwnerReference{
-				*metav1.NewControllerRef(managerPod, managerPod.GroupVersionKind()),
-			},
-		},
-		Template: jobTemplate,
-	}
+	log.Info("Successfully created collector ImageJob", "job", job.Name)
 
-	err = r.Create(ctx, &template)
-	if err != nil {
-		log.Error(err, "Could not create collector PodTemplate")
-		return reconcile.Result{}, err
+	util.InitJobConditions(job, "Created by the collector schedule")
+	if err := r.Status().Update(ctx, job); err != nil {
+		// the imagejob controller has already set the job's conditions
+		log.V(1).Info("could not set initial conditions of imagejob", "job", job.Name, "error", err.Error())
 	}
 
-	log.Info("Successfully created collector ImageJob", "job", job.Name)
 	return reconcile.Result{}, nil
 }
 
 func (r *Reconciler) handleCompletedImageJob(ctx context.Context, childJob *eraserv1.ImageJob) (ctrl.Result, error) {
 	var err error
-	var timeRemaining time.Duration
 	eraserConfig, err := r.eraserConfig.Read()
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
 	otlpEndpoint := eraserConfig.Manager.OTLPEndpoint
-	repeatInterval := time.Duration(eraserConfig.Manager.Scheduling.RepeatInterval)
 
 	cleanupCfg := eraserConfig.Manager.ImageJob.Cleanup
 	successDelay := time.Duration(cleanupCfg.DelayOnSuccess)
@@ -562,7 +413,6 @@ func (r *Reconciler) handleCompletedImageJob(ctx context.Context, childJob *eras
 			metrics.ExportMetrics(log, exporter, reader)
 		}
 
-		timeRemaining = repeatInterval - successDelay
 		if res, err := r.handleJobDeletion(ctx, childJob); err != nil || res.RequeueAfter > 0 {
 			return res, err
 		}
@@ -584,18 +434,75 @@ func (r *Reconciler) handleCompletedImageJob(ctx context.Context, childJob *eras
 			metrics.ExportMetrics(log, exporter, reader)
 		}
 
-		timeRemaining = repeatInterval - errDelay
 		if res, err := r.handleJobDeletion(ctx, childJob); err != nil || res.RequeueAfter > 0 {
 			return res, err
 		}
 	default:
 		err = errors.New("should not reach this point for imagejob")
 		log.Error(err, "imagejob not in completed or failed phase", "imagejob", childJob)
+		return ctrl.Result{Requeue: true}, err
 	}
 
-	if timeRemaining <= 0 {
-		return ctrl.Result{Requeue: true}, err
+	// the next run is timed from the start of this one, so that neither the
+	// job nor its cleanup delays it
+	sched, err := schedule.New(&eraserConfig.Manager.Scheduling)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	next, err := sched.Next(childJob.CreationTimestamp.Time, time.Now())
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	r.setNextRun(ctx, next)
+	if wait := time.Until(next); wait > 0 {
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	return ctrl.Result{Requeue: true}, nil
+}
+
+func (r *Reconciler) setNextRun(ctx context.Context, next time.Time) {
+	log.Info("Scheduled next collector ImageJob", "time", next)
+	r.nextRun = next
+	r.recordNextRun(ctx)
+	r.recordNextRunEvent(ctx)
+}
+
+// recordNextRun shows the next run in the status of the EraserConfig
+// resource, if there is one.
+func (r *Reconciler) recordNextRun(ctx context.Context) {
+	eraserConfig := &v1alpha3.EraserConfig{}
+	if err := r.Get(ctx, types.NamespacedName{Name: eraserconfig.Name}, eraserConfig); err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Error(err, "unable to get EraserConfig to record the next run")
+		}
+		return
+	}
+
+	next := metav1.NewTime(r.nextRun)
+	if eraserConfig.Status.NextScheduledRun.Equal(&next) {
+		return
+	}
+
+	patch := client.MergeFrom(eraserConfig.DeepCopy())
+	eraserConfig.Status.NextScheduledRun = &next
+	if err := r.Status().Patch(ctx, eraserConfig, patch); err != nil {
+		log.Error(err, "unable to record the next run in the EraserConfig status")
+	}
+}
+
+// recordNextRunEvent records the next run as an Event on the manager's
+// ConfigMap, which every install has, unlike the EraserConfig resource.
+func (r *Reconciler) recordNextRunEvent(ctx context.Context) {
+	configMap := &corev1.ConfigMap{}
+	key := types.NamespacedName{Namespace: eraserUtils.GetNamespace(), Name: util.EraserConfigmapName}
+	if err := r.Get(ctx, key, configMap); err != nil {
+		log.Error(err, "unable to get the manager's ConfigMap to record the next run")
+		return
 	}
 
-	return ctrl.Result{RequeueAfter: timeRemaining}, err
+	r.recorder.Eventf(configMap, corev1.EventTypeNormal, util.ReasonNextRunScheduled,
+		"Next collector ImageJob at %s", r.nextRun.UTC().Format(time.RFC3339))
 }
@@ -0,0 +1,51 @@
+package imagecollector
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/eraser-dev/eraser/api/unversioned/config"
+	"github.com/eraser-dev/eraser/api/v1alpha3"
+	"github.com/eraser-dev/eraser/controllers/util"
+	eraserUtils "github.com/eraser-dev/eraser/pkg/utils"
+)
+
+func TestSetNextRunWithoutEraserConfig(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1alpha3.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: eraserUtils.GetNamespace(), Name: util.EraserConfigmapName}}
+	recorder := record.NewFakeRecorder(10)
+	r := &Reconciler{
+		Client:       fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build(),
+		Scheme:       scheme,
+		eraserConfig: config.NewManager(config.Default()),
+		recorder:     recorder,
+	}
+
+	next := time.Date(2023, time.June, 2, 2, 0, 0, 0, time.UTC)
+	r.setNextRun(context.Background(), next)
+
+	select {
+	case e := <-recorder.Events:
+		if !strings.Contains(e, util.ReasonNextRunScheduled) || !strings.Contains(e, "2023-06-02T02:00:00Z") {
+			t.Errorf("expected an event with the next run, got %q", e)
+		}
+	default:
+		t.Error("expected an event with the next run")
+	}
+}
@@ -15,17 +15,27 @@ package imagejob
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/exp/slices"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/kubernetes/pkg/scheduler/framework"
 	"k8s.io/kubernetes/pkg/scheduler/framework/plugins/noderesources"
 
@@ -38,6 +48,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -47,6 +58,8 @@ import (
 	"github.com/eraser-dev/eraser/api/unversioned/config"
 	eraserv1 "github.com/eraser-dev/eraser/api/v1"
 	controllerUtils "github.com/eraser-dev/eraser/controllers/util"
+	"github.com/eraser-dev/eraser/pkg/metrics"
+	"github.com/eraser-dev/eraser/pkg/tracing"
 	eraserUtils "github.com/eraser-dev/eraser/pkg/utils"
 )
 
@@ -57,8 +70,9 @@ const (
 	collectorJobType     = "collector"
 	manualJobType        = "manual"
 	removerContainer     = "remover"
-	managerLabelValue    = "controller-manager"
-	managerLabelKey      = "control-plane"
+
+	// queueInterval is how often a queued job checks whether it can start.
+	queueInterval = 15 * time.Second
 )
 
 var log = logf.Log.WithName("controller").WithValues("process", "imagejob-controller")
@@ -70,15 +84,31 @@ var defaultTolerations = []corev1.Toleration{
 }
 
 func Add(mgr manager.Manager, cfg *config.Manager) error {
-	return add(mgr, newReconciler(mgr, cfg))
+	// job metrics are served on the manager's /metrics endpoint, alongside
+	// the controller-runtime metrics
+	provider, err := metrics.ConfigurePrometheus(ctrlmetrics.Registry)
+	if err != nil {
+		return err
+	}
+
+	scans, err := metrics.NewScanRecorder(provider)
+	if err != nil {
+		return err
+	}
+
+	return add(mgr, newReconciler(mgr, cfg, provider, scans))
 }
 
 // newReconciler returns a new reconcile.Reconciler.
-func newReconciler(mgr manager.Manager, cfg *config.Manager) reconcile.Reconciler {
+func newReconciler(mgr manager.Manager, cfg *config.Manager, provider metric.MeterProvider, scans *metrics.ScanRecorder) reconcile.Reconciler {
 	rec := &Reconciler{
 		Client:       mgr.GetClient(),
 		scheme:       mgr.GetScheme(),
 		eraserConfig: cfg,
+		metrics:      provider,
+		scans:        scans,
+		repositories: metrics.NewRepositoryLimiter(),
+		recorder:     mgr.GetEventRecorderFor("imagejob-controller"),
 	}
 
 	return rec
@@ -89,6 +119,13 @@ type Reconciler struct {
 	client.Client
 	scheme       *runtime.Scheme
 	eraserConfig *config.Manager
+	metrics      metric.MeterProvider
+	scans        *metrics.ScanRecorder
+
+	// repositories bounds the repository labels recorded over the lifetime
+	// of the manager, across every node.
+	repositories *metrics.RepositoryLimiter
+	recorder     record.EventRecorder
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler.
@@ -124,7 +161,7 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 			Type: &corev1.Pod{},
 		},
 		&handler.EnqueueRequestForOwner{
-			OwnerType:    &corev1.PodTemplate{},
+			OwnerType:    &eraserv1.ImageJob{},
 			IsController: true,
 		},
 		predicate.Funcs{
@@ -143,34 +180,6 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 		return err
 	}
 
-	// watch for changes to imagejob podTemplate (owned by controller manager pod)
-	err = c.Watch(
-		&source.Kind{
-			Type: &corev1.PodTemplate{},
-		},
-		&handler.EnqueueRequestForOwner{
-			OwnerType:    &corev1.Pod{},
-			IsController: true,
-		},
-		predicate.Funcs{
-			CreateFunc: func(e event.CreateEvent) bool {
-				ownerLabels, ok := e.Object.GetLabels()[managerLabelKey]
-				return ok && ownerLabels == managerLabelValue
-			},
-			UpdateFunc: func(e event.UpdateEvent) bool {
-				ownerLabels, ok := e.ObjectNew.GetLabels()[managerLabelKey]
-				return ok && ownerLabels == managerLabelValue
-			},
-			DeleteFunc: func(e event.DeleteEvent) bool {
-				ownerLabels, ok := e.Object.GetLabels()[managerLabelKey]
-				return ok && ownerLabels == managerLabelValue
-			},
-		},
-	)
-	if err != nil {
-		return err
-	}
-
 	return nil
 }
 
@@ -189,9 +198,9 @@ func checkNodeFitness(pod *corev1.Pod, node *corev1.Node) bool {
 }
 
 //+kubebuilder:rbac:groups=eraser.sh,resources=imagejobs,verbs=get;list;watch;create;delete
-//+kubebuilder:rbac:groups="",namespace="system",resources=podtemplates,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=eraser.sh,resources=imagejobs/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups="",namespace="system",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -214,13 +223,33 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 
 	switch imageJob.Status.Phase {
 	case "":
+		blocking, err := r.blockingJob(ctx, imageJob)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("reconcile new: %w", err)
+		}
+		if blocking != nil {
+			log.Info("imagejob is queued", "job", imageJob.Name, "blockingJob", blocking.Name)
+			message := fmt.Sprintf("Waiting for ImageJob %s, which runs on some of the same nodes", blocking.Name)
+			// the job is requeued until it can start, so the event is only
+			// recorded when it is first queued, or behind another job
+			if r.setCondition(imageJob, eraserv1.ConditionScheduled, metav1.ConditionFalse, controllerUtils.ReasonJobQueued, message) {
+				if err := r.updateJobStatus(ctx, imageJob); err != nil {
+					return ctrl.Result{}, err
+				}
+				r.recorder.Event(imageJob, corev1.EventTypeNormal, controllerUtils.ReasonJobQueued, message)
+			}
+			return ctrl.Result{RequeueAfter: queueInterval}, nil
+		}
+
 		if err := r.handleNewJob(ctx, imageJob); err != nil {
 			return ctrl.Result{}, fmt.Errorf("reconcile new: %w", err)
 		}
 	case eraserv1.PhaseRunning:
-		if err := r.handleRunningJob(ctx, imageJob); err != nil {
+		res, err := r.handleRunningJob(ctx, imageJob)
+		if err != nil {
 			return ctrl.Result{}, fmt.Errorf("reconcile running: %w", err)
 		}
+		return res, nil
 	case eraserv1.PhaseCompleted, eraserv1.PhaseFailed:
 		break // this is handled by the Owning controller
 	default:
@@ -230,13 +259,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	return ctrl.Result{}, nil
 }
 
-func podListOptions(jobTemplate *corev1.PodTemplate) client.ListOptions {
-	var set map[string]string
-
-	if jobTemplate.Template.Spec.Containers[0].Name == removerContainer {
+func podListOptions(imageJob *eraserv1.ImageJob) client.ListOptions {
+	set := map[string]string{imageJobTypeLabelKey: collectorJobType}
+	if imageJob.Spec.Mode == eraserv1.ModeRemove {
 		set = map[string]string{imageJobTypeLabelKey: manualJobType}
-	} else {
-		set = map[string]string{imageJobTypeLabelKey: collectorJobType}
 	}
 
 	return client.ListOptions{
@@ -245,43 +271,69 @@ func podListOptions(jobTemplate *corev1.PodTemplate) client.ListOptions {
 	}
 }
 
-func (r *Reconciler) handleRunningJob(ctx context.Context, imageJob *eraserv1.ImageJob) error {
-	// get eraser pods
+// jobPods returns the pods of imageJob.
+func (r *Reconciler) jobPods(ctx context.Context, imageJob *eraserv1.ImageJob) ([]corev1.Pod, error) {
 	podList := &corev1.PodList{}
+	listOpts := podListOptions(imageJob)
+	if err := r.List(ctx, podList, &listOpts); err != nil {
+		return nil, err
+	}
 
-	template := corev1.PodTemplate{}
-	namespace := eraserUtils.GetNamespace()
-
-	err := r.Get(ctx, types.NamespacedName{
-		Name:      imageJob.GetName(),
-		Namespace: namespace,
-	}, &template)
-	if err != nil {
-		imageJob.Status = eraserv1.ImageJobStatus{
-			Phase:       eraserv1.PhaseFailed,
-			DeleteAfter: controllerUtils.After(time.Now(), 1),
+	var pods []corev1.Pod
+	for i := range podList.Items {
+		if metav1.IsControlledBy(&podList.Items[i], imageJob) {
+			pods = append(pods, podList.Items[i])
 		}
-		return r.updateJobStatus(ctx, imageJob)
 	}
 
-	listOpts := podListOptions(&template)
-	err = r.List(ctx, podList, &listOpts)
+	return pods, nil
+}
+
+func (r *Reconciler) handleRunningJob(ctx context.Context, imageJob *eraserv1.ImageJob) (ctrl.Result, error) {
+	// get eraser pods
+	pods, err := r.jobPods(ctx, imageJob)
 	if err != nil {
-		return err
+		return ctrl.Result{}, err
+	}
+
+	if len(imageJob.Status.PendingNodes) > 0 {
+		started, pause, err := r.startPods(ctx, imageJob, pods)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		pods = append(pods, started...)
+		r.setPodConditions(imageJob, pods)
+		setNodeStatuses(imageJob, pods)
+		if err := r.updateJobStatus(ctx, imageJob); err != nil {
+			return ctrl.Result{}, err
+		}
+
+		// the job is requeued as its pods finish, or once the pause
+		// between batches is over
+		if len(imageJob.Status.PendingNodes) > 0 {
+			return ctrl.Result{RequeueAfter: pause}, nil
+		}
 	}
 
 	failed := 0
 	success := 0
 	skipped := imageJob.Status.Skipped
 
-	if !podsComplete(podList.Items) {
-		return nil
+	if !podsComplete(pods) {
+		// nodes are updated as their pods finish, so that stuck or failing
+		// nodes show up while the job runs
+		conditionsChanged := r.setPodConditions(imageJob, pods)
+		nodesChanged := setNodeStatuses(imageJob, pods)
+		if conditionsChanged || nodesChanged {
+			return ctrl.Result{}, r.updateJobStatus(ctx, imageJob)
+		}
+		return ctrl.Result{}, nil
 	}
 
 	// if all pods are complete, job is complete
 	// get status of pods
-	for i := range podList.Items {
-		if podList.Items[i].Status.Phase == corev1.PodSucceeded {
+	for i := range pods {
+		if pods[i].Status.Phase == corev1.PodSucceeded {
 			success++
 		} else {
 			failed++
@@ -289,160 +341,541 @@ func (r *Reconciler) handleRunningJob(ctx context.Context, imageJob *eraserv1.Im
 	}
 
 	imageJob.Status = eraserv1.ImageJobStatus{
-		Desired:   imageJob.Status.Desired,
-		Succeeded: success,
-		Skipped:   skipped,
-		Failed:    failed,
-		Phase:     eraserv1.PhaseCompleted,
+		Desired:    imageJob.Status.Desired,
+		Succeeded:  success,
+		Skipped:    skipped,
+		Failed:     failed,
+		Stopped:    imageJob.Status.Stopped,
+		Phase:      eraserv1.PhaseCompleted,
+		Conditions: imageJob.Status.Conditions,
 	}
+	setNodeStatuses(imageJob, pods)
 
 	successAndSkipped := success + skipped
 
 	eraserConfig, err := r.eraserConfig.Read()
 	if err != nil {
-		return err
+		return ctrl.Result{}, err
 	}
 
 	managerConfig := eraserConfig.Manager
 	successRatio := managerConfig.ImageJob.SuccessRatio
 
-	if float64(successAndSkipped/imageJob.Status.Desired) < successRatio {
+	// a job without nodes has nothing left to do
+	actualRatio := 1.0
+	if imageJob.Status.Desired > 0 {
+		actualRatio = float64(successAndSkipped) / float64(imageJob.Status.Desired)
+	}
+
+	if actualRatio < successRatio {
 		log.Info(
 			"Marking job as failed",
 			"success ratio", successRatio,
-			"actual ratio", success/imageJob.Status.Desired,
+			"actual ratio", actualRatio,
 		)
 		imageJob.Status.Phase = eraserv1.PhaseFailed
 	}
 
-	return r.updateJobStatus(ctx, imageJob)
+	r.setPodConditions(imageJob, pods)
+	ratioMessage := fmt.Sprintf("%d of %d pods succeeded and %d nodes were skipped, and the success ratio is %v", success, imageJob.Status.Desired, skipped, successRatio)
+	ratioMet := imageJob.Status.Phase == eraserv1.PhaseCompleted
+	ratioReason := controllerUtils.ReasonSuccessRatioMet
+	jobReason := controllerUtils.ReasonJobCompleted
+	if !ratioMet {
+		ratioReason = controllerUtils.ReasonSuccessRatioNotMet
+		jobReason = controllerUtils.ReasonJobFailed
+	}
+
+	// nodes left out by a stopped rollout count towards Desired, so the
+	// stop is given as the reason rather than only the ratio
+	stoppedMessage := ""
+	succeededReason := jobReason
+	if stopped := imageJob.Status.Stopped; stopped > 0 {
+		stoppedMessage = fmt.Sprintf("The rollout was stopped after too many pods failed, and %d nodes were left out. ", stopped)
+		ratioMessage = stoppedMessage + ratioMessage
+		if !ratioMet {
+			ratioReason = controllerUtils.ReasonRolloutStopped
+			succeededReason = controllerUtils.ReasonRolloutStopped
+		}
+	}
+
+	r.setCondition(imageJob, eraserv1.ConditionSuccessRatioNotMet, controllerUtils.ConditionStatus(!ratioMet), ratioReason, ratioMessage)
+	r.setCondition(imageJob, eraserv1.ConditionSucceeded, controllerUtils.ConditionStatus(ratioMet), succeededReason, ratioMessage)
+	r.setCondition(imageJob, eraserv1.ConditionProgressing, metav1.ConditionFalse, jobReason, "All pods finished")
+
+	if err := r.updateJobStatus(ctx, imageJob); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if imageJob.Status.Phase == eraserv1.PhaseFailed {
+		r.recorder.Eventf(imageJob, corev1.EventTypeWarning, controllerUtils.ReasonJobFailed,
+			"%s%d of %d pods succeeded and %d nodes were skipped, below the success ratio of %v", stoppedMessage, success, imageJob.Status.Desired, skipped, successRatio)
+	} else {
+		r.recorder.Eventf(imageJob, corev1.EventTypeNormal, controllerUtils.ReasonJobCompleted,
+			"%s%d of %d pods succeeded and %d nodes were skipped", stoppedMessage, success, imageJob.Status.Desired, skipped)
+	}
+
+	r.recordNodeEvents(ctx, imageJob, pods)
+	r.recordMetrics(ctx, imageJob, pods)
+	return ctrl.Result{}, nil
 }
 
-func (r *Reconciler) handleNewJob(ctx context.Context, imageJob *eraserv1.ImageJob) error {
-	nodes := &corev1.NodeList{}
-	err := r.List(ctx, nodes)
+// recordNodeEvents records the outcome of each of a finished job's pods on
+// its node, so that removals show up alongside the node's other events.
+func (r *Reconciler) recordNodeEvents(ctx context.Context, imageJob *eraserv1.ImageJob, pods []corev1.Pod) {
+	for i := range pods {
+		pod := &pods[i]
+
+		node := &corev1.Node{}
+		if err := r.Get(ctx, types.NamespacedName{Name: pod.Spec.NodeName}, node); err != nil {
+			log.Error(err, "unable to get node to record events on", "node", pod.Spec.NodeName)
+			continue
+		}
+
+		if pod.Status.Phase != corev1.PodSucceeded {
+			r.recorder.Eventf(node, corev1.EventTypeWarning, controllerUtils.ReasonJobPodFailed, "Pod %s of ImageJob %s failed", pod.Name, imageJob.Name)
+		}
+
+		report := metrics.PodReport(pod)
+		if report.ImagesRemoved > 0 {
+			r.recorder.Eventf(node, corev1.EventTypeNormal, controllerUtils.ReasonImagesRemoved, "Removed %d images in ImageJob %s", report.ImagesRemoved, imageJob.Name)
+		}
+
+		var failed int64
+		for _, removal := range report.Removals {
+			if removal.Outcome == metrics.OutcomeError {
+				failed += removal.Count
+			}
+		}
+		if failed > 0 {
+			r.recorder.Eventf(node, corev1.EventTypeWarning, controllerUtils.ReasonImageRemovalFailed, "Failed to remove %d images in ImageJob %s", failed, imageJob.Name)
+		}
+	}
+}
+
+// recordMetrics records a finished job, and the reports written by its pods'
+// containers, so that they can be scraped from the manager.
+func (r *Reconciler) recordMetrics(ctx context.Context, imageJob *eraserv1.ImageJob, pods []corev1.Pod) {
+	if r.metrics == nil {
+		return
+	}
+
+	duration := time.Since(imageJob.CreationTimestamp.Time).Seconds()
+	if err := metrics.RecordMetricsController(ctx, r.metrics, duration, int64(imageJob.Status.Succeeded), int64(imageJob.Status.Failed)); err != nil {
+		log.Error(err, "error recording job metrics", "imagejob", imageJob.Name)
+	}
+
+	eraserConfig, err := r.eraserConfig.Read()
+	if err != nil {
+		log.Error(err, "unable to read eraser config")
+		return
+	}
+	maxRepositories := eraserConfig.Manager.Metrics.MaxRepositories
+
+	for i := range pods {
+		pod := &pods[i]
+		report := metrics.PodReport(pod)
+
+		if err := metrics.RecordMetricsRemoverNode(ctx, r.metrics, pod.Spec.NodeName, report.ImagesRemoved); err != nil {
+			log.Error(err, "error recording remover metrics", "pod", pod.Name)
+		}
+
+		if err := metrics.RecordMetricsScannerNode(ctx, r.metrics, pod.Spec.NodeName, int(report.VulnerableImages)); err != nil {
+			log.Error(err, "error recording scanner metrics", "pod", pod.Name)
+		}
+
+		for j := range report.Removals {
+			report.Removals[j].Repository = r.repositories.Limit(report.Removals[j].Repository, maxRepositories)
+		}
+		if err := metrics.RecordMetricsRemovals(ctx, r.metrics, pod.Spec.NodeName, report.Source, report.Removals); err != nil {
+			log.Error(err, "error recording removal metrics", "pod", pod.Name)
+		}
+
+		if report.Scan != nil && r.scans != nil {
+			if err := r.scans.Record(ctx, pod.Spec.NodeName, report.Scan); err != nil {
+				log.Error(err, "error recording scan metrics", "pod", pod.Name)
+			}
+		}
+	}
+}
+
+func (r *Reconciler) handleNewJob(ctx context.Context, imageJob *eraserv1.ImageJob) (err error) {
+	ctx = tracing.WithTraceparent(ctx, imageJob.Annotations[tracing.TraceparentAnnotation])
+	ctx, span := tracing.Tracer().Start(ctx, "imagejob.handleNewJob", trace.WithAttributes(attribute.String("imagejob", imageJob.Name)))
+	defer func() { tracing.End(span, err) }()
+
+	eraserConfig, err := r.eraserConfig.Read()
 	if err != nil {
 		return err
 	}
 
-	template := corev1.PodTemplate{}
-	err = r.Get(ctx,
-		types.NamespacedName{
-			Namespace: eraserUtils.GetNamespace(),
-			Name:      imageJob.GetName(),
-		},
-		&template,
-	)
+	if errs := ValidateSpec(&imageJob.Spec, &eraserConfig.Manager.ImageJob); len(errs) > 0 {
+		message := fmt.Sprintf("Invalid spec: %v", errs.ToAggregate())
+		r.recorder.Event(imageJob, corev1.EventTypeWarning, controllerUtils.ReasonJobFailed, message)
+		imageJob.Status = eraserv1.ImageJobStatus{Phase: eraserv1.PhaseFailed, Conditions: imageJob.Status.Conditions}
+		r.setCondition(imageJob, eraserv1.ConditionScheduled, metav1.ConditionFalse, controllerUtils.ReasonInvalidSpec, message)
+		r.setCondition(imageJob, eraserv1.ConditionProgressing, metav1.ConditionFalse, controllerUtils.ReasonInvalidSpec, message)
+		r.setCondition(imageJob, eraserv1.ConditionSucceeded, metav1.ConditionFalse, controllerUtils.ReasonInvalidSpec, message)
+		return r.updateJobStatus(ctx, imageJob)
+	}
+
+	nodes := &corev1.NodeList{}
+	err = r.List(ctx, nodes)
 	if err != nil {
 		return err
 	}
 
 	imageJob.Status = eraserv1.ImageJobStatus{
-		Desired:   len(nodes.Items),
-		Succeeded: 0,
-		Skipped:   0, // placeholder, updated below
-		Failed:    0,
-		Phase:     eraserv1.PhaseRunning,
+		Desired:    len(nodes.Items),
+		Succeeded:  0,
+		Skipped:    0, // placeholder, updated below
+		Failed:     0,
+		Phase:      eraserv1.PhaseRunning,
+		Conditions: imageJob.Status.Conditions,
 	}
 
-	skipped := 0
-	var nodeList []corev1.Node
-
 	log := log.WithValues("job", imageJob.Name)
+	log.V(1).Info("configuration used", "manager", eraserConfig.Manager, "components", eraserConfig.Components)
 
-	env := []corev1.EnvVar{
-		{Name: "NODE_NAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"}}},
+	nodeList, skipped, err := selectNodes(nodes, imageJob, eraserConfig.Manager.NodeFilter)
+	if err != nil {
+		return err
 	}
 
-	eraserConfig, err := r.eraserConfig.Read()
+	if imageJob.Spec.Mode == eraserv1.ModeRemove {
+		if err := r.ensureImageList(ctx, imageJob); err != nil {
+			return err
+		}
+	}
+
+	imageJob.Status.Skipped = skipped
+	for i := range nodeList {
+		imageJob.Status.PendingNodes = append(imageJob.Status.PendingNodes, nodeList[i].Name)
+	}
+
+	// pods which were started before the status could be updated are kept
+	pods, err := r.jobPods(ctx, imageJob)
 	if err != nil {
 		return err
 	}
-	log.V(1).Info("configuration used", "manager", eraserConfig.Manager, "components", eraserConfig.Components)
 
-	filterOpts := eraserConfig.Manager.NodeFilter
+	// the first batch has no pause before it
+	started, _, err := r.startPods(ctx, imageJob, pods)
+	if err != nil {
+		return err
+	}
+
+	r.setCondition(imageJob, eraserv1.ConditionScheduled, metav1.ConditionTrue, controllerUtils.ReasonNodesSelected,
+		fmt.Sprintf("Selected %d nodes and skipped %d", len(nodeList), skipped))
+	r.setCondition(imageJob, eraserv1.ConditionProgressing, metav1.ConditionTrue, controllerUtils.ReasonPodsRunning,
+		fmt.Sprintf("Running on %d nodes", len(nodeList)))
+	pods = append(pods, started...)
+	r.setPodConditions(imageJob, pods)
+	setNodeStatuses(imageJob, pods)
+	if err := r.updateJobStatus(ctx, imageJob); err != nil {
+		return err
+	}
+
+	if skipped > 0 {
+		r.recorder.Eventf(imageJob, corev1.EventTypeNormal, controllerUtils.ReasonNodesSkipped, "Skipped %d nodes excluded by the node filter", skipped)
+	}
+
+	r.recorder.Eventf(imageJob, corev1.EventTypeNormal, controllerUtils.ReasonJobStarted, "Started %d pods on %d nodes", len(started), len(nodeList))
+
+	for i := range started {
+		namespacedName := types.NamespacedName{Name: started[i].Name, Namespace: started[i].Namespace}
+		if err := wait.PollImmediate(time.Nanosecond, time.Minute*5, r.isPodReady(ctx, namespacedName)); err != nil {
+			log.Error(err, "timed out waiting for pod to leave pending state", "pod NamespacedName", namespacedName)
+		}
+	}
+
+	return nil
+}
+
+// selectNodes returns the nodes which imageJob runs on, and the number of
+// nodes which its node filter, or else the manager's, skips.
+func selectNodes(nodes *corev1.NodeList, imageJob *eraserv1.ImageJob, filterOpts unversioned.NodeFilterConfig) ([]corev1.Node, int, error) {
+	if imageJob.Spec.NodeFilter != nil {
+		filterOpts.Type = imageJob.Spec.NodeFilter.Type
+		filterOpts.Selectors = imageJob.Spec.NodeFilter.Selectors
+	}
+	// copy so that the default label is not added to the configuration
+	filterOpts.Selectors = append([]string{}, filterOpts.Selectors...)
 	if !slices.Contains(filterOpts.Selectors, defaultFilterLabel) {
 		filterOpts.Selectors = append(filterOpts.Selectors, defaultFilterLabel)
 	}
 
 	switch filterOpts.Type {
 	case "exclude":
-		nodeList, skipped, err = filterOutSkippedNodes(nodes, filterOpts.Selectors)
-		if err != nil {
-			return err
-		}
+		return filterOutSkippedNodes(nodes, filterOpts.Selectors)
 	case "include":
-		nodeList, skipped, err = selectIncludedNodes(nodes, filterOpts.Selectors)
-		if err != nil {
-			return err
-		}
+		return selectIncludedNodes(nodes, filterOpts.Selectors)
 	default:
-		return errors.Errorf("invalid node filter option")
+		return nil, -1, errors.Errorf("invalid node filter option")
 	}
+}
 
-	imageJob.Status.Skipped = skipped
-	if err := r.updateJobStatus(ctx, imageJob); err != nil {
-		return err
+// blockingJob returns the job which imageJob is queued behind, if any. Jobs
+// which run on some of the same nodes run one at a time, in the order they
+// were created, so that their pods do not compete for the same disks.
+func (r *Reconciler) blockingJob(ctx context.Context, imageJob *eraserv1.ImageJob) (*eraserv1.ImageJob, error) {
+	jobs := &eraserv1.ImageJobList{}
+	if err := r.List(ctx, jobs); err != nil {
+		return nil, err
 	}
 
-	var namespacedNames []types.NamespacedName
-	podSpecTemplate := template.Template.Spec
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes); err != nil {
+		return nil, err
+	}
+
+	eraserConfig, err := r.eraserConfig.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	nodeList, _, err := selectNodes(nodes, imageJob, eraserConfig.Manager.NodeFilter)
+	if err != nil {
+		// the job fails when it is handled
+		return nil, nil
+	}
+
+	selected := make(map[string]bool, len(nodeList))
 	for i := range nodeList {
-		log := log.WithValues("node", nodeList[i].Name)
-		podSpec, err := copyAndFillTemplateSpec(&podSpecTemplate, env, &nodeList[i], &eraserConfig.Manager.Runtime)
-		if err != nil {
-			return err
+		selected[nodeList[i].Name] = true
+	}
+
+	for i := range jobs.Items {
+		other := &jobs.Items[i]
+		if other.UID == imageJob.UID || controllerUtils.IsCompletedOrFailed(other.Status.Phase) {
+			continue
 		}
 
-		containerName := podSpec.Containers[0].Name
-		nodeName := nodeList[i].Name
+		var otherNodes []string
+		switch other.Status.Phase {
+		case eraserv1.PhaseRunning:
+			pods, err := r.jobPods(ctx, other)
+			if err != nil {
+				return nil, err
+			}
+			for j := range pods {
+				otherNodes = append(otherNodes, pods[j].Spec.NodeName)
+			}
+			otherNodes = append(otherNodes, other.Status.PendingNodes...)
+		case "":
+			if !createdBefore(other, imageJob) {
+				continue
+			}
 
-		pod := &corev1.Pod{
-			TypeMeta: metav1.TypeMeta{},
-			Spec:     *podSpec,
-			ObjectMeta: metav1.ObjectMeta{
-				Namespace:    eraserUtils.GetNamespace(),
-				GenerateName: "eraser-" + nodeName + "-",
-				OwnerReferences: []metav1.OwnerReference{
-					*metav1.NewControllerRef(&template, template.GroupVersionKind()),
-				},
-			},
+			otherList, _, err := selectNodes(nodes, other, eraserConfig.Manager.NodeFilter)
+			if err != nil {
+				continue
+			}
+			for j := range otherList {
+				otherNodes = append(otherNodes, otherList[j].Name)
+			}
+		}
+
+		for _, node := range otherNodes {
+			if selected[node] {
+				return other, nil
+			}
 		}
+	}
 
-		pod.Labels = map[string]string{}
+	return nil, nil
+}
+
+func createdBefore(a, b *eraserv1.ImageJob) bool {
+	if a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.Name < b.Name
+	}
 
-		for k, v := range eraserConfig.Manager.AdditionalPodLabels {
-			pod.Labels[k] = v
+	return a.CreationTimestamp.Before(&b.CreationTimestamp)
+}
+
+// ValidateSpec checks the parts of an ImageJobSpec which the CRD's schema
+// does not. The images which the spec overrides the configured component
+// images with must be listed in the configuration's AllowedImages, since the
+// job's pods are privileged.
+func ValidateSpec(spec *eraserv1.ImageJobSpec, cfg *unversioned.ImageJobConfig) field.ErrorList {
+	var errs field.ErrorList
+	path := field.NewPath("spec")
+
+	switch spec.Mode {
+	case eraserv1.ModeRemove:
+		if len(spec.Images) == 0 {
+			errs = append(errs, field.Required(path.Child("images"), "no images to remove"))
 		}
+	case eraserv1.ModeCollect:
+	default:
+		errs = append(errs, field.NotSupported(path.Child("mode"), spec.Mode, []string{string(eraserv1.ModeRemove), string(eraserv1.ModeCollect)}))
+	}
 
-		if containerName == removerContainer {
-			pod.Labels[imageJobTypeLabelKey] = manualJobType
-		} else {
-			pod.Labels[imageJobTypeLabelKey] = collectorJobType
+	if spec.Concurrency < 0 {
+		errs = append(errs, field.Invalid(path.Child("concurrency"), spec.Concurrency, "must not be negative"))
+	}
+
+	overrides := []struct {
+		name  string
+		image string
+	}{
+		{"removerImage", spec.RemoverImage},
+		{"collectorImage", spec.CollectorImage},
+		{"scannerImage", spec.ScannerImage},
+	}
+	for _, o := range overrides {
+		if o.image != "" && !slices.Contains(cfg.AllowedImages, o.image) {
+			errs = append(errs, field.Forbidden(path.Child(o.name), fmt.Sprintf("%q is not listed in manager.imageJob.allowedImages", o.image)))
 		}
+	}
 
-		fitness := checkNodeFitness(pod, &nodeList[i])
-		if !fitness {
-			log.Info(containerName + " pod does not fit on node, skipping")
+	return errs
+}
+
+// startPods starts pods on imageJob's pending nodes, and removes those nodes
+// from its status. Pods start until Concurrency of them are running, and
+// within the rollout limits of the manager's configuration. If the pause
+// between batches holds the next batch back, it returns how long is left of
+// the pause. Nodes which already have a pod, or which a pod does not fit on,
+// are skipped.
+func (r *Reconciler) startPods(ctx context.Context, imageJob *eraserv1.ImageJob, pods []corev1.Pod) ([]corev1.Pod, time.Duration, error) {
+	log := log.WithValues("job", imageJob.Name)
+
+	eraserConfig, err := r.eraserConfig.Read()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	rollout := &eraserConfig.Manager.ImageJob.Rollout
+	counts := countPods(pods)
+	if failureRatioReached(rollout, counts) {
+		r.stopRollout(imageJob, rollout, counts)
+		return nil, 0, nil
+	}
+
+	batch, pause := batchSize(rollout, imageJob.Status.Desired-imageJob.Status.Skipped, counts, time.Now())
+	if batch == 0 {
+		return nil, pause, nil
+	}
+
+	pools, err := r.nodePools(ctx, rollout, pods)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	podSpecTemplate, err := r.podSpec(ctx, imageJob, &eraserConfig)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	env := []corev1.EnvVar{
+		{Name: "NODE_NAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"}}},
+		{Name: eraserUtils.EnvEraserJobName, Value: imageJob.Name},
+	}
+
+	hasPod := make(map[string]bool, len(pods))
+	for i := range pods {
+		hasPod[pods[i].Spec.NodeName] = true
+	}
+
+	var started []corev1.Pod
+	var waiting []string
+	for i, nodeName := range imageJob.Status.PendingNodes {
+		if (imageJob.Spec.Concurrency > 0 && counts.active >= imageJob.Spec.Concurrency) || len(started) == batch {
+			waiting = append(waiting, imageJob.Status.PendingNodes[i:]...)
+			break
+		}
+
+		if hasPod[nodeName] {
 			continue
 		}
 
-		err = r.Create(ctx, pod)
+		log := log.WithValues("node", nodeName)
+		node := &corev1.Node{}
+		if err := r.Get(ctx, types.NamespacedName{Name: nodeName}, node); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return started, 0, err
+			}
+			log.Info("node no longer exists, skipping")
+			continue
+		}
+
+		// the node waits for a pod of its pool to finish
+		if pools.full(node) {
+			waiting = append(waiting, nodeName)
+			continue
+		}
+
+		pod, err := r.createPod(ctx, log, imageJob, podSpecTemplate, env, node, &eraserConfig)
 		if err != nil {
-			return err
+			return started, 0, err
 		}
+		if pod != nil {
+			started = append(started, *pod)
+			counts.active++
+			pools.add(node)
+		}
+	}
+
+	imageJob.Status.PendingNodes = waiting
+	return started, 0, nil
+}
 
-		log.Info("Started "+containerName+" pod on node", "nodeName", nodeName)
-		namespacedNames = append(namespacedNames, types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace})
+// createPod creates the job pod of one node, or returns nil if the pod does
+// not fit on the node. The pod is given the context of its span, so that its
+// own spans are part of the ImageJob's trace.
+func (r *Reconciler) createPod(ctx context.Context, log logr.Logger, imageJob *eraserv1.ImageJob, podSpecTemplate *corev1.PodSpec, env []corev1.EnvVar, node *corev1.Node, eraserConfig *unversioned.EraserConfig) (_ *corev1.Pod, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "imagejob.createPod", trace.WithAttributes(attribute.String("node", node.Name)))
+	defer func() { tracing.End(span, err) }()
+
+	podSpec, err := copyAndFillTemplateSpec(podSpecTemplate, env, node, &eraserConfig.Manager.Runtime, tracing.Traceparent(ctx))
+	if err != nil {
+		return nil, err
 	}
 
-	for _, namespacedName := range namespacedNames {
-		if err := wait.PollImmediate(time.Nanosecond, time.Minute*5, r.isPodReady(ctx, namespacedName)); err != nil {
-			log.Error(err, "timed out waiting for pod to leave pending state", "pod NamespacedName", namespacedName)
-		}
+	containerName := podSpec.Containers[0].Name
+	nodeName := node.Name
+
+	pod := &corev1.Pod{
+		TypeMeta: metav1.TypeMeta{},
+		Spec:     *podSpec,
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:    eraserUtils.GetNamespace(),
+			GenerateName: "eraser-" + nodeName + "-",
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(imageJob, eraserv1.GroupVersion.WithKind("ImageJob")),
+			},
+		},
 	}
 
-	return nil
+	pod.Labels = map[string]string{}
+
+	for k, v := range eraserConfig.Manager.AdditionalPodLabels {
+		pod.Labels[k] = v
+	}
+
+	if imageJob.Spec.Mode == eraserv1.ModeRemove {
+		pod.Labels[imageJobTypeLabelKey] = manualJobType
+	} else {
+		pod.Labels[imageJobTypeLabelKey] = collectorJobType
+	}
+
+	fitness := checkNodeFitness(pod, node)
+	if !fitness {
+		log.Info(containerName + " pod does not fit on node, skipping")
+		r.recorder.Eventf(imageJob, corev1.EventTypeWarning, controllerUtils.ReasonNodeSkipped, "%s pod does not fit on node %s", containerName, nodeName)
+		return nil, nil
+	}
+
+	err = r.Create(ctx, pod)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Started "+containerName+" pod on node", "nodeName", nodeName)
+
+	return pod, nil
 }
 
 func (r *Reconciler) isPodReady(ctx context.Context, namespacedName types.NamespacedName) wait.ConditionFunc {
@@ -485,12 +918,84 @@ func containersFailed(pod *corev1.Pod) bool {
 }
 
 func (r *Reconciler) updateJobStatus(ctx context.Context, imageJob *eraserv1.ImageJob) error {
-	if imageJob.Name != "" {
-		if err := r.Status().Update(ctx, imageJob); err != nil {
-			return err
+	if imageJob.Name == "" {
+		return nil
+	}
+
+	status := imageJob.Status.DeepCopy()
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := r.Status().Update(ctx, imageJob)
+		if apierrors.IsConflict(err) {
+			if err := r.Get(ctx, client.ObjectKeyFromObject(imageJob), imageJob); err != nil {
+				return err
+			}
+			mergeJobStatus(&imageJob.Status, status)
+		}
+		return err
+	})
+}
+
+// jobConditions are the conditions of a job which this controller sets.
+var jobConditions = []string{
+	eraserv1.ConditionScheduled,
+	eraserv1.ConditionPodsCreated,
+	eraserv1.ConditionProgressing,
+	eraserv1.ConditionSucceeded,
+	eraserv1.ConditionDegraded,
+}
+
+// mergeJobStatus sets the fields of latest which this controller owns from
+// status. DeleteAfter, which the job's owner sets, and the conditions of
+// other controllers are kept. The creator of a job sets its initial
+// Scheduled condition, which is superseded by the one set here.
+func mergeJobStatus(latest, status *eraserv1.ImageJobStatus) {
+	deleteAfter := latest.DeleteAfter
+	conditions := latest.Conditions
+
+	// copied, so that fetching the job again does not overwrite status
+	*latest = *status.DeepCopy()
+	latest.DeleteAfter = deleteAfter
+	latest.Conditions = conditions
+	for _, conditionType := range jobConditions {
+		if c := meta.FindStatusCondition(status.Conditions, conditionType); c != nil {
+			meta.SetStatusCondition(&latest.Conditions, *c)
 		}
 	}
-	return nil
+}
+
+// setCondition sets a condition of imageJob, and returns whether it changed.
+func (r *Reconciler) setCondition(imageJob *eraserv1.ImageJob, conditionType string, status metav1.ConditionStatus, reason, message string) bool {
+	return controllerUtils.SetCondition(&imageJob.Status.Conditions, imageJob.Generation, conditionType, status, reason, message)
+}
+
+// setPodConditions sets the PodsCreated and Degraded conditions of imageJob
+// from its pods, and returns whether they changed.
+func (r *Reconciler) setPodConditions(imageJob *eraserv1.ImageJob, pods []corev1.Pod) bool {
+	var changed bool
+	if stopped := imageJob.Status.Stopped; stopped > 0 {
+		changed = r.setCondition(imageJob, eraserv1.ConditionPodsCreated, metav1.ConditionFalse, controllerUtils.ReasonRolloutStopped,
+			fmt.Sprintf("Created %d pods, and %d nodes were left out after too many pods failed", len(pods), stopped))
+	} else if pending := len(imageJob.Status.PendingNodes); pending > 0 {
+		changed = r.setCondition(imageJob, eraserv1.ConditionPodsCreated, metav1.ConditionFalse, controllerUtils.ReasonPodsPending,
+			fmt.Sprintf("Created %d pods, and %d nodes are waiting for a pod", len(pods), pending))
+	} else {
+		changed = r.setCondition(imageJob, eraserv1.ConditionPodsCreated, metav1.ConditionTrue, controllerUtils.ReasonAllPodsCreated,
+			fmt.Sprintf("Created %d pods", len(pods)))
+	}
+
+	failed := 0
+	for i := range pods {
+		if pods[i].Status.Phase == corev1.PodFailed || containersFailed(&pods[i]) {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return r.setCondition(imageJob, eraserv1.ConditionDegraded, metav1.ConditionTrue, controllerUtils.ReasonPodsFailed,
+			fmt.Sprintf("%d of %d pods failed", failed, len(pods))) || changed
+	}
+
+	return r.setCondition(imageJob, eraserv1.ConditionDegraded, metav1.ConditionFalse, controllerUtils.ReasonNoPodsFailed, "No pods failed") || changed
 }
 
 func selectIncludedNodes(nodes *corev1.NodeList, includeNodesSelectors []string) ([]corev1.Node, int, error) {
@@ -562,7 +1067,7 @@ nodes:
 	return nodeList, skipped, nil
 }
 
-func copyAndFillTemplateSpec(templateSpecTemplate *corev1.PodSpec, env []corev1.EnvVar, node *corev1.Node, runtimeSpec *unversioned.RuntimeSpec) (*corev1.PodSpec, error) {
+func copyAndFillTemplateSpec(templateSpecTemplate *corev1.PodSpec, env []corev1.EnvVar, node *corev1.Node, runtimeSpec *unversioned.RuntimeSpec, traceparent string) (*corev1.PodSpec, error) {
 	nodeName := node.Name
 
 	u, err := url.Parse(runtimeSpec.Address)
@@ -578,6 +1083,17 @@ func copyAndFillTemplateSpec(templateSpecTemplate *corev1.PodSpec, env []corev1.
 		{MountPath: controllerUtils.CRIPath, Name: "runtime-sock-volume"},
 	}
 
+	nodeLabels, err := json.Marshal(node.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	// copy so that the per-node variable does not leak into the shared slice
+	env = append(append([]corev1.EnvVar{}, env...), corev1.EnvVar{Name: eraserUtils.EnvEraserNodeLabels, Value: string(nodeLabels)})
+	if traceparent != "" {
+		env = append(env, corev1.EnvVar{Name: tracing.EnvTraceparent, Value: traceparent})
+	}
+
 	templateSpec := templateSpecTemplate.DeepCopy()
 	templateSpec.Tolerations = defaultTolerations
 
@@ -0,0 +1,566 @@
+package imagejob
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+	"github.com/eraser-dev/eraser/api/unversioned/config"
+	eraserv1 "github.com/eraser-dev/eraser/api/v1"
+	controllerUtils "github.com/eraser-dev/eraser/controllers/util"
+	eraserUtils "github.com/eraser-dev/eraser/pkg/utils"
+)
+
+// newTestReconciler returns a Reconciler with a fake client which holds
+// objs, and which reads cfg as the manager's configuration.
+func newTestReconciler(t *testing.T, cfg *unversioned.EraserConfig, objs ...client.Object) *Reconciler {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := eraserv1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	return &Reconciler{
+		Client:       fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+		scheme:       scheme,
+		eraserConfig: config.NewManager(cfg),
+		recorder:     record.NewFakeRecorder(10),
+	}
+}
+
+func testNode(name, cpu string, labels map[string]string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+				corev1.ResourcePods:   resource.MustParse("110"),
+			},
+		},
+	}
+}
+
+func TestValidateSpec(t *testing.T) {
+	cfg := unversioned.ImageJobConfig{AllowedImages: []string{"registry.local/remover:dev"}}
+
+	tests := []struct {
+		desc     string
+		spec     eraserv1.ImageJobSpec
+		wantErrs []string
+	}{
+		{
+			desc: "remove",
+			spec: eraserv1.ImageJobSpec{Mode: eraserv1.ModeRemove, Images: []string{"alpine:3.7.3"}},
+		},
+		{
+			desc: "collect",
+			spec: eraserv1.ImageJobSpec{Mode: eraserv1.ModeCollect, ScannerEnabled: true},
+		},
+		{
+			desc:     "remove without images",
+			spec:     eraserv1.ImageJobSpec{Mode: eraserv1.ModeRemove},
+			wantErrs: []string{"spec.images"},
+		},
+		{
+			desc:     "unsupported mode and negative concurrency",
+			spec:     eraserv1.ImageJobSpec{Mode: "Prune", Concurrency: -1},
+			wantErrs: []string{"spec.mode", "spec.concurrency"},
+		},
+		{
+			desc: "allowed override",
+			spec: eraserv1.ImageJobSpec{Mode: eraserv1.ModeCollect, RemoverImage: "registry.local/remover:dev"},
+		},
+		{
+			desc:     "overrides which are not allowed",
+			spec:     eraserv1.ImageJobSpec{Mode: eraserv1.ModeCollect, CollectorImage: "attacker/collector", ScannerImage: "registry.local/remover:dev2"},
+			wantErrs: []string{"spec.collectorImage", "spec.scannerImage"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			errs := ValidateSpec(&tt.spec, &cfg)
+			if len(errs) != len(tt.wantErrs) {
+				t.Fatalf("expected %d errors, got %d: %v", len(tt.wantErrs), len(errs), errs)
+			}
+
+			for i, want := range tt.wantErrs {
+				if errs[i].Field != want {
+					t.Errorf("expected error %d to be on %s, got: %s", i, want, errs[i].Field)
+				}
+			}
+		})
+	}
+}
+
+func TestStartPods(t *testing.T) {
+	nodes := []client.Object{
+		testNode("node-a", "4", nil),
+		testNode("node-b", "4", nil),
+		// too small for the scanner's request
+		testNode("node-small", "500m", nil),
+	}
+
+	remove := eraserv1.ImageJobSpec{Mode: eraserv1.ModeRemove, Images: []string{"alpine:3.7.3"}}
+	running := corev1.Pod{Spec: corev1.PodSpec{NodeName: "node-a"}, Status: corev1.PodStatus{Phase: corev1.PodRunning}}
+
+	tests := []struct {
+		desc    string
+		spec    eraserv1.ImageJobSpec
+		pending []string
+		pods    []corev1.Pod
+		rollout unversioned.RolloutConfig
+		started []string
+		waiting []string
+	}{
+		{
+			desc:    "every pending node",
+			spec:    remove,
+			pending: []string{"node-a", "node-b"},
+			started: []string{"node-a", "node-b"},
+		},
+		{
+			desc:    "limited concurrency",
+			spec:    eraserv1.ImageJobSpec{Mode: eraserv1.ModeRemove, Images: []string{"alpine:3.7.3"}, Concurrency: 1},
+			pending: []string{"node-a", "node-b"},
+			started: []string{"node-a"},
+			waiting: []string{"node-b"},
+		},
+		{
+			desc:    "node which already has a pod",
+			spec:    remove,
+			pending: []string{"node-a", "node-b"},
+			pods:    []corev1.Pod{running},
+			started: []string{"node-b"},
+		},
+		{
+			desc:    "node which no longer exists",
+			spec:    remove,
+			pending: []string{"node-gone", "node-b"},
+			started: []string{"node-b"},
+		},
+		{
+			desc:    "batch size",
+			spec:    remove,
+			pending: []string{"node-a", "node-b"},
+			rollout: unversioned.RolloutConfig{BatchSize: intstr.FromInt(1)},
+			started: []string{"node-a"},
+			waiting: []string{"node-b"},
+		},
+		{
+			desc:    "batch still running",
+			spec:    remove,
+			pending: []string{"node-b"},
+			pods:    []corev1.Pod{running},
+			rollout: unversioned.RolloutConfig{BatchSize: intstr.FromInt(1)},
+			waiting: []string{"node-b"},
+		},
+		{
+			desc:    "collect without scanner",
+			spec:    eraserv1.ImageJobSpec{Mode: eraserv1.ModeCollect},
+			pending: []string{"node-small"},
+			started: []string{"node-small"},
+		},
+		{
+			desc:    "collect with scanner which does not fit",
+			spec:    eraserv1.ImageJobSpec{Mode: eraserv1.ModeCollect, ScannerEnabled: true},
+			pending: []string{"node-a", "node-small"},
+			started: []string{"node-a"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Manager.ImageJob.Rollout = tt.rollout
+
+			r := newTestReconciler(t, cfg, nodes...)
+			job := &eraserv1.ImageJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "imagejob-abc"},
+				Spec:       tt.spec,
+				Status:     eraserv1.ImageJobStatus{Desired: 2, PendingNodes: tt.pending},
+			}
+
+			started, _, err := r.startPods(context.Background(), job, tt.pods)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			startedOn := make([]string, 0, len(started))
+			for i := range started {
+				startedOn = append(startedOn, started[i].Spec.NodeName)
+			}
+			if strings.Join(startedOn, ",") != strings.Join(tt.started, ",") {
+				t.Errorf("expected pods on %v, got %v", tt.started, startedOn)
+			}
+			if strings.Join(job.Status.PendingNodes, ",") != strings.Join(tt.waiting, ",") {
+				t.Errorf("expected pending nodes %v, got %v", tt.waiting, job.Status.PendingNodes)
+			}
+
+			pods := &corev1.PodList{}
+			if err := r.List(context.Background(), pods); err != nil {
+				t.Fatal(err)
+			}
+			if len(pods.Items) != len(tt.started) {
+				t.Errorf("expected %d pods to be created, got %d", len(tt.started), len(pods.Items))
+			}
+		})
+	}
+}
+
+func TestSelectNodes(t *testing.T) {
+	nodes := &corev1.NodeList{Items: []corev1.Node{
+		*testNode("node-a", "4", map[string]string{"zone": "a"}),
+		*testNode("node-b", "4", map[string]string{"zone": "b"}),
+		*testNode("node-filtered", "4", map[string]string{"zone": "a", defaultFilterLabel: ""}),
+	}}
+	exclude := unversioned.NodeFilterConfig{Type: "exclude"}
+
+	tests := []struct {
+		desc     string
+		filter   *eraserv1.NodeFilter
+		config   unversioned.NodeFilterConfig
+		selected []string
+		skipped  int
+		wantErr  bool
+	}{
+		{desc: "default filter label", config: exclude, selected: []string{"node-a", "node-b"}, skipped: 1},
+		{
+			desc:     "configured filter",
+			config:   unversioned.NodeFilterConfig{Type: "exclude", Selectors: []string{"zone=b"}},
+			selected: []string{"node-a"},
+			skipped:  2,
+		},
+		{
+			desc:     "job filter overrides the configuration",
+			filter:   &eraserv1.NodeFilter{Type: "include", Selectors: []string{"zone=b"}},
+			config:   exclude,
+			selected: []string{"node-b", "node-filtered"},
+			skipped:  1,
+		},
+		{desc: "invalid type", config: unversioned.NodeFilterConfig{Type: "only"}, wantErr: true},
+		{desc: "invalid selector", config: unversioned.NodeFilterConfig{Type: "exclude", Selectors: []string{"a b"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			job := &eraserv1.ImageJob{Spec: eraserv1.ImageJobSpec{Mode: eraserv1.ModeCollect, NodeFilter: tt.filter}}
+
+			selected, skipped, err := selectNodes(nodes, job, tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error: %v, got: %v", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			names := make([]string, 0, len(selected))
+			for i := range selected {
+				names = append(names, selected[i].Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.selected, ",") {
+				t.Errorf("expected nodes %v, got %v", tt.selected, names)
+			}
+			if skipped != tt.skipped {
+				t.Errorf("expected %d skipped, got %d", tt.skipped, skipped)
+			}
+		})
+	}
+}
+
+func TestBlockingJob(t *testing.T) {
+	created := metav1.NewTime(time.Date(2023, time.June, 1, 10, 0, 0, 0, time.UTC))
+	earlier := metav1.NewTime(created.Add(-time.Minute))
+	later := metav1.NewTime(created.Add(time.Minute))
+	zone := func(z string) *eraserv1.NodeFilter {
+		return &eraserv1.NodeFilter{Type: "include", Selectors: []string{"zone=" + z}}
+	}
+
+	newJob := func(name string, creation metav1.Time, filter *eraserv1.NodeFilter, phase eraserv1.JobPhase, pending ...string) *eraserv1.ImageJob {
+		return &eraserv1.ImageJob{
+			ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(name), CreationTimestamp: creation},
+			Spec:       eraserv1.ImageJobSpec{Mode: eraserv1.ModeRemove, Images: []string{"alpine"}, NodeFilter: filter},
+			Status:     eraserv1.ImageJobStatus{Phase: phase, PendingNodes: pending},
+		}
+	}
+	podOf := func(job *eraserv1.ImageJob, node string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            job.Name + "-" + node,
+				Namespace:       eraserUtils.GetNamespace(),
+				Labels:          map[string]string{imageJobTypeLabelKey: manualJobType},
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(job, eraserv1.GroupVersion.WithKind("ImageJob"))},
+			},
+			Spec: corev1.PodSpec{NodeName: node},
+		}
+	}
+
+	running := newJob("job-running", earlier, nil, eraserv1.PhaseRunning)
+	runningWithPending := newJob("job-running", earlier, nil, eraserv1.PhaseRunning, "node-b")
+
+	tests := []struct {
+		desc     string
+		job      *eraserv1.ImageJob
+		others   []client.Object
+		expected string
+	}{
+		{desc: "no other jobs", job: newJob("job-b", created, nil, "")},
+		{
+			desc:     "older queued job on the same nodes",
+			job:      newJob("job-b", created, zone("a"), ""),
+			others:   []client.Object{newJob("job-a", earlier, nil, "")},
+			expected: "job-a",
+		},
+		{
+			desc:   "older queued job on other nodes",
+			job:    newJob("job-b", created, zone("a"), ""),
+			others: []client.Object{newJob("job-a", earlier, zone("b"), "")},
+		},
+		{
+			desc:   "newer queued job on the same nodes",
+			job:    newJob("job-b", created, nil, ""),
+			others: []client.Object{newJob("job-a", later, nil, "")},
+		},
+		{
+			desc:     "created at the same time, earlier name",
+			job:      newJob("job-b", created, nil, ""),
+			others:   []client.Object{newJob("job-a", created, nil, "")},
+			expected: "job-a",
+		},
+		{
+			desc:   "created at the same time, later name",
+			job:    newJob("job-b", created, nil, ""),
+			others: []client.Object{newJob("job-c", created, nil, "")},
+		},
+		{
+			desc:     "running job with a pod on the same node",
+			job:      newJob("job-b", created, zone("a"), ""),
+			others:   []client.Object{running, podOf(running, "node-a")},
+			expected: "job-running",
+		},
+		{
+			desc:   "running job with a pod on another node",
+			job:    newJob("job-b", created, zone("a"), ""),
+			others: []client.Object{running, podOf(running, "node-b")},
+		},
+		{
+			desc:     "running job with the same node pending",
+			job:      newJob("job-b", created, zone("b"), ""),
+			others:   []client.Object{runningWithPending, podOf(runningWithPending, "node-a")},
+			expected: "job-running",
+		},
+		{
+			desc:   "finished job on the same nodes",
+			job:    newJob("job-b", created, nil, ""),
+			others: []client.Object{newJob("job-a", earlier, nil, eraserv1.PhaseCompleted)},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			objs := append([]client.Object{
+				testNode("node-a", "4", map[string]string{"zone": "a"}),
+				testNode("node-b", "4", map[string]string{"zone": "b"}),
+				tt.job,
+			}, tt.others...)
+			r := newTestReconciler(t, config.Default(), objs...)
+
+			blocking, err := r.blockingJob(context.Background(), tt.job)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var actual string
+			if blocking != nil {
+				actual = blocking.Name
+			}
+			if actual != tt.expected {
+				t.Errorf("expected to be blocked by %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestReconcileQueuedJob(t *testing.T) {
+	blocking := &eraserv1.ImageJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "job-a", UID: "job-a", CreationTimestamp: metav1.NewTime(time.Date(2023, time.June, 1, 10, 0, 0, 0, time.UTC))},
+		Spec:       eraserv1.ImageJobSpec{Mode: eraserv1.ModeCollect},
+	}
+	job := &eraserv1.ImageJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "job-b", UID: "job-b", CreationTimestamp: metav1.NewTime(time.Date(2023, time.June, 1, 11, 0, 0, 0, time.UTC))},
+		Spec:       eraserv1.ImageJobSpec{Mode: eraserv1.ModeCollect},
+	}
+
+	r := newTestReconciler(t, config.Default(), testNode("node-a", "4", nil), blocking, job)
+	recorder := r.recorder.(*record.FakeRecorder)
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: job.Name}}
+
+	for i := 0; i < 3; i++ {
+		res, err := r.Reconcile(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.RequeueAfter != queueInterval {
+			t.Errorf("expected to be requeued after %s, got %s", queueInterval, res.RequeueAfter)
+		}
+	}
+
+	if events := len(recorder.Events); events != 1 {
+		t.Errorf("expected one JobQueued event, got %d", events)
+	}
+
+	queued := &eraserv1.ImageJob{}
+	if err := r.Get(context.Background(), req.NamespacedName, queued); err != nil {
+		t.Fatal(err)
+	}
+	scheduled := meta.FindStatusCondition(queued.Status.Conditions, eraserv1.ConditionScheduled)
+	if scheduled == nil || scheduled.Reason != controllerUtils.ReasonJobQueued {
+		t.Errorf("expected the job to be queued, got %+v", scheduled)
+	}
+}
+
+func TestUpdateJobStatusConflict(t *testing.T) {
+	job := &eraserv1.ImageJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "imagejob-abc"},
+		Spec:       eraserv1.ImageJobSpec{Mode: eraserv1.ModeCollect},
+	}
+	r := newTestReconciler(t, config.Default(), job)
+	ctx := context.Background()
+
+	stale := &eraserv1.ImageJob{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(job), stale); err != nil {
+		t.Fatal(err)
+	}
+
+	// the job's owner and another controller update the job in the meantime
+	deleteAfter := metav1.NewTime(time.Date(2023, time.June, 1, 11, 0, 0, 0, time.UTC))
+	current := stale.DeepCopy()
+	current.Status.DeleteAfter = &deleteAfter
+	controllerUtils.SetCondition(&current.Status.Conditions, 1, "Audited", metav1.ConditionTrue, "Audited", "audited")
+	controllerUtils.SetCondition(&current.Status.Conditions, 1, eraserv1.ConditionScheduled, metav1.ConditionUnknown, controllerUtils.ReasonJobCreated, "created")
+	if err := r.Status().Update(ctx, current); err != nil {
+		t.Fatal(err)
+	}
+
+	stale.Status.Phase = eraserv1.PhaseRunning
+	stale.Status.Desired = 3
+	r.setCondition(stale, eraserv1.ConditionScheduled, metav1.ConditionTrue, controllerUtils.ReasonNodesSelected, "selected")
+	if err := r.updateJobStatus(ctx, stale); err != nil {
+		t.Fatal(err)
+	}
+
+	updated := &eraserv1.ImageJob{}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(job), updated); err != nil {
+		t.Fatal(err)
+	}
+
+	if updated.Status.Phase != eraserv1.PhaseRunning || updated.Status.Desired != 3 {
+		t.Errorf("expected the controller's fields to be updated, got %+v", updated.Status)
+	}
+	if updated.Status.DeleteAfter == nil || !updated.Status.DeleteAfter.Equal(&deleteAfter) {
+		t.Errorf("expected DeleteAfter to be kept, got %v", updated.Status.DeleteAfter)
+	}
+	if meta.FindStatusCondition(updated.Status.Conditions, "Audited") == nil {
+		t.Error("expected the other controller's condition to be kept")
+	}
+	if scheduled := meta.FindStatusCondition(updated.Status.Conditions, eraserv1.ConditionScheduled); scheduled == nil || scheduled.Reason != controllerUtils.ReasonNodesSelected {
+		t.Errorf("expected the controller's Scheduled condition, got %+v", scheduled)
+	}
+}
+
+func TestHandleFinishedJob(t *testing.T) {
+	tests := []struct {
+		desc         string
+		succeeded    int
+		failed       int
+		stopped      int
+		successRatio float64
+		phase        eraserv1.JobPhase
+		reason       string
+	}{
+		{desc: "ratio met", succeeded: 9, failed: 1, successRatio: 0.8, phase: eraserv1.PhaseCompleted, reason: controllerUtils.ReasonJobCompleted},
+		{desc: "ratio not met", succeeded: 7, failed: 3, successRatio: 0.8, phase: eraserv1.PhaseFailed, reason: controllerUtils.ReasonJobFailed},
+		{desc: "every pod must succeed", succeeded: 9, failed: 1, successRatio: 1.0, phase: eraserv1.PhaseFailed, reason: controllerUtils.ReasonJobFailed},
+		{desc: "no nodes", successRatio: 1.0, phase: eraserv1.PhaseCompleted, reason: controllerUtils.ReasonJobCompleted},
+		{desc: "rollout stopped", succeeded: 1, failed: 2, stopped: 7, successRatio: 0.8, phase: eraserv1.PhaseFailed, reason: controllerUtils.ReasonRolloutStopped},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			job := &eraserv1.ImageJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "imagejob-abc", UID: "imagejob-abc"},
+				Spec:       eraserv1.ImageJobSpec{Mode: eraserv1.ModeCollect},
+				Status:     eraserv1.ImageJobStatus{Phase: eraserv1.PhaseRunning, Desired: tt.succeeded + tt.failed + tt.stopped, Stopped: tt.stopped},
+			}
+
+			objs := []client.Object{job}
+			for i := 0; i < tt.succeeded+tt.failed; i++ {
+				phase := corev1.PodSucceeded
+				if i >= tt.succeeded {
+					phase = corev1.PodFailed
+				}
+				pod := testPod(fmt.Sprintf("node-%d", i), phase, time.Time{})
+				pod.Namespace = eraserUtils.GetNamespace()
+				pod.Labels = map[string]string{imageJobTypeLabelKey: collectorJobType}
+				pod.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(job, eraserv1.GroupVersion.WithKind("ImageJob"))}
+				objs = append(objs, &pod)
+			}
+
+			cfg := config.Default()
+			cfg.Manager.ImageJob.SuccessRatio = tt.successRatio
+			r := newTestReconciler(t, cfg, objs...)
+			ctx := context.Background()
+
+			if _, err := r.handleRunningJob(ctx, job.DeepCopy()); err != nil {
+				t.Fatal(err)
+			}
+
+			finished := &eraserv1.ImageJob{}
+			if err := r.Get(ctx, client.ObjectKeyFromObject(job), finished); err != nil {
+				t.Fatal(err)
+			}
+
+			if finished.Status.Phase != tt.phase {
+				t.Errorf("expected phase %s, got %s", tt.phase, finished.Status.Phase)
+			}
+
+			ratioMet := tt.phase == eraserv1.PhaseCompleted
+			succeeded := meta.FindStatusCondition(finished.Status.Conditions, eraserv1.ConditionSucceeded)
+			if succeeded == nil || succeeded.Status != controllerUtils.ConditionStatus(ratioMet) || succeeded.Reason != tt.reason {
+				t.Fatalf("expected Succeeded to be %v with reason %s, got %+v", ratioMet, tt.reason, succeeded)
+			}
+			if stoppedMessage := strings.Contains(succeeded.Message, "rollout was stopped"); stoppedMessage != (tt.stopped > 0) {
+				t.Errorf("expected the message to report the stopped rollout: %v, got %q", tt.stopped > 0, succeeded.Message)
+			}
+			if !meta.IsStatusConditionPresentAndEqual(finished.Status.Conditions, eraserv1.ConditionSuccessRatioNotMet, controllerUtils.ConditionStatus(!ratioMet)) {
+				t.Errorf("expected SuccessRatioNotMet to be %v, got %+v", !ratioMet, meta.FindStatusCondition(finished.Status.Conditions, eraserv1.ConditionSuccessRatioNotMet))
+			}
+		})
+	}
+}
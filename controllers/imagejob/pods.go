@@ -0,0 +1,260 @@
+package imagejob
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+	eraserv1 "github.com/eraser-dev/eraser/api/v1"
+	controllerUtils "github.com/eraser-dev/eraser/controllers/util"
+	"github.com/eraser-dev/eraser/pkg/logger"
+	eraserUtils "github.com/eraser-dev/eraser/pkg/utils"
+)
+
+const (
+	collectorContainer = "collector"
+	scannerContainer   = "trivy-scanner"
+
+	imgListPath      = "/run/eraser.sh/imagelist"
+	imgListVolume    = "imagelist"
+	sharedDataPath   = "/run/eraser.sh/shared-data"
+	sharedDataVolume = "shared-data"
+	configVolumeName = "eraser-config"
+	configDir        = "/config"
+
+	// volumeMountDir is where scanner volumes without a host path are mounted.
+	volumeMountDir = "/mnt/eraser"
+)
+
+// podSpec renders the pod spec of imageJob's pods from its spec and the
+// manager's configuration. It is filled in for each node by
+// copyAndFillTemplateSpec.
+func (r *Reconciler) podSpec(ctx context.Context, imageJob *eraserv1.ImageJob, eraserConfig *unversioned.EraserConfig) (*corev1.PodSpec, error) {
+	mgrCfg := &eraserConfig.Manager
+	compCfg := &eraserConfig.Components
+
+	pullSecrets := []corev1.LocalObjectReference{}
+	for _, secret := range mgrCfg.PullSecrets {
+		pullSecrets = append(pullSecrets, corev1.LocalObjectReference{Name: secret})
+	}
+
+	spec := &corev1.PodSpec{
+		ImagePullSecrets:   pullSecrets,
+		RestartPolicy:      corev1.RestartPolicyNever,
+		PriorityClassName:  mgrCfg.PriorityClassName,
+		ServiceAccountName: "eraser-imagejob-pods",
+	}
+
+	// the --remover-image flag of the manager overrides the configuration
+	removerImage := imageJob.Spec.RemoverImage
+	if removerImage == "" {
+		removerImage = *controllerUtils.RemoverImage
+	}
+
+	remover := corev1.Container{
+		Name:            removerContainer,
+		Image:           componentImage(removerImage, compCfg.Remover.Image),
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Args:            []string{"--log-level=" + logger.GetLevel()},
+		Resources:       resources(&compCfg.Remover),
+		SecurityContext: eraserUtils.SharedSecurityContext,
+		// env vars for exporting metrics
+		Env: append([]corev1.EnvVar{
+			{Name: "OTEL_SERVICE_NAME", Value: "remover"},
+		}, controllerUtils.GetRemoverMetricsEnv(&mgrCfg.Metrics)...),
+	}
+
+	switch imageJob.Spec.Mode {
+	case eraserv1.ModeRemove:
+		// the images are read from the ConfigMap created by ensureImageList
+		spec.Volumes = []corev1.Volume{{
+			Name: imgListVolume,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: imageJob.Name}},
+			},
+		}}
+		remover.Args = append(remover.Args, "--imagelist="+filepath.Join(imgListPath, "images"))
+		remover.VolumeMounts = []corev1.VolumeMount{{MountPath: imgListPath, Name: imgListVolume}}
+		spec.Containers = []corev1.Container{remover}
+	case eraserv1.ModeCollect:
+		scanDisabled := !imageJob.Spec.ScannerEnabled
+		profileArgs := []string{
+			"--enable-pprof=" + strconv.FormatBool(mgrCfg.Profile.Enabled),
+			fmt.Sprintf("--pprof-port=%d", mgrCfg.Profile.Port),
+		}
+		sharedData := corev1.VolumeMount{MountPath: sharedDataPath, Name: sharedDataVolume}
+
+		spec.Volumes = []corev1.Volume{
+			{
+				// EmptyDir default
+				Name: sharedDataVolume,
+			},
+			{
+				Name: configVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					ConfigMap: &corev1.ConfigMapVolumeSource{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: controllerUtils.EraserConfigmapName,
+						},
+					},
+				},
+			},
+		}
+
+		collector := corev1.Container{
+			Name:            collectorContainer,
+			Image:           componentImage(imageJob.Spec.CollectorImage, compCfg.Collector.Image),
+			ImagePullPolicy: corev1.PullIfNotPresent,
+			Args:            append([]string{"--scan-disabled=" + strconv.FormatBool(scanDisabled)}, profileArgs...),
+			VolumeMounts:    []corev1.VolumeMount{sharedData},
+			Resources:       resources(&compCfg.Collector.ContainerConfig),
+		}
+
+		remover.Args = append(remover.Args, "--scan-disabled="+strconv.FormatBool(scanDisabled))
+		remover.Args = append(remover.Args, profileArgs...)
+		remover.VolumeMounts = []corev1.VolumeMount{sharedData}
+		spec.Containers = []corev1.Container{collector, remover}
+
+		if !scanDisabled {
+			scanner := corev1.Container{
+				Name:  scannerContainer,
+				Image: componentImage(imageJob.Spec.ScannerImage, compCfg.Scanner.Image),
+				Args:  append([]string{"--config=" + filepath.Join(configDir, "controller_manager_config.yaml")}, profileArgs...),
+				VolumeMounts: []corev1.VolumeMount{
+					sharedData,
+					{MountPath: configDir, Name: configVolumeName},
+				},
+				Resources: resources(&compCfg.Scanner.ContainerConfig),
+				// env vars for exporting metrics
+				Env: []corev1.EnvVar{
+					{Name: "OTEL_SERVICE_NAME", Value: "trivy-scanner"},
+					{Name: "ERASER_RUNTIME_NAME", Value: string(mgrCfg.Runtime.Name)},
+				},
+			}
+
+			spec.Volumes = append(spec.Volumes, compCfg.Scanner.Volumes...)
+			scanner.VolumeMounts = append(scanner.VolumeMounts, volumeMounts(compCfg.Scanner.Volumes, true)...)
+			spec.Volumes = append(spec.Volumes, compCfg.Scanner.WritableVolumes...)
+			scanner.VolumeMounts = append(scanner.VolumeMounts, volumeMounts(compCfg.Scanner.WritableVolumes, false)...)
+
+			spec.Containers = append(spec.Containers, scanner)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported imagejob mode %q", imageJob.Spec.Mode)
+	}
+
+	configmapList := &corev1.ConfigMapList{}
+	if err := r.List(ctx, configmapList, client.InNamespace(eraserUtils.GetNamespace())); err != nil {
+		return nil, fmt.Errorf("list configmaps: %w", err)
+	}
+
+	exclusionMount, exclusionVolume, err := controllerUtils.GetExclusionVolume(configmapList)
+	if err != nil {
+		return nil, fmt.Errorf("get exclusion volumes: %w", err)
+	}
+
+	policyEnv, err := controllerUtils.GetImagePolicyEnv(ctx, r.Client)
+	if err != nil {
+		return nil, fmt.Errorf("get image policies: %w", err)
+	}
+
+	otlpEnv := controllerUtils.GetOTLPEnv(mgrCfg)
+	otlpMount, otlpVolume := controllerUtils.GetOTLPVolume(&mgrCfg.OTLP)
+
+	for i := range spec.Containers {
+		container := &spec.Containers[i]
+		container.VolumeMounts = append(container.VolumeMounts, exclusionMount...)
+		container.VolumeMounts = append(container.VolumeMounts, otlpMount...)
+		container.Env = append(container.Env, policyEnv...)
+		container.Env = append(container.Env, otlpEnv...)
+	}
+
+	spec.Volumes = append(spec.Volumes, exclusionVolume...)
+	spec.Volumes = append(spec.Volumes, otlpVolume...)
+
+	return spec, nil
+}
+
+// ensureImageList creates the ConfigMap which holds the images of a Remove
+// job. It is owned by the job, so that it is deleted with it.
+func (r *Reconciler) ensureImageList(ctx context.Context, imageJob *eraserv1.ImageJob) error {
+	images, err := json.Marshal(imageJob.Spec.Images)
+	if err != nil {
+		return fmt.Errorf("marshal images: %w", err)
+	}
+
+	configMap := corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      imageJob.Name,
+			Namespace: eraserUtils.GetNamespace(),
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(imageJob, eraserv1.GroupVersion.WithKind("ImageJob")),
+			},
+		},
+		Immutable: eraserUtils.BoolPtr(true),
+		Data:      map[string]string{"images": string(images)},
+	}
+
+	if err := r.Create(ctx, &configMap); err != nil && !errors.IsAlreadyExists(err) {
+		return fmt.Errorf("create configmap: %w", err)
+	}
+
+	return nil
+}
+
+// componentImage returns image, or the configured image if it is empty.
+func componentImage(image string, cfg unversioned.RepoTag) string {
+	if image != "" {
+		return image
+	}
+
+	return fmt.Sprintf("%s:%s", cfg.Repo, cfg.Tag)
+}
+
+func resources(cfg *unversioned.ContainerConfig) corev1.ResourceRequirements {
+	return corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			"cpu":    cfg.Request.CPU,
+			"memory": cfg.Request.Mem,
+		},
+		Limits: corev1.ResourceList{
+			"memory": cfg.Limit.Mem,
+		},
+	}
+}
+
+// volumeMounts mounts hostPath volumes at their host path, and
+// PersistentVolumeClaim and Secret volumes under volumeMountDir.
+func volumeMounts(volumes []corev1.Volume, readOnly bool) []corev1.VolumeMount {
+	mounts := []corev1.VolumeMount{}
+	for idx := range volumes {
+		volume := volumes[idx]
+
+		var mountPath string
+		switch {
+		case volume.HostPath != nil:
+			mountPath = volume.HostPath.Path
+		case volume.PersistentVolumeClaim != nil, volume.Secret != nil:
+			mountPath = filepath.Join(volumeMountDir, volume.Name)
+		default:
+			log.Error(fmt.Errorf("volume must be a hostPath, persistentVolumeClaim or secret"), "invalid volume", "volumeName", volume.Name)
+			continue
+		}
+
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      volume.Name,
+			MountPath: mountPath,
+			ReadOnly:  readOnly,
+		})
+	}
+
+	return mounts
+}
@@ -0,0 +1,129 @@
+package imagejob
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+	"github.com/eraser-dev/eraser/api/unversioned/config"
+	eraserv1 "github.com/eraser-dev/eraser/api/v1"
+)
+
+func TestComponentImage(t *testing.T) {
+	cfg := unversioned.RepoTag{Repo: "ghcr.io/eraser-dev/remover", Tag: "v1.0.0"}
+
+	tests := []struct {
+		desc     string
+		image    string
+		expected string
+	}{
+		{desc: "configured image", image: "", expected: "ghcr.io/eraser-dev/remover:v1.0.0"},
+		{desc: "override", image: "registry.local/remover:dev", expected: "registry.local/remover:dev"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			if actual := componentImage(tt.image, cfg); actual != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPodSpec(t *testing.T) {
+	cfg := config.Default()
+	cfg.Components.Remover.Image = unversioned.RepoTag{Repo: "remover", Tag: "v1"}
+	cfg.Components.Collector.Image = unversioned.RepoTag{Repo: "collector", Tag: "v1"}
+	cfg.Components.Scanner.Image = unversioned.RepoTag{Repo: "scanner", Tag: "v1"}
+
+	tests := []struct {
+		desc       string
+		spec       eraserv1.ImageJobSpec
+		containers map[string]string
+		volumes    []string
+		arg        string
+		wantErr    bool
+	}{
+		{
+			desc:       "remove",
+			spec:       eraserv1.ImageJobSpec{Mode: eraserv1.ModeRemove, Images: []string{"alpine:3.7.3"}},
+			containers: map[string]string{removerContainer: "remover:v1"},
+			volumes:    []string{imgListVolume},
+			arg:        "--imagelist=/run/eraser.sh/imagelist/images",
+		},
+		{
+			desc:       "collect without scanner",
+			spec:       eraserv1.ImageJobSpec{Mode: eraserv1.ModeCollect},
+			containers: map[string]string{collectorContainer: "collector:v1", removerContainer: "remover:v1"},
+			volumes:    []string{sharedDataVolume, configVolumeName},
+			arg:        "--scan-disabled=true",
+		},
+		{
+			desc:       "collect with scanner",
+			spec:       eraserv1.ImageJobSpec{Mode: eraserv1.ModeCollect, ScannerEnabled: true},
+			containers: map[string]string{collectorContainer: "collector:v1", removerContainer: "remover:v1", scannerContainer: "scanner:v1"},
+			volumes:    []string{sharedDataVolume, configVolumeName},
+			arg:        "--scan-disabled=false",
+		},
+		{
+			desc:       "image overrides",
+			spec:       eraserv1.ImageJobSpec{Mode: eraserv1.ModeCollect, ScannerEnabled: true, RemoverImage: "remover:dev", ScannerImage: "scanner:dev"},
+			containers: map[string]string{collectorContainer: "collector:v1", removerContainer: "remover:dev", scannerContainer: "scanner:dev"},
+			volumes:    []string{sharedDataVolume, configVolumeName},
+			arg:        "--scan-disabled=false",
+		},
+		{
+			desc:    "unsupported mode",
+			spec:    eraserv1.ImageJobSpec{Mode: "Prune"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			r := newTestReconciler(t, cfg)
+			job := &eraserv1.ImageJob{Spec: tt.spec}
+			job.Name = "imagejob-abc"
+
+			spec, err := r.podSpec(context.Background(), job, cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error: %v, got: %v", tt.wantErr, err)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if len(spec.Containers) != len(tt.containers) {
+				t.Fatalf("expected %d containers, got %d", len(tt.containers), len(spec.Containers))
+			}
+
+			var remover *corev1.Container
+			for i := range spec.Containers {
+				c := &spec.Containers[i]
+				if image, ok := tt.containers[c.Name]; !ok || c.Image != image {
+					t.Errorf("unexpected container %s with image %s", c.Name, c.Image)
+				}
+				if c.Name == removerContainer {
+					remover = c
+				}
+			}
+
+			if remover == nil || !strings.Contains(strings.Join(remover.Args, " "), tt.arg) {
+				t.Errorf("expected the remover's args to contain %s", tt.arg)
+			}
+
+			names := make([]string, 0, len(spec.Volumes))
+			for i := range spec.Volumes {
+				names = append(names, spec.Volumes[i].Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.volumes, ",") {
+				t.Errorf("expected volumes %v, got %v", tt.volumes, names)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package imagejob
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+	eraserv1 "github.com/eraser-dev/eraser/api/v1"
+	controllerUtils "github.com/eraser-dev/eraser/controllers/util"
+)
+
+// podCounts is the progress of a job's pods, as far as its rollout is
+// concerned.
+type podCounts struct {
+	active    int
+	succeeded int
+	failed    int
+	// lastFinish is when the last of the finished pods finished.
+	lastFinish time.Time
+}
+
+func countPods(pods []corev1.Pod) podCounts {
+	var counts podCounts
+	for i := range pods {
+		if !podsComplete(pods[i : i+1]) {
+			counts.active++
+			continue
+		}
+
+		if pods[i].Status.Phase == corev1.PodSucceeded {
+			counts.succeeded++
+		} else {
+			counts.failed++
+		}
+
+		if finish := nodeStatus(&pods[i]).FinishTime; finish != nil && finish.Time.After(counts.lastFinish) {
+			counts.lastFinish = finish.Time
+		}
+	}
+
+	return counts
+}
+
+// minFinishedPods is how many pods must have finished before the failure
+// ratio is checked while pods are still running. Between batches it is
+// always checked.
+const minFinishedPods = 10
+
+// failureRatioReached returns whether enough of the finished pods have
+// failed for the rollout to stop. While pods are running, it waits until
+// minFinishedPods of them have finished, so that the first failure, which
+// is often the first pod to finish, doesn't stop the rollout on its own.
+func failureRatioReached(rollout *unversioned.RolloutConfig, counts podCounts) bool {
+	finished := counts.succeeded + counts.failed
+	if rollout.StopOnFailureRatio <= 0 || finished == 0 {
+		return false
+	}
+
+	if counts.active > 0 && finished < minFinishedPods {
+		return false
+	}
+
+	return float64(counts.failed)/float64(finished) >= rollout.StopOnFailureRatio
+}
+
+// batchSize returns how many pods can start now, of a job which runs on
+// nodes nodes, or -1 if there is no limit. If it is 0 because of the pause
+// between batches, it also returns how long is left of the pause.
+func batchSize(rollout *unversioned.RolloutConfig, nodes int, counts podCounts, now time.Time) (int, time.Duration) {
+	size, err := intstr.GetScaledValueFromIntOrPercent(&rollout.BatchSize, nodes, true)
+	if err != nil || size <= 0 {
+		return -1, 0
+	}
+
+	// the previous batch is still running
+	if counts.active > 0 {
+		return 0, 0
+	}
+
+	if !counts.lastFinish.IsZero() {
+		if wait := counts.lastFinish.Add(time.Duration(rollout.PauseBetweenBatches)).Sub(now); wait > 0 {
+			return 0, wait
+		}
+	}
+
+	return size, 0
+}
+
+// nodePools counts the running pods of each node pool.
+type nodePools struct {
+	label  string
+	limit  int
+	active map[string]int
+}
+
+// nodePools returns the node pools of a rollout, with the pools of pods
+// counted against them.
+func (r *Reconciler) nodePools(ctx context.Context, rollout *unversioned.RolloutConfig, pods []corev1.Pod) (*nodePools, error) {
+	pools := &nodePools{label: rollout.NodePoolLabel, limit: rollout.NodePoolConcurrency, active: map[string]int{}}
+	if pools.label == "" || pools.limit <= 0 {
+		return pools, nil
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes); err != nil {
+		return nil, err
+	}
+
+	poolOf := make(map[string]string, len(nodes.Items))
+	for i := range nodes.Items {
+		poolOf[nodes.Items[i].Name] = nodes.Items[i].Labels[pools.label]
+	}
+
+	for i := range pods {
+		if !podsComplete(pods[i : i+1]) {
+			pools.active[poolOf[pods[i].Spec.NodeName]]++
+		}
+	}
+
+	return pools, nil
+}
+
+// full returns whether node's pool is running as many pods as it can. Nodes
+// without the pool label are not limited.
+func (p *nodePools) full(node *corev1.Node) bool {
+	pool := node.Labels[p.label]
+	return p.limit > 0 && pool != "" && p.active[pool] >= p.limit
+}
+
+func (p *nodePools) add(node *corev1.Node) {
+	p.active[node.Labels[p.label]]++
+}
+
+// stopRollout leaves imageJob's pending nodes out of it.
+func (r *Reconciler) stopRollout(imageJob *eraserv1.ImageJob, rollout *unversioned.RolloutConfig, counts podCounts) {
+	stopped := len(imageJob.Status.PendingNodes)
+	imageJob.Status.Stopped += stopped
+	imageJob.Status.PendingNodes = nil
+
+	log.Info("stopping rollout", "job", imageJob.Name, "failed", counts.failed, "succeeded", counts.succeeded, "stopped", stopped)
+	r.recorder.Eventf(imageJob, corev1.EventTypeWarning, controllerUtils.ReasonRolloutStopped,
+		"%d of %d finished pods failed, reaching the stop ratio of %v, so %d nodes were left out",
+		counts.failed, counts.failed+counts.succeeded, rollout.StopOnFailureRatio, stopped)
+}
@@ -0,0 +1,246 @@
+package imagejob
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+	"github.com/eraser-dev/eraser/api/unversioned/config"
+	eraserv1 "github.com/eraser-dev/eraser/api/v1"
+)
+
+var rolloutNow = time.Date(2023, time.June, 1, 10, 30, 0, 0, time.UTC)
+
+// testPod returns a pod on node in phase, whose container finished at
+// finishedAt if it isn't zero.
+func testPod(node string, phase corev1.PodPhase, finishedAt time.Time) corev1.Pod {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "eraser-" + node},
+		Spec:       corev1.PodSpec{NodeName: node},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+
+	if !finishedAt.IsZero() {
+		exitCode := int32(0)
+		if phase == corev1.PodFailed {
+			exitCode = 1
+		}
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+			State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode, FinishedAt: metav1.NewTime(finishedAt)}},
+		}}
+	}
+
+	return pod
+}
+
+func TestCountPods(t *testing.T) {
+	earlier := rolloutNow.Add(-10 * time.Minute)
+	later := rolloutNow.Add(-5 * time.Minute)
+
+	// the remover of a running pod has failed, which finishes the pod
+	failedContainer := testPod("node-d", corev1.PodRunning, later)
+	failedContainer.Status.ContainerStatuses[0].State.Terminated.ExitCode = 1
+
+	tests := []struct {
+		desc     string
+		pods     []corev1.Pod
+		expected podCounts
+	}{
+		{desc: "no pods"},
+		{
+			desc: "active and finished pods",
+			pods: []corev1.Pod{
+				testPod("node-a", corev1.PodPending, time.Time{}),
+				testPod("node-b", corev1.PodSucceeded, later),
+				testPod("node-c", corev1.PodFailed, earlier),
+			},
+			expected: podCounts{active: 1, succeeded: 1, failed: 1, lastFinish: later},
+		},
+		{
+			desc:     "running pod with a failed container",
+			pods:     []corev1.Pod{testPod("node-a", corev1.PodRunning, time.Time{}), failedContainer},
+			expected: podCounts{active: 1, failed: 1, lastFinish: later},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			if actual := countPods(tt.pods); actual != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFailureRatioReached(t *testing.T) {
+	half := unversioned.RolloutConfig{StopOnFailureRatio: 0.5}
+
+	tests := []struct {
+		desc     string
+		rollout  unversioned.RolloutConfig
+		counts   podCounts
+		expected bool
+	}{
+		{desc: "never stops", counts: podCounts{failed: 10}},
+		{desc: "nothing finished", rollout: half, counts: podCounts{active: 5}},
+		{desc: "below the ratio", rollout: half, counts: podCounts{succeeded: 3, failed: 1}},
+		{desc: "ratio reached between batches", rollout: half, counts: podCounts{succeeded: 1, failed: 1}, expected: true},
+		{desc: "first failure while pods run", rollout: half, counts: podCounts{active: 9, failed: 1}},
+		{desc: "too few finished while pods run", rollout: half, counts: podCounts{active: 5, succeeded: 4, failed: 5}},
+		{desc: "enough finished while pods run", rollout: half, counts: podCounts{active: 5, succeeded: 4, failed: 6}, expected: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			if actual := failureRatioReached(&tt.rollout, tt.counts); actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestBatchSize(t *testing.T) {
+	pause := unversioned.Duration(5 * time.Minute)
+
+	tests := []struct {
+		desc     string
+		rollout  unversioned.RolloutConfig
+		nodes    int
+		counts   podCounts
+		expected int
+		wait     time.Duration
+	}{
+		{desc: "unlimited", nodes: 10, expected: -1},
+		{desc: "number of nodes", rollout: unversioned.RolloutConfig{BatchSize: intstr.FromInt(3)}, nodes: 10, expected: 3},
+		{desc: "percentage rounded up", rollout: unversioned.RolloutConfig{BatchSize: intstr.FromString("25%")}, nodes: 10, expected: 3},
+		{desc: "percentage of a few nodes", rollout: unversioned.RolloutConfig{BatchSize: intstr.FromString("1%")}, nodes: 3, expected: 1},
+		{desc: "invalid percentage", rollout: unversioned.RolloutConfig{BatchSize: intstr.FromString("half")}, nodes: 10, expected: -1},
+		{
+			desc:    "previous batch running",
+			rollout: unversioned.RolloutConfig{BatchSize: intstr.FromInt(3)},
+			nodes:   10,
+			counts:  podCounts{active: 1, succeeded: 2},
+		},
+		{
+			desc:    "pausing",
+			rollout: unversioned.RolloutConfig{BatchSize: intstr.FromInt(3), PauseBetweenBatches: pause},
+			nodes:   10,
+			counts:  podCounts{succeeded: 3, lastFinish: rolloutNow.Add(-2 * time.Minute)},
+			wait:    3 * time.Minute,
+		},
+		{
+			desc:     "pause over",
+			rollout:  unversioned.RolloutConfig{BatchSize: intstr.FromInt(3), PauseBetweenBatches: pause},
+			nodes:    10,
+			counts:   podCounts{succeeded: 3, lastFinish: rolloutNow.Add(-5 * time.Minute)},
+			expected: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			size, wait := batchSize(&tt.rollout, tt.nodes, tt.counts, rolloutNow)
+			if size != tt.expected {
+				t.Errorf("expected a batch of %d, got %d", tt.expected, size)
+			}
+			if wait != tt.wait {
+				t.Errorf("expected to wait %s, got %s", tt.wait, wait)
+			}
+		})
+	}
+}
+
+func TestNodePools(t *testing.T) {
+	const label = "pool"
+	rollout := &unversioned.RolloutConfig{NodePoolLabel: label, NodePoolConcurrency: 1}
+
+	r := newTestReconciler(t, config.Default(),
+		testNode("node-a", "4", map[string]string{label: "a"}),
+		testNode("node-b", "4", map[string]string{label: "b"}),
+		testNode("node-c", "4", nil),
+	)
+
+	pools, err := r.nodePools(context.Background(), rollout, []corev1.Pod{
+		testPod("node-a", corev1.PodRunning, time.Time{}),
+		testPod("node-b", corev1.PodSucceeded, rolloutNow),
+		testPod("node-c", corev1.PodRunning, time.Time{}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		desc     string
+		node     *corev1.Node
+		expected bool
+	}{
+		{desc: "pool at its limit", node: testNode("node-a2", "4", map[string]string{label: "a"}), expected: true},
+		{desc: "pool whose pod finished", node: testNode("node-b2", "4", map[string]string{label: "b"})},
+		{desc: "pool without pods", node: testNode("node-d", "4", map[string]string{label: "d"})},
+		{desc: "node without a pool", node: testNode("node-c2", "4", nil)},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			if actual := pools.full(tt.node); actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+
+	pools.add(testNode("node-b2", "4", map[string]string{label: "b"}))
+	if !pools.full(testNode("node-b3", "4", map[string]string{label: "b"})) {
+		t.Error("expected the pool to be full after a pod was added")
+	}
+
+	unlimited, err := r.nodePools(context.Background(), &unversioned.RolloutConfig{NodePoolLabel: label}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unlimited.full(testNode("node-a2", "4", map[string]string{label: "a"})) {
+		t.Error("expected pools without a limit never to be full")
+	}
+}
+
+func TestStartPodsStopsRollout(t *testing.T) {
+	cfg := config.Default()
+	cfg.Manager.ImageJob.Rollout = unversioned.RolloutConfig{BatchSize: intstr.FromInt(2), StopOnFailureRatio: 0.5}
+
+	var nodes []client.Object
+	for _, name := range []string{"node-a", "node-b", "node-c", "node-d"} {
+		nodes = append(nodes, testNode(name, "4", nil))
+	}
+	r := newTestReconciler(t, cfg, nodes...)
+
+	job := &eraserv1.ImageJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "imagejob-abc"},
+		Spec:       eraserv1.ImageJobSpec{Mode: eraserv1.ModeRemove, Images: []string{"alpine:3.7.3"}},
+		Status:     eraserv1.ImageJobStatus{Desired: 4, PendingNodes: []string{"node-c", "node-d"}},
+	}
+	pods := []corev1.Pod{
+		testPod("node-a", corev1.PodSucceeded, rolloutNow),
+		testPod("node-b", corev1.PodFailed, rolloutNow),
+	}
+
+	started, _, err := r.startPods(context.Background(), job, pods)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(started) != 0 {
+		t.Errorf("expected no pods to start, got %d", len(started))
+	}
+	if job.Status.Stopped != 2 || len(job.Status.PendingNodes) != 0 {
+		t.Errorf("expected 2 stopped and no pending nodes, got %d and %v", job.Status.Stopped, job.Status.PendingNodes)
+	}
+}
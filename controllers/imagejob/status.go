@@ -0,0 +1,124 @@
+package imagejob
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	eraserv1 "github.com/eraser-dev/eraser/api/v1"
+	"github.com/eraser-dev/eraser/pkg/metrics"
+)
+
+const (
+	// maxNodeStatuses caps the nodes of a job's status, so that the status of
+	// a job on a large cluster stays well below the size limit of an object.
+	maxNodeStatuses = 100
+
+	// maxMessageLength caps the message of each node, since a container's
+	// termination message can be up to 4KiB.
+	maxMessageLength = 256
+)
+
+// setNodeStatuses sets the status of each of imageJob's nodes from its pods,
+// and returns whether they changed. If there are more than maxNodeStatuses
+// nodes, those whose pods succeeded are left out first.
+func setNodeStatuses(imageJob *eraserv1.ImageJob, pods []corev1.Pod) bool {
+	nodes := make([]eraserv1.NodeStatus, 0, len(pods))
+	for i := range pods {
+		nodes = append(nodes, nodeStatus(&pods[i]))
+	}
+
+	if len(nodes) > maxNodeStatuses {
+		sort.SliceStable(nodes, func(i, j int) bool {
+			return nodes[i].Phase != corev1.PodSucceeded && nodes[j].Phase == corev1.PodSucceeded
+		})
+		nodes = nodes[:maxNodeStatuses]
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+
+	if len(nodes) == 0 {
+		nodes = nil
+	}
+	if equality.Semantic.DeepEqual(nodes, imageJob.Status.Nodes) {
+		return false
+	}
+
+	imageJob.Status.Nodes = nodes
+	return true
+}
+
+// nodeStatus returns the status of the node which pod runs on.
+func nodeStatus(pod *corev1.Pod) eraserv1.NodeStatus {
+	status := eraserv1.NodeStatus{
+		Name:      pod.Spec.NodeName,
+		PodName:   pod.Name,
+		Phase:     pod.Status.Phase,
+		StartTime: pod.Status.StartTime,
+		Reason:    pod.Status.Reason,
+		Message:   pod.Status.Message,
+	}
+
+	// the pod's own reason, for example an eviction, explains it best, and
+	// then that of the first container to fail or wait
+	finished := pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed || containersFailed(pod)
+	for i := range pod.Status.ContainerStatuses {
+		state := pod.Status.ContainerStatuses[i].State
+
+		if terminated := state.Terminated; terminated != nil {
+			if finished && (status.FinishTime == nil || status.FinishTime.Before(&terminated.FinishedAt)) {
+				finishedAt := terminated.FinishedAt
+				status.FinishTime = &finishedAt
+			}
+
+			if terminated.ExitCode != 0 && status.Reason == "" {
+				status.Reason = terminated.Reason
+				status.Message = failureMessage(terminated)
+			}
+		}
+
+		if state.Waiting != nil && status.Reason == "" {
+			status.Reason = state.Waiting.Reason
+			status.Message = state.Waiting.Message
+		}
+	}
+
+	status.Message = truncate(status.Message, maxMessageLength)
+
+	report := metrics.PodReport(pod)
+	status.ImagesRemoved = report.ImagesRemoved
+	for _, removal := range report.Removals {
+		switch removal.Outcome {
+		case metrics.OutcomeExcluded, metrics.OutcomeRunning:
+			status.ImagesProtected += removal.Count
+		case metrics.OutcomeError:
+			status.ImagesFailed += removal.Count
+		}
+	}
+
+	return status
+}
+
+// failureMessage returns the termination message of a failed container, if
+// it is not one of the reports which are read by metrics.PodReport.
+func failureMessage(terminated *corev1.ContainerStateTerminated) string {
+	var report metrics.Report
+	if terminated.Message == "" || json.Unmarshal([]byte(terminated.Message), &report) == nil {
+		return ""
+	}
+
+	return terminated.Message
+}
+
+// truncate shortens s to at most n bytes, marking that it was cut off, and
+// without splitting a character.
+func truncate(s string, n int) string {
+	const ellipsis = "..."
+	if len(s) <= n {
+		return s
+	}
+
+	return strings.ToValidUTF8(s[:n-len(ellipsis)], "") + ellipsis
+}
@@ -0,0 +1,190 @@
+package imagejob
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eraserv1 "github.com/eraser-dev/eraser/api/v1"
+)
+
+func terminated(exitCode int32, reason, message string, finishedAt time.Time) corev1.ContainerStatus {
+	return corev1.ContainerStatus{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+		ExitCode:   exitCode,
+		Reason:     reason,
+		Message:    message,
+		FinishedAt: metav1.NewTime(finishedAt),
+	}}}
+}
+
+func TestNodeStatus(t *testing.T) {
+	finish := time.Date(2023, time.June, 1, 10, 0, 41, 0, time.UTC)
+	report := `{"imagesRemoved":12,"source":"remover","removals":[{"outcome":"removed","count":12},{"outcome":"excluded","count":2},{"outcome":"running","count":1},{"outcome":"error","count":4}]}`
+	long := strings.Repeat("é", maxMessageLength)
+
+	tests := []struct {
+		desc     string
+		pod      corev1.Pod
+		expected eraserv1.NodeStatus
+	}{
+		{
+			desc: "succeeded with a report",
+			pod: corev1.Pod{Status: corev1.PodStatus{
+				Phase:             corev1.PodSucceeded,
+				ContainerStatuses: []corev1.ContainerStatus{terminated(0, "Completed", report, finish)},
+			}},
+			expected: eraserv1.NodeStatus{
+				Phase:           corev1.PodSucceeded,
+				FinishTime:      &metav1.Time{Time: finish},
+				ImagesRemoved:   12,
+				ImagesProtected: 3,
+				ImagesFailed:    4,
+			},
+		},
+		{
+			desc: "failed container",
+			pod: corev1.Pod{Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{
+					{State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+					terminated(137, "OOMKilled", "out of memory", finish),
+				},
+			}},
+			expected: eraserv1.NodeStatus{
+				Phase:      corev1.PodRunning,
+				FinishTime: &metav1.Time{Time: finish},
+				Reason:     "OOMKilled",
+				Message:    "out of memory",
+			},
+		},
+		{
+			desc: "evicted",
+			pod: corev1.Pod{Status: corev1.PodStatus{
+				Phase:             corev1.PodFailed,
+				Reason:            "Evicted",
+				Message:           "The node was low on resource: ephemeral-storage.",
+				ContainerStatuses: []corev1.ContainerStatus{terminated(137, "Error", "killed", finish)},
+			}},
+			expected: eraserv1.NodeStatus{
+				Phase:      corev1.PodFailed,
+				FinishTime: &metav1.Time{Time: finish},
+				Reason:     "Evicted",
+				Message:    "The node was low on resource: ephemeral-storage.",
+			},
+		},
+		{
+			desc: "waiting",
+			pod: corev1.Pod{Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				ContainerStatuses: []corev1.ContainerStatus{{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+					Reason:  "ImagePullBackOff",
+					Message: "Back-off pulling image",
+				}}}},
+			}},
+			expected: eraserv1.NodeStatus{Phase: corev1.PodPending, Reason: "ImagePullBackOff", Message: "Back-off pulling image"},
+		},
+		{
+			desc: "long message",
+			pod: corev1.Pod{Status: corev1.PodStatus{
+				Phase:             corev1.PodFailed,
+				ContainerStatuses: []corev1.ContainerStatus{terminated(1, "Error", long, finish)},
+			}},
+			expected: eraserv1.NodeStatus{
+				Phase:      corev1.PodFailed,
+				FinishTime: &metav1.Time{Time: finish},
+				Reason:     "Error",
+				// 2 bytes for each é
+				Message: strings.Repeat("é", (maxMessageLength-3)/2) + "...",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			actual := nodeStatus(&tt.pod)
+			if actual.FinishTime.Equal(tt.expected.FinishTime) {
+				actual.FinishTime = tt.expected.FinishTime
+			}
+			if actual != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, actual)
+			}
+			if len(actual.Message) > maxMessageLength || !utf8.ValidString(actual.Message) {
+				t.Errorf("expected a valid message of at most %d bytes, got %d bytes", maxMessageLength, len(actual.Message))
+			}
+		})
+	}
+}
+
+func TestFailureMessage(t *testing.T) {
+	tests := []struct {
+		desc     string
+		message  string
+		expected string
+	}{
+		{desc: "no message"},
+		{desc: "report", message: `{"imagesRemoved":3}`},
+		{desc: "error", message: "failed to connect to containerd", expected: "failed to connect to containerd"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			if actual := failureMessage(&corev1.ContainerStateTerminated{ExitCode: 1, Message: tt.message}); actual != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSetNodeStatuses(t *testing.T) {
+	podOn := func(node string, phase corev1.PodPhase) corev1.Pod {
+		return corev1.Pod{Spec: corev1.PodSpec{NodeName: node}, Status: corev1.PodStatus{Phase: phase}}
+	}
+
+	job := &eraserv1.ImageJob{}
+	pods := []corev1.Pod{podOn("node-b", corev1.PodRunning), podOn("node-a", corev1.PodSucceeded)}
+
+	if !setNodeStatuses(job, pods) {
+		t.Error("expected the nodes to change")
+	}
+	if len(job.Status.Nodes) != 2 || job.Status.Nodes[0].Name != "node-a" {
+		t.Errorf("expected the nodes sorted by name, got %+v", job.Status.Nodes)
+	}
+	if setNodeStatuses(job, pods) {
+		t.Error("expected the nodes not to change")
+	}
+
+	// more nodes than are listed, of which a few failed
+	pods = nil
+	for i := 0; i < maxNodeStatuses+50; i++ {
+		phase := corev1.PodSucceeded
+		if i%40 == 0 {
+			phase = corev1.PodFailed
+		}
+		pods = append(pods, podOn(fmt.Sprintf("node-%03d", i), phase))
+	}
+
+	setNodeStatuses(job, pods)
+	if len(job.Status.Nodes) != maxNodeStatuses {
+		t.Fatalf("expected %d nodes, got %d", maxNodeStatuses, len(job.Status.Nodes))
+	}
+
+	failed := 0
+	for i := range job.Status.Nodes {
+		if job.Status.Nodes[i].Phase == corev1.PodFailed {
+			failed++
+		}
+		if i > 0 && job.Status.Nodes[i-1].Name >= job.Status.Nodes[i].Name {
+			t.Errorf("expected the nodes sorted by name, got %s before %s", job.Status.Nodes[i-1].Name, job.Status.Nodes[i].Name)
+		}
+	}
+	if failed != 4 {
+		t.Errorf("expected every failed node to be listed, got %d", failed)
+	}
+}
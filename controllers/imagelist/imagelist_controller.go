@@ -15,21 +15,22 @@ package imagelist
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/signal"
-	"path/filepath"
 	"syscall"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric/global"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -44,23 +45,19 @@ import (
 	"github.com/eraser-dev/eraser/api/unversioned/config"
 	eraserv1 "github.com/eraser-dev/eraser/api/v1"
 	"github.com/eraser-dev/eraser/controllers/util"
-	"github.com/eraser-dev/eraser/pkg/logger"
 	"github.com/eraser-dev/eraser/pkg/metrics"
-	eraserUtils "github.com/eraser-dev/eraser/pkg/utils"
+	"github.com/eraser-dev/eraser/pkg/tracing"
 
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 )
 
 const (
-	imgListPath     = "/run/eraser.sh/imagelist"
 	ownerLabelValue = "imagelist-controller"
 )
 
 var (
 	log        = logf.Log.WithName("controller").WithValues("process", "imagelist-controller")
-	imageList  = types.NamespacedName{Name: "imagelist"}
 	ownerLabel labels.Selector
-	startTime  time.Time
 	exporter   sdkmetric.Exporter
 	reader     sdkmetric.Reader
 	provider   *sdkmetric.MeterProvider
@@ -96,14 +93,24 @@ func newReconciler(mgr manager.Manager, cfg *config.Manager) (reconcile.Reconcil
 		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 		defer cancel()
 
-		exporter, reader, provider = metrics.ConfigureMetrics(ctx, log, otlpEndpoint)
-		global.SetMeterProvider(provider)
+		exporterCfg, err := util.GetOTLPExporterConfig(ctx, mgr.GetAPIReader(), &c.Manager)
+		if err != nil {
+			return nil, err
+		}
+
+		// the controller runs without exporting metrics rather than not at all
+		if exporter, reader, provider, err = metrics.ConfigureMetrics(ctx, exporterCfg); err != nil {
+			log.Error(err, "error configuring metrics")
+		} else {
+			global.SetMeterProvider(provider)
+		}
 	}
 
 	rec := &Reconciler{
 		Client:       mgr.GetClient(),
 		scheme:       mgr.GetScheme(),
 		eraserConfig: cfg,
+		recorder:     mgr.GetEventRecorderFor("imagelist-controller"),
 	}
 
 	return rec, nil
@@ -119,13 +126,15 @@ type Reconciler struct {
 	client.Client
 	scheme       *runtime.Scheme
 	eraserConfig *config.Manager
+	recorder     record.EventRecorder
 }
 
 //+kubebuilder:rbac:groups=eraser.sh,resources=imagelists,verbs=get;list;watch
-//+kubebuilder:rbac:groups="",namespace="system",resources=podtemplates,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=eraser.sh,resources=imagelists/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=eraser.sh,resources=imagepolicies,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",namespace="system",resources=pods,verbs=get;list;watch;update;create;delete
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -137,12 +146,8 @@ type Reconciler struct {
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.8.3/pkg/reconcile
 func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
-	// Ignore unsupported lists
-	if req.NamespacedName != imageList {
-		log.Info("Ignoring unsupported imagelist name", "name", req.Name)
-		return reconcile.Result{}, nil
-	}
-
+	// every ImageList gets its own ImageJob. The imagejob controller queues
+	// jobs which run on the same nodes.
 	imageList := eraserv1.ImageList{}
 	err := r.Get(ctx, req.NamespacedName, &imageList)
 	if err != nil {
@@ -168,6 +173,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			return r.handleJobListEvent(ctx, &imageList, &job)
 		}
 
+		// the list is scheduled when its job is, which may be queued behind
+		// other jobs
+		if util.CopyJobCondition(&imageList.Status.Conditions, imageList.Generation, &job, eraserv1.ConditionScheduled) {
+			if err := r.Status().Update(ctx, &imageList); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
 		// If we got here due to an update to the ImageList, and there is an ImageJob already running,
 		// keep requeueing it until that job is completed.
 		return ctrl.Result{RequeueAfter: time.Minute}, nil
@@ -194,10 +207,19 @@ func (r *Reconciler) handleJobListEvent(ctx context.Context, imageList *eraserv1
 		errDelay := time.Duration(cleanupCfg.DelayOnFailure)
 
 		if job.Status.DeleteAfter == nil {
+			// the job is only seen without DeleteAfter once, so this is
+			// recorded once per job
 			if job.Status.Phase == eraserv1.PhaseCompleted {
 				job.Status.DeleteAfter = util.After(time.Now(), int64(successDelay.Seconds()))
+				r.recorder.Eventf(imageList, corev1.EventTypeNormal, util.ReasonJobCompleted,
+					"ImageJob %s completed: %d pods succeeded, %d failed and %d nodes were skipped", job.Name, job.Status.Succeeded, job.Status.Failed, job.Status.Skipped)
 			} else if job.Status.Phase == eraserv1.PhaseFailed {
 				job.Status.DeleteAfter = util.After(time.Now(), int64(errDelay.Seconds()))
+				message := fmt.Sprintf("ImageJob %s failed: %d pods succeeded, %d failed and %d nodes were skipped", job.Name, job.Status.Succeeded, job.Status.Failed, job.Status.Skipped)
+				if job.Status.Stopped > 0 {
+					message += fmt.Sprintf(", and the rollout was stopped with %d nodes left out", job.Status.Stopped)
+				}
+				r.recorder.Event(imageList, corev1.EventTypeWarning, util.ReasonJobFailed, message)
 			}
 
 			if err := r.Status().Update(ctx, job); err != nil {
@@ -209,7 +231,7 @@ func (r *Reconciler) handleJobListEvent(ctx context.Context, imageList *eraserv1
 		otlpEndpoint := eraserConfig.Manager.OTLPEndpoint
 		if otlpEndpoint != "" {
 			// record metrics
-			if err := metrics.RecordMetricsController(ctx, global.MeterProvider(), float64(time.Since(startTime).Seconds()), int64(job.Status.Succeeded), int64(job.Status.Failed)); err != nil {
+			if err := metrics.RecordMetricsController(ctx, global.MeterProvider(), time.Since(job.CreationTimestamp.Time).Seconds(), int64(job.Status.Succeeded), int64(job.Status.Failed)); err != nil {
 				log.Error(err, "error recording metrics")
 			}
 			metrics.ExportMetrics(log, exporter, reader)
@@ -228,118 +250,21 @@ func (r *Reconciler) handleJobDeletion(ctx context.Context, job *eraserv1.ImageJ
 		return ctrl.Result{RequeueAfter: until}, nil
 	}
 
+	// the job's pods and ConfigMap are owned by it, and deleted with it
 	log.Info("Deleting imagejob", "job", job.Name)
 	err := r.Delete(ctx, job)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	template := corev1.PodTemplate{}
-	if err := r.Get(ctx,
-		types.NamespacedName{
-			Namespace: eraserUtils.GetNamespace(),
-			Name:      job.GetName(),
-		},
-		&template,
-	); err != nil {
-		return ctrl.Result{}, err
-	}
-
-	log.Info("Deleting pod template", "template", template.Name)
-	if err := r.Delete(ctx, &template); err != nil {
-		return ctrl.Result{}, err
-	}
-
 	return ctrl.Result{}, nil
 }
 
-func (r *Reconciler) handleImageListEvent(ctx context.Context, imageList *eraserv1.ImageList) (ctrl.Result, error) {
-	imgListJSON, err := json.Marshal(imageList.Spec.Images)
-	if err != nil {
-		return ctrl.Result{}, fmt.Errorf("marshal image list: %w", err)
-	}
-
-	configMap := corev1.ConfigMap{
-		ObjectMeta: metav1.ObjectMeta{
-			GenerateName: "imagelist-",
-			Namespace:    eraserUtils.GetNamespace(),
-		},
-		Immutable: eraserUtils.BoolPtr(true),
-		Data:      map[string]string{"images": string(imgListJSON)},
-	}
-	if err := r.Create(ctx, &configMap); err != nil {
-		return ctrl.Result{}, fmt.Errorf("create configmap: %w", err)
-	}
-
-	configName := configMap.Name
-	args := []string{
-		"--imagelist=" + filepath.Join(imgListPath, "images"),
-		"--log-level=" + logger.GetLevel(),
-	}
-
-	eraserConfig, err := r.eraserConfig.Read()
-	if err != nil {
-		return ctrl.Result{}, err
-	}
-
-	eraserContainerCfg := eraserConfig.Components.Remover
-	imageCfg := eraserContainerCfg.Image
-	image := fmt.Sprintf("%s:%s", imageCfg.Repo, imageCfg.Tag)
-
-	pullSecrets := []corev1.LocalObjectReference{}
-	for _, secret := range eraserConfig.Manager.PullSecrets {
-		pullSecrets = append(pullSecrets, corev1.LocalObjectReference{Name: secret})
-	}
-
-	jobTemplate := corev1.PodTemplateSpec{
-		Spec: corev1.PodSpec{
-			Volumes: []corev1.Volume{
-				{
-					Name: configName,
-					VolumeSource: corev1.VolumeSource{
-						ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: configName}},
-					},
-				},
-			},
-			ImagePullSecrets:  pullSecrets,
-			RestartPolicy:     corev1.RestartPolicyNever,
-			PriorityClassName: eraserConfig.Manager.PriorityClassName,
-			Containers: []corev1.Container{
-				{
-					Name:            "remover",
-					Image:           image,
-					ImagePullPolicy: corev1.PullIfNotPresent,
-					Args:            args,
-					VolumeMounts: []corev1.VolumeMount{
-						{MountPath: imgListPath, Name: configName},
-					},
-					Resources: corev1.ResourceRequirements{
-						Requests: corev1.ResourceList{
-							"cpu":    eraserContainerCfg.Request.CPU,
-							"memory": eraserContainerCfg.Request.Mem,
-						},
-						Limits: corev1.ResourceList{
-							"memory": eraserContainerCfg.Limit.Mem,
-						},
-					},
-					SecurityContext: eraserUtils.SharedSecurityContext,
-					// env vars for exporting metrics
-					Env: []corev1.EnvVar{
-						{
-							Name:  "OTEL_EXPORTER_OTLP_ENDPOINT",
-							Value: eraserConfig.Manager.OTLPEndpoint,
-						},
-						{
-							Name:  "OTEL_SERVICE_NAME",
-							Value: "remover",
-						},
-					},
-				},
-			},
-			ServiceAccountName: "eraser-imagejob-pods",
-		},
-	}
+func (r *Reconciler) handleImageListEvent(ctx context.Context, imageList *eraserv1.ImageList) (res ctrl.Result, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "imagelist.handleImageListEvent", trace.WithAttributes(attribute.Int("images", len(imageList.Spec.Images))))
+	defer func() { tracing.End(span, err) }()
 
+	// the imagejob controller renders the job's pods from its spec
 	job := &eraserv1.ImageJob{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: "imagejob-",
@@ -350,29 +275,19 @@ func (r *Reconciler) handleImageListEvent(ctx context.Context, imageList *eraser
 				*metav1.NewControllerRef(imageList, eraserv1.GroupVersion.WithKind("ImageList")),
 			},
 		},
+		Spec: eraserv1.ImageJobSpec{
+			Mode:   eraserv1.ModeRemove,
+			Images: imageList.Spec.Images,
+		},
 	}
 
-	configmapList := &corev1.ConfigMapList{}
-	if err := r.List(ctx, configmapList); err != nil {
-		log.Info("Could not get list of configmaps")
-		return reconcile.Result{}, err
-	}
-
-	exclusionMount, exclusionVolume, err := util.GetExclusionVolume(configmapList)
-	if err != nil {
-		log.Info("Could not get exclusion mounts and volumes")
-		return reconcile.Result{}, err
-	}
-
-	for i := range jobTemplate.Spec.Containers {
-		jobTemplate.Spec.Containers[i].VolumeMounts = append(jobTemplate.Spec.Containers[i].VolumeMounts, exclusionMount...)
+	// the imagejob controller continues the trace from here
+	if traceparent := tracing.Traceparent(ctx); traceparent != "" {
+		job.Annotations = map[string]string{tracing.TraceparentAnnotation: traceparent}
 	}
 
-	jobTemplate.Spec.Volumes = append(jobTemplate.Spec.Volumes, exclusionVolume...)
-
 	err = r.Create(ctx, job)
-	startTime = time.Now()
-	log.Info("creating imagejob", "job", job.Name)
+	log.Info("creating imagejob", "job", job.Name, "imagelist", imageList.Name)
 
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -381,36 +296,24 @@ func (r *Reconciler) handleImageListEvent(ctx context.Context, imageList *eraser
 		return reconcile.Result{}, err
 	}
 
-	// get manager pod with label control-plane=controller-manager
-	podList := corev1.PodList{}
-	if err = r.List(ctx, &podList, client.InNamespace(eraserUtils.GetNamespace()), client.MatchingLabels{"control-plane": "controller-manager"}); err != nil {
-		log.Info("Unable to list controller-manager pod")
-	}
-	if len(podList.Items) != 1 {
-		log.Info("Incorrect number of controller-manager pods", "number of pods", len(podList.Items))
-	}
-	managerPod := &podList.Items[0]
+	r.recorder.Eventf(imageList, corev1.EventTypeNormal, util.ReasonJobStarted, "Created ImageJob %s to remove %d images", job.Name, len(imageList.Spec.Images))
 
-	template := corev1.PodTemplate{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      job.GetName(),
-			Namespace: eraserUtils.GetNamespace(),
-			OwnerReferences: []metav1.OwnerReference{
-				*metav1.NewControllerRef(managerPod, managerPod.GroupVersionKind()),
-			},
-		},
-		Template: jobTemplate,
+	util.InitJobConditions(job, "Created for ImageList "+imageList.Name)
+	if err := r.Status().Update(ctx, job); err != nil {
+		// the imagejob controller has already set the job's conditions
+		log.V(1).Info("could not set initial conditions of imagejob", "job", job.Name, "error", err.Error())
 	}
 
-	err = r.Create(ctx, &template)
-	if err != nil {
-		return reconcile.Result{}, err
+	// the conditions of the previous job, if the list was updated, no longer apply
+	for _, conditionType := range []string{eraserv1.ConditionPodsCreated, eraserv1.ConditionSucceeded, eraserv1.ConditionDegraded, eraserv1.ConditionSuccessRatioNotMet} {
+		meta.RemoveStatusCondition(&imageList.Status.Conditions, conditionType)
 	}
-
-	configMap.ObjectMeta.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(job, eraserv1.GroupVersion.WithKind("ImageJob"))}
-	err = r.Update(ctx, &configMap)
-	if err != nil {
-		return reconcile.Result{}, err
+	// the job is not scheduled until the imagejob controller selects its
+	// nodes, and its Scheduled condition is mirrored until then
+	util.CopyJobCondition(&imageList.Status.Conditions, imageList.Generation, job, eraserv1.ConditionScheduled)
+	util.SetCondition(&imageList.Status.Conditions, imageList.Generation, eraserv1.ConditionProgressing, metav1.ConditionTrue, util.ReasonJobCreated, "Created ImageJob "+job.Name)
+	if err := r.Status().Update(ctx, imageList); err != nil {
+		return ctrl.Result{}, err
 	}
 
 	return ctrl.Result{}, nil
@@ -423,6 +326,7 @@ func (r *Reconciler) handleJobCompletion(ctx context.Context, imageList *eraserv
 	imageList.Status.Failed = int64(job.Status.Failed)
 	imageList.Status.Skipped = int64(job.Status.Skipped)
 	imageList.Status.Timestamp = &now
+	util.CopyJobConditions(&imageList.Status.Conditions, imageList.Generation, job)
 
 	err := r.Status().Update(ctx, imageList)
 	if err != nil {
@@ -455,11 +359,13 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 			DeleteFunc:  util.NeverOnDelete,
 			GenericFunc: util.NeverOnGeneric,
 			UpdateFunc: func(e event.UpdateEvent) bool {
-				if job, ok := e.ObjectNew.(*eraserv1.ImageJob); ok && util.IsCompletedOrFailed(job.Status.Phase) {
-					return ownerLabel.Matches(labels.Set(job.ObjectMeta.Labels))
+				job, ok := e.ObjectNew.(*eraserv1.ImageJob)
+				if !ok || !ownerLabel.Matches(labels.Set(job.ObjectMeta.Labels)) {
+					return false
 				}
 
-				return false
+				oldJob, ok := e.ObjectOld.(*eraserv1.ImageJob)
+				return util.IsCompletedOrFailed(job.Status.Phase) || (ok && scheduledChanged(oldJob, job))
 			},
 		},
 	)
@@ -469,3 +375,15 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 
 	return nil
 }
+
+// scheduledChanged returns whether the Scheduled condition of a job changed,
+// so that its ImageList can mirror it.
+func scheduledChanged(oldJob, newJob *eraserv1.ImageJob) bool {
+	oldCondition := meta.FindStatusCondition(oldJob.Status.Conditions, eraserv1.ConditionScheduled)
+	newCondition := meta.FindStatusCondition(newJob.Status.Conditions, eraserv1.ConditionScheduled)
+	if oldCondition == nil || newCondition == nil {
+		return oldCondition != newCondition
+	}
+
+	return oldCondition.Status != newCondition.Status || oldCondition.Reason != newCondition.Reason || oldCondition.Message != newCondition.Message
+}
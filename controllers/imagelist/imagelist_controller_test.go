@@ -0,0 +1,46 @@
+package imagelist
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eraserv1 "github.com/eraser-dev/eraser/api/v1"
+	"github.com/eraser-dev/eraser/controllers/util"
+)
+
+func TestScheduledChanged(t *testing.T) {
+	job := func(status metav1.ConditionStatus, reason, message string) *eraserv1.ImageJob {
+		j := &eraserv1.ImageJob{}
+		if reason != "" {
+			util.SetCondition(&j.Status.Conditions, 1, eraserv1.ConditionScheduled, status, reason, message)
+		}
+		return j
+	}
+
+	created := job(metav1.ConditionUnknown, util.ReasonJobCreated, "created")
+	queued := job(metav1.ConditionFalse, util.ReasonJobQueued, "waiting for job-a")
+
+	tests := []struct {
+		desc     string
+		oldJob   *eraserv1.ImageJob
+		newJob   *eraserv1.ImageJob
+		expected bool
+	}{
+		{desc: "no condition", oldJob: job("", "", ""), newJob: job("", "", "")},
+		{desc: "condition set", oldJob: job("", "", ""), newJob: created, expected: true},
+		{desc: "unchanged", oldJob: queued, newJob: job(metav1.ConditionFalse, util.ReasonJobQueued, "waiting for job-a")},
+		{desc: "queued", oldJob: created, newJob: queued, expected: true},
+		{desc: "queued behind another job", oldJob: queued, newJob: job(metav1.ConditionFalse, util.ReasonJobQueued, "waiting for job-b"), expected: true},
+		{desc: "scheduled", oldJob: queued, newJob: job(metav1.ConditionTrue, util.ReasonNodesSelected, "selected"), expected: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			if actual := scheduledChanged(tt.oldJob, tt.newJob); actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
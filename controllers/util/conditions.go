@@ -0,0 +1,75 @@
+package util
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eraserv1 "github.com/eraser-dev/eraser/api/v1"
+)
+
+// The reasons of the Conditions set by the controllers, alongside the Event
+// reasons which they share.
+const (
+	ReasonJobCreated         = "JobCreated"
+	ReasonNodesSelected      = "NodesSelected"
+	ReasonInvalidSpec        = "InvalidSpec"
+	ReasonPodsPending        = "PodsPending"
+	ReasonAllPodsCreated     = "AllPodsCreated"
+	ReasonPodsRunning        = "PodsRunning"
+	ReasonPodsFailed         = "PodsFailed"
+	ReasonNoPodsFailed       = "NoPodsFailed"
+	ReasonSuccessRatioMet    = "SuccessRatioMet"
+	ReasonSuccessRatioNotMet = "SuccessRatioNotMet"
+)
+
+// SetCondition sets the condition of type conditionType, observed at
+// generation, and returns whether it changed.
+func SetCondition(conditions *[]metav1.Condition, generation int64, conditionType string, status metav1.ConditionStatus, reason, message string) bool {
+	if c := meta.FindStatusCondition(*conditions, conditionType); c != nil &&
+		c.Status == status && c.Reason == reason && c.Message == message && c.ObservedGeneration == generation {
+		return false
+	}
+
+	meta.SetStatusCondition(conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: generation,
+	})
+	return true
+}
+
+// ConditionStatus returns the status of a condition which is true if b is.
+func ConditionStatus(b bool) metav1.ConditionStatus {
+	if b {
+		return metav1.ConditionTrue
+	}
+
+	return metav1.ConditionFalse
+}
+
+// InitJobConditions sets the conditions of a job which was just created, so
+// that it shows who created it until the imagejob controller schedules it.
+func InitJobConditions(job *eraserv1.ImageJob, message string) {
+	SetCondition(&job.Status.Conditions, job.Generation, eraserv1.ConditionScheduled, metav1.ConditionUnknown, ReasonJobCreated, message)
+}
+
+// CopyJobConditions copies the conditions of a job to the object which owns
+// it, such as its ImageList, observed at the owner's generation.
+func CopyJobConditions(conditions *[]metav1.Condition, generation int64, job *eraserv1.ImageJob) {
+	for i := range job.Status.Conditions {
+		CopyJobCondition(conditions, generation, job, job.Status.Conditions[i].Type)
+	}
+}
+
+// CopyJobCondition copies the condition of type conditionType of a job to
+// the object which owns it, and returns whether it changed.
+func CopyJobCondition(conditions *[]metav1.Condition, generation int64, job *eraserv1.ImageJob, conditionType string) bool {
+	c := meta.FindStatusCondition(job.Status.Conditions, conditionType)
+	if c == nil {
+		return false
+	}
+
+	return SetCondition(conditions, generation, c.Type, c.Status, c.Reason, c.Message)
+}
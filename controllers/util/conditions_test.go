@@ -0,0 +1,61 @@
+package util
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eraserv1 "github.com/eraser-dev/eraser/api/v1"
+)
+
+func TestSetCondition(t *testing.T) {
+	var conditions []metav1.Condition
+
+	if !SetCondition(&conditions, 1, eraserv1.ConditionScheduled, metav1.ConditionUnknown, ReasonJobCreated, "created") {
+		t.Error("expected a new condition to change")
+	}
+	created := conditions[0].LastTransitionTime
+	if created.IsZero() {
+		t.Error("expected the transition time to be set")
+	}
+
+	if SetCondition(&conditions, 1, eraserv1.ConditionScheduled, metav1.ConditionUnknown, ReasonJobCreated, "created") {
+		t.Error("expected the same condition not to change")
+	}
+
+	if !SetCondition(&conditions, 2, eraserv1.ConditionScheduled, metav1.ConditionUnknown, ReasonJobCreated, "created") {
+		t.Error("expected a new generation to change the condition")
+	}
+	if !conditions[0].LastTransitionTime.Equal(&created) {
+		t.Error("expected the transition time to be kept while the status is the same")
+	}
+
+	if !SetCondition(&conditions, 2, eraserv1.ConditionScheduled, metav1.ConditionTrue, ReasonNodesSelected, "selected") {
+		t.Error("expected a new status to change the condition")
+	}
+	if len(conditions) != 1 || conditions[0].Status != metav1.ConditionTrue || conditions[0].Reason != ReasonNodesSelected {
+		t.Errorf("expected the condition to be replaced, got %+v", conditions)
+	}
+}
+
+func TestCopyJobCondition(t *testing.T) {
+	job := &eraserv1.ImageJob{}
+	SetCondition(&job.Status.Conditions, 1, eraserv1.ConditionScheduled, metav1.ConditionFalse, ReasonJobQueued, "queued")
+
+	var conditions []metav1.Condition
+	SetCondition(&conditions, 3, eraserv1.ConditionProgressing, metav1.ConditionTrue, ReasonJobCreated, "created")
+
+	if CopyJobCondition(&conditions, 3, job, eraserv1.ConditionSucceeded) {
+		t.Error("expected a condition which the job does not have not to be copied")
+	}
+	if !CopyJobCondition(&conditions, 3, job, eraserv1.ConditionScheduled) {
+		t.Error("expected the condition to be copied")
+	}
+	if CopyJobCondition(&conditions, 3, job, eraserv1.ConditionScheduled) {
+		t.Error("expected the copied condition not to change")
+	}
+
+	if len(conditions) != 2 || conditions[1].Reason != ReasonJobQueued || conditions[1].ObservedGeneration != 3 {
+		t.Errorf("expected the job's condition at the owner's generation, got %+v", conditions)
+	}
+}
@@ -0,0 +1,27 @@
+package util
+
+// The reasons of the Events recorded by the controllers. Events about a run
+// are recorded on its ImageJob and, for manual removals, its ImageList, and
+// the outcome on each node is recorded on the Node.
+const (
+	ReasonJobQueued    = "JobQueued"
+	ReasonJobStarted   = "JobStarted"
+	ReasonJobCompleted = "JobCompleted"
+	ReasonJobFailed    = "JobFailed"
+	ReasonNodesSkipped = "NodesSkipped"
+	ReasonNodeSkipped  = "NodeSkipped"
+	// the rollout of a job stopped because too many of its pods failed
+	ReasonRolloutStopped = "RolloutStopped"
+
+	ReasonImagesRemoved      = "ImagesRemoved"
+	ReasonImageRemovalFailed = "ImageRemovalFailed"
+	ReasonJobPodFailed       = "JobPodFailed"
+
+	// recorded on the EraserConfig resource when the manager loads it
+	ReasonConfigLoaded  = "ConfigLoaded"
+	ReasonConfigInvalid = "ConfigInvalid"
+
+	// recorded on the manager's ConfigMap when the next collector run is
+	// scheduled, so that installs without the EraserConfig resource show it
+	ReasonNextRunScheduled = "NextRunScheduled"
+)
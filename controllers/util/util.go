@@ -1,15 +1,25 @@
 package util
 
 import (
+	"context"
+	"encoding/json"
 	"flag"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
 	"time"
 
+	"github.com/eraser-dev/eraser/api/unversioned"
 	eraserv1 "github.com/eraser-dev/eraser/api/v1"
+	"github.com/eraser-dev/eraser/pkg/metrics"
+	"github.com/eraser-dev/eraser/pkg/policy"
+	eraserUtils "github.com/eraser-dev/eraser/pkg/utils"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 )
 
@@ -32,6 +42,9 @@ const (
 	EnvVarContainerdNamespaceKey   = "CONTAINERD_NAMESPACE"
 	EnvVarContainerdNamespaceValue = "k8s.io"
 	CRIPath                        = "/run/cri/cri.sock"
+
+	otlpTLSVolumeName = "otlp-tls"
+	otlpTLSMountPath  = "/etc/eraser/otlp-tls"
 )
 
 func NeverOnCreate(_ event.CreateEvent) bool {
@@ -137,3 +150,147 @@ func GetExclusionVolume(configmapList *corev1.ConfigMapList) ([]corev1.VolumeMou
 
 	return exclusionMount, exclusionVolume, nil
 }
+
+// GetImagePolicyEnv collects the rules of every ImagePolicy in the cluster
+// and returns them as an environment variable for the eraser containers. It
+// returns no variables if there are no policies, in which case the built-in
+// removal behavior is used.
+func GetImagePolicyEnv(ctx context.Context, c client.Client) ([]corev1.EnvVar, error) {
+	policyList := &eraserv1.ImagePolicyList{}
+	if err := c.List(ctx, policyList); err != nil {
+		return nil, err
+	}
+
+	rules := []policy.Rule{}
+	for i := range policyList.Items {
+		p := policyList.Items[i]
+		for _, r := range p.Spec.Rules {
+			rules = append(rules, policy.Rule{
+				Name:       p.Name + "/" + r.Name,
+				Expression: r.Expression,
+			})
+		}
+	}
+
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	// fail here rather than in every pod if a rule does not compile
+	if _, err := policy.New(rules); err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	return []corev1.EnvVar{{Name: eraserUtils.EnvEraserImagePolicy, Value: string(b)}}, nil
+}
+
+// GetRemoverMetricsEnv returns the environment variables which configure the
+// removal metrics of the remover container.
+func GetRemoverMetricsEnv(cfg *unversioned.MetricsConfig) []corev1.EnvVar {
+	if !cfg.RepositoryLabels || cfg.MaxRepositories <= 0 {
+		return nil
+	}
+
+	return []corev1.EnvVar{{Name: eraserUtils.EnvEraserMetricsMaxRepositories, Value: strconv.Itoa(cfg.MaxRepositories)}}
+}
+
+// GetOTLPEnv returns the environment variables which configure the metrics
+// exporter of job containers. The CA and client certificate are read from the
+// volume returned by GetOTLPVolume, and the headers from their Secret.
+func GetOTLPEnv(mgr *unversioned.ManagerConfig) []corev1.EnvVar {
+	cfg := mgr.OTLP
+	env := []corev1.EnvVar{
+		{Name: metrics.EnvOTLPEndpoint, Value: mgr.OTLPEndpoint},
+		{Name: metrics.EnvOTLPProtocol, Value: cfg.Protocol},
+		{Name: metrics.EnvOTLPInsecure, Value: strconv.FormatBool(cfg.Insecure)},
+		{Name: metrics.EnvOTLPCompression, Value: cfg.Compression},
+	}
+
+	if cfg.ExportInterval > 0 {
+		env = append(env, corev1.EnvVar{Name: metrics.EnvMetricExportInterval, Value: strconv.FormatInt(time.Duration(cfg.ExportInterval).Milliseconds(), 10)})
+	}
+
+	if cfg.HeadersSecretName != "" {
+		env = append(env, corev1.EnvVar{Name: metrics.EnvOTLPHeaders, ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: cfg.HeadersSecretName},
+				Key:                  metrics.HeadersKey,
+			},
+		}})
+	}
+
+	if cfg.TLSSecretName != "" && !cfg.Insecure {
+		env = append(env,
+			corev1.EnvVar{Name: metrics.EnvOTLPCertificate, Value: filepath.Join(otlpTLSMountPath, metrics.TLSCAKey)},
+			corev1.EnvVar{Name: metrics.EnvOTLPClientCertificate, Value: filepath.Join(otlpTLSMountPath, metrics.TLSCertificateKey)},
+			corev1.EnvVar{Name: metrics.EnvOTLPClientKey, Value: filepath.Join(otlpTLSMountPath, metrics.TLSKeyKey)},
+		)
+	}
+
+	return env
+}
+
+// GetOTLPVolume returns the volume of the exporter's TLS Secret, if there is
+// one.
+func GetOTLPVolume(cfg *unversioned.OTLPConfig) ([]corev1.VolumeMount, []corev1.Volume) {
+	if cfg.TLSSecretName == "" || cfg.Insecure {
+		return nil, nil
+	}
+
+	mounts := []corev1.VolumeMount{{MountPath: otlpTLSMountPath, Name: otlpTLSVolumeName, ReadOnly: true}}
+	volumes := []corev1.Volume{{
+		Name: otlpTLSVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: cfg.TLSSecretName},
+		},
+	}}
+
+	return mounts, volumes
+}
+
+// GetOTLPExporterConfig returns the configuration of the manager's own
+// metrics exporter. The TLS and headers Secrets are read with r, since the
+// manager does not mount them.
+func GetOTLPExporterConfig(ctx context.Context, r client.Reader, mgr *unversioned.ManagerConfig) (metrics.ExporterConfig, error) {
+	cfg := mgr.OTLP
+	exporterCfg := metrics.ExporterConfig{
+		Endpoint:    mgr.OTLPEndpoint,
+		Protocol:    cfg.Protocol,
+		Insecure:    cfg.Insecure,
+		Compression: cfg.Compression,
+		Interval:    time.Duration(cfg.ExportInterval),
+	}
+
+	if cfg.HeadersSecretName != "" {
+		var secret corev1.Secret
+		if err := r.Get(ctx, client.ObjectKey{Namespace: eraserUtils.GetNamespace(), Name: cfg.HeadersSecretName}, &secret); err != nil {
+			return exporterCfg, fmt.Errorf("get OTLP headers secret: %w", err)
+		}
+
+		headers, err := metrics.ParseHeaders(string(secret.Data[metrics.HeadersKey]))
+		if err != nil {
+			return exporterCfg, fmt.Errorf("parse OTLP headers secret: %w", err)
+		}
+		exporterCfg.Headers = headers
+	}
+
+	if cfg.TLSSecretName == "" || cfg.Insecure {
+		return exporterCfg, nil
+	}
+
+	var secret corev1.Secret
+	if err := r.Get(ctx, client.ObjectKey{Namespace: eraserUtils.GetNamespace(), Name: cfg.TLSSecretName}, &secret); err != nil {
+		return exporterCfg, fmt.Errorf("get OTLP TLS secret: %w", err)
+	}
+
+	exporterCfg.CA = secret.Data[metrics.TLSCAKey]
+	exporterCfg.Certificate = secret.Data[metrics.TLSCertificateKey]
+	exporterCfg.Key = secret.Data[metrics.TLSKeyKey]
+
+	return exporterCfg, nil
+}
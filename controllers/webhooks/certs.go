@@ -0,0 +1,180 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	serviceName              = "eraser-webhook-service"
+	webhookConfigurationName = "eraser-validating-webhook-configuration"
+	// the EraserConfig CRD is converted between versions by the webhook server
+	eraserConfigCRDName = "eraserconfigs.eraser.sh"
+
+	// the certificates are replaced whenever the manager starts, so they
+	// only need to outlive it
+	certValidity   = 10 * 365 * 24 * time.Hour
+	injectInterval = 5 * time.Second
+)
+
+// generateCerts returns a new CA, and a serving certificate and key for the
+// webhook service in namespace signed by it, all PEM encoded.
+func generateCerts(namespace string, now time.Time) (caPEM, certPEM, keyPEM []byte, err error) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "eraser-webhook-ca"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(certValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("create CA certificate: %w", err)
+	}
+
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	host := fmt.Sprintf("%s.%s.svc", serviceName, namespace)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: host},
+		DNSNames:     []string{serviceName, serviceName + "." + namespace, host, host + ".cluster.local"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(certValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("create serving certificate: %w", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	caPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return caPEM, certPEM, keyPEM, nil
+}
+
+// writeCerts writes the serving certificate and key where the webhook server
+// reads them from.
+func writeCerts(dir, certName, keyName string, certPEM, keyPEM []byte) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, certName), certPEM, 0o600); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, keyName), keyPEM, 0o600)
+}
+
+// caInjector sets the CA bundle of Eraser's webhooks, and of the conversion
+// webhook of the EraserConfig CRD, to the CA which signed the manager's
+// serving certificate, so that the API server trusts it.
+type caInjector struct {
+	client   client.Client
+	reader   client.Reader
+	caBundle []byte
+	injected atomic.Bool
+}
+
+// Start injects the CA bundle, retrying until it succeeds or ctx is done.
+func (c *caInjector) Start(ctx context.Context) error {
+	err := wait.PollImmediateUntilWithContext(ctx, injectInterval, func(ctx context.Context) (bool, error) {
+		if err := c.inject(ctx); err != nil {
+			log.Error(err, "unable to inject the CA bundle of the webhooks, retrying")
+			return false, nil
+		}
+
+		log.Info("injected the CA bundle of the webhooks", "webhookConfiguration", webhookConfigurationName)
+		c.injected.Store(true)
+		return true, nil
+	})
+	if ctx.Err() != nil {
+		// the manager is stopping
+		return nil
+	}
+
+	return err
+}
+
+func (c *caInjector) inject(ctx context.Context) error {
+	webhookConfig := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	if err := c.reader.Get(ctx, types.NamespacedName{Name: webhookConfigurationName}, webhookConfig); err != nil {
+		return err
+	}
+
+	patch := client.MergeFromWithOptions(webhookConfig.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	for i := range webhookConfig.Webhooks {
+		webhookConfig.Webhooks[i].ClientConfig.CABundle = c.caBundle
+	}
+
+	if err := c.client.Patch(ctx, webhookConfig, patch); err != nil {
+		return err
+	}
+
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	if err := c.reader.Get(ctx, types.NamespacedName{Name: eraserConfigCRDName}, crd); err != nil {
+		// without the CRD, there is nothing to convert
+		return client.IgnoreNotFound(err)
+	}
+
+	conversion := crd.Spec.Conversion
+	if conversion == nil || conversion.Strategy != apiextensionsv1.WebhookConverter || conversion.Webhook == nil || conversion.Webhook.ClientConfig == nil {
+		return nil
+	}
+
+	patch = client.MergeFromWithOptions(crd.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	conversion.Webhook.ClientConfig.CABundle = c.caBundle
+	return c.client.Patch(ctx, crd, patch)
+}
+
+// check is a readiness check which fails until the CA bundle is injected.
+func (c *caInjector) check(_ *http.Request) error {
+	if !c.injected.Load() {
+		return fmt.Errorf("the CA bundle of the webhooks has not been injected")
+	}
+
+	return nil
+}
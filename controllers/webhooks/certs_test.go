@@ -0,0 +1,53 @@
+package webhooks
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGenerateCerts(t *testing.T) {
+	now := time.Date(2023, time.January, 31, 0, 0, 0, 0, time.UTC)
+	caPEM, certPEM, keyPEM, err := generateCerts("eraser-system", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	if err := writeCerts(dir, "tls.crt", "tls.key", certPEM, keyPEM); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tls.LoadX509KeyPair(filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")); err != nil {
+		t.Fatalf("expected the written certificate and key to be a pair: %v", err)
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caPEM) {
+		t.Fatal("expected the CA bundle to hold a certificate")
+	}
+
+	block, _ := pem.Decode(certPEM)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, host := range []string{"eraser-webhook-service.eraser-system.svc", "eraser-webhook-service.eraser-system.svc.cluster.local"} {
+		_, err := cert.Verify(x509.VerifyOptions{
+			DNSName:     host,
+			Roots:       roots,
+			CurrentTime: now.Add(365 * 24 * time.Hour),
+		})
+		if err != nil {
+			t.Errorf("expected the certificate to be valid for %s: %v", host, err)
+		}
+	}
+
+	if info, err := os.Stat(filepath.Join(dir, "tls.key")); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("expected the key to only be readable by its owner, got: %v, %v", info, err)
+	}
+}
@@ -0,0 +1,103 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/eraser-dev/eraser/api/unversioned/config"
+	"github.com/eraser-dev/eraser/controllers/eraserconfig"
+	controllerUtils "github.com/eraser-dev/eraser/controllers/util"
+	"github.com/eraser-dev/eraser/pkg/configfile"
+	"github.com/eraser-dev/eraser/pkg/utils"
+)
+
+//+kubebuilder:webhook:path=/validate-eraser-sh-eraserconfig,mutating=false,failurePolicy=fail,sideEffects=None,groups=eraser.sh,resources=eraserconfigs,verbs=create;update,versions=v1alpha1;v1alpha2;v1alpha3,name=veraserconfigresource.eraser.sh,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-eraser-config,mutating=false,failurePolicy=ignore,sideEffects=None,groups="",resources=configmaps,verbs=create;update,versions=v1,name=veraserconfig.eraser.sh,admissionReviewVersions=v1
+
+// ValidateEraserConfig parses the EraserConfig in the manager's
+// configuration file, rejecting unknown fields, and validates it.
+func ValidateEraserConfig(b []byte) field.ErrorList {
+	path := field.NewPath("data").Key(configfile.Key)
+
+	cfg, err := configfile.ParseStrict(b)
+	if err != nil {
+		return field.ErrorList{field.Invalid(path, "", err.Error())}
+	}
+
+	errs := config.Validate(cfg)
+	for _, err := range errs {
+		err.Field = path.String() + "." + err.Field
+	}
+
+	return errs
+}
+
+// configMapValidator validates the EraserConfig in the manager's ConfigMap.
+// The webhook only sees ConfigMaps in Eraser's namespace, and allows all but
+// the manager's.
+type configMapValidator struct {
+	decoder *admission.Decoder
+}
+
+func (v *configMapValidator) Handle(_ context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+	if req.Namespace != utils.GetNamespace() || req.Name != controllerUtils.EraserConfigmapName {
+		return admission.Allowed("")
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := v.decoder.Decode(req, configMap); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	b, ok := configMap.Data[configfile.Key]
+	if !ok {
+		return admission.Denied(fmt.Sprintf("%s must hold the EraserConfig in %s", controllerUtils.EraserConfigmapName, configfile.Key))
+	}
+
+	if errs := ValidateEraserConfig([]byte(b)); len(errs) > 0 {
+		return admission.Denied(errs.ToAggregate().Error())
+	}
+
+	return admission.Allowed("")
+}
+
+// InjectDecoder is called by the webhook server before Handle.
+func (v *configMapValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// eraserConfigValidator validates EraserConfig resources. It is sent them in
+// the version they were written in, so that the defaults of that version
+// are filled in before they are validated, as they are for the manager's
+// ConfigMap.
+type eraserConfigValidator struct{}
+
+func (v *eraserConfigValidator) Handle(_ context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+	if req.Name != eraserconfig.Name {
+		return admission.Denied(fmt.Sprintf("the manager only uses the EraserConfig named %s", eraserconfig.Name))
+	}
+
+	cfg, err := configfile.ParseStrict(req.Object.Raw)
+	if err != nil {
+		return admission.Denied(err.Error())
+	}
+
+	if errs := config.Validate(cfg); len(errs) > 0 {
+		return admission.Denied(errs.ToAggregate().Error())
+	}
+
+	return admission.Allowed("")
+}
@@ -0,0 +1,194 @@
+package webhooks
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func TestValidateEraserConfig(t *testing.T) {
+	tests := []struct {
+		desc     string
+		config   string
+		wantErrs []string
+	}{
+		{
+			desc:   "defaults",
+			config: "apiVersion: eraser.sh/v1alpha3\nkind: EraserConfig\n",
+		},
+		{
+			desc:   "older version",
+			config: "apiVersion: eraser.sh/v1alpha1\nkind: EraserConfig\nmanager:\n  scheduling:\n    repeatInterval: 1h\n",
+		},
+		{
+			desc:     "unknown apiVersion",
+			config:   "apiVersion: eraser.sh/v1\nkind: EraserConfig\n",
+			wantErrs: []string{"unknown apiVersion"},
+		},
+		{
+			desc:     "unknown field",
+			config:   "apiVersion: eraser.sh/v1alpha3\nkind: EraserConfig\nmanager:\n  schedulng:\n    repeatInterval: 1h\n",
+			wantErrs: []string{"schedulng"},
+		},
+		{
+			desc:     "invalid node filter selector",
+			config:   "apiVersion: eraser.sh/v1alpha3\nkind: EraserConfig\nmanager:\n  nodeFilter:\n    type: exclude\n    selectors:\n    - eraser.sh/cleanup.filter\n    - \"a b\"\n",
+			wantErrs: []string{"manager.nodeFilter.selectors[1]"},
+		},
+		{
+			desc:     "invalid node filter type",
+			config:   "apiVersion: eraser.sh/v1alpha3\nkind: EraserConfig\nmanager:\n  nodeFilter:\n    type: only\n",
+			wantErrs: []string{"manager.nodeFilter.type"},
+		},
+		{
+			desc:     "out of range ratios",
+			config:   "apiVersion: eraser.sh/v1alpha3\nkind: EraserConfig\nmanager:\n  imageJob:\n    successRatio: 1.5\n  tracing:\n    sampleRatio: -1\n",
+			wantErrs: []string{"manager.tracing.sampleRatio", "manager.imageJob.successRatio"},
+		},
+		{
+			desc:     "zero repeat interval",
+			config:   "apiVersion: eraser.sh/v1alpha3\nkind: EraserConfig\nmanager:\n  scheduling:\n    repeatInterval: 0s\n",
+			wantErrs: []string{"manager.scheduling.repeatInterval"},
+		},
+		{
+			desc:   "cron with maintenance windows",
+			config: "apiVersion: eraser.sh/v1alpha3\nkind: EraserConfig\nmanager:\n  scheduling:\n    cron: \"0 2 * * *\"\n    timeZone: Europe/Berlin\n    blockedWindows:\n    - cron: \"0 9 * * 1-5\"\n      duration: 8h\n",
+		},
+		{
+			desc:     "invalid cron expressions",
+			config:   "apiVersion: eraser.sh/v1alpha3\nkind: EraserConfig\nmanager:\n  scheduling:\n    cron: \"0 2 * *\"\n    allowedWindows:\n    - cron: \"@every 1h\"\n      duration: 0s\n",
+			wantErrs: []string{"manager.scheduling.cron", "manager.scheduling.allowedWindows[0].cron", "manager.scheduling.allowedWindows[0].duration"},
+		},
+		{
+			desc:     "unknown time zone",
+			config:   "apiVersion: eraser.sh/v1alpha3\nkind: EraserConfig\nmanager:\n  scheduling:\n    cron: \"0 2 * * *\"\n    timeZone: Mars/Olympus_Mons\n",
+			wantErrs: []string{"manager.scheduling.timeZone"},
+		},
+		{
+			desc:     "windows which never permit a run",
+			config:   "apiVersion: eraser.sh/v1alpha3\nkind: EraserConfig\nmanager:\n  scheduling:\n    allowedWindows:\n    - cron: \"0 9 * * 1-5\"\n      duration: 8h\n    blockedWindows:\n    - cron: \"0 0 * * *\"\n      duration: 24h\n",
+			wantErrs: []string{"manager.scheduling: Forbidden"},
+		},
+		{
+			desc:   "staggered rollout",
+			config: "apiVersion: eraser.sh/v1alpha3\nkind: EraserConfig\nmanager:\n  imageJob:\n    rollout:\n      batchSize: 25%\n      pauseBetweenBatches: 5m\n      nodePoolLabel: cloud.google.com/gke-nodepool\n      nodePoolConcurrency: 2\n      stopOnFailureRatio: 0.5\n",
+		},
+		{
+			desc:     "invalid rollout",
+			config:   "apiVersion: eraser.sh/v1alpha3\nkind: EraserConfig\nmanager:\n  imageJob:\n    rollout:\n      batchSize: half\n      nodePoolConcurrency: 2\n      stopOnFailureRatio: 2\n",
+			wantErrs: []string{"manager.imageJob.rollout.batchSize", "manager.imageJob.rollout.nodePoolLabel", "manager.imageJob.rollout.stopOnFailureRatio"},
+		},
+		{
+			desc:     "invalid allowed image",
+			config:   "apiVersion: eraser.sh/v1alpha3\nkind: EraserConfig\nmanager:\n  imageJob:\n    allowedImages:\n    - ghcr.io/eraser-dev/remover:v1.3.0\n    - \"Remover:latest\"\n",
+			wantErrs: []string{"manager.imageJob.allowedImages[1]"},
+		},
+		{
+			desc:     "unsupported otlp protocol",
+			config:   "apiVersion: eraser.sh/v1alpha3\nkind: EraserConfig\nmanager:\n  otlp:\n    protocol: http/json\n",
+			wantErrs: []string{"manager.otlp.protocol"},
+		},
+		{
+			desc:     "invalid pull secret",
+			config:   "apiVersion: eraser.sh/v1alpha3\nkind: EraserConfig\nmanager:\n  pullSecrets:\n  - My_Secret\n",
+			wantErrs: []string{"manager.pullSecrets[0]"},
+		},
+		{
+			desc:     "missing image of enabled component",
+			config:   "apiVersion: eraser.sh/v1alpha3\nkind: EraserConfig\ncomponents:\n  scanner:\n    enabled: true\n    image:\n      repo: \"\"\n",
+			wantErrs: []string{"components.scanner.image.repo"},
+		},
+		{
+			desc:   "missing image of disabled component",
+			config: "apiVersion: eraser.sh/v1alpha3\nkind: EraserConfig\ncomponents:\n  scanner:\n    enabled: false\n    image:\n      repo: \"\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			errs := ValidateEraserConfig([]byte(tt.config))
+			if len(errs) != len(tt.wantErrs) {
+				t.Fatalf("expected %d errors, got %d: %v", len(tt.wantErrs), len(errs), errs)
+			}
+
+			for i, want := range tt.wantErrs {
+				if got := errs[i].Error(); !strings.Contains(got, want) {
+					t.Errorf("expected error %d to contain %q, got: %s", i, want, got)
+				}
+				if !strings.HasPrefix(errs[i].Field, "data[controller_manager_config.yaml]") {
+					t.Errorf("expected error %d to be in the configuration file, got: %s", i, errs[i].Field)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateEraserConfigDefault(t *testing.T) {
+	b, err := os.ReadFile("../../config/manager/controller_manager_config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if errs := ValidateEraserConfig(b); len(errs) > 0 {
+		t.Errorf("expected the default configuration to be valid, got: %v", errs)
+	}
+}
+
+func TestEraserConfigValidator(t *testing.T) {
+	tests := []struct {
+		desc    string
+		name    string
+		object  string
+		allowed bool
+	}{
+		{
+			desc:    "valid",
+			name:    "eraser-config",
+			object:  `{"apiVersion":"eraser.sh/v1alpha3","kind":"EraserConfig","metadata":{"name":"eraser-config"},"manager":{"scheduling":{"repeatInterval":"1h"}}}`,
+			allowed: true,
+		},
+		{
+			desc:    "older version",
+			name:    "eraser-config",
+			object:  `{"apiVersion":"eraser.sh/v1alpha1","kind":"EraserConfig","metadata":{"name":"eraser-config"},"components":{"eraser":{"image":{"repo":"remover"}}}}`,
+			allowed: true,
+		},
+		{
+			desc:   "other name",
+			name:   "my-config",
+			object: `{"apiVersion":"eraser.sh/v1alpha3","kind":"EraserConfig","metadata":{"name":"my-config"}}`,
+		},
+		{
+			desc:   "invalid",
+			name:   "eraser-config",
+			object: `{"apiVersion":"eraser.sh/v1alpha3","kind":"EraserConfig","metadata":{"name":"eraser-config"},"manager":{"nodeFilter":{"type":"exclude","selectors":["a b"]}}}`,
+		},
+		{
+			desc:   "unknown field",
+			name:   "eraser-config",
+			object: `{"apiVersion":"eraser.sh/v1alpha3","kind":"EraserConfig","metadata":{"name":"eraser-config"},"manager":{"schedulng":{}}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				Name:      tt.name,
+				Operation: admissionv1.Create,
+				Object:    runtime.RawExtension{Raw: []byte(tt.object)},
+			}}
+
+			resp := (&eraserConfigValidator{}).Handle(context.Background(), req)
+			if resp.Allowed != tt.allowed {
+				t.Errorf("expected allowed: %v, got: %v (%s)", tt.allowed, resp.Allowed, resp.Result.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/eraser-dev/eraser/api/unversioned/config"
+	eraserv1 "github.com/eraser-dev/eraser/api/v1"
+	"github.com/eraser-dev/eraser/controllers/imagejob"
+)
+
+//+kubebuilder:webhook:path=/validate-eraser-sh-v1-imagejob,mutating=false,failurePolicy=fail,sideEffects=None,groups=eraser.sh,resources=imagejobs,verbs=create;update,versions=v1,name=vimagejob.eraser.sh,admissionReviewVersions=v1
+
+// imageJobValidator rejects ImageJobs which imagejob.ValidateSpec finds
+// invalid with the manager's current configuration, most importantly those
+// which run component images that aren't allowed.
+type imageJobValidator struct {
+	config *config.Manager
+}
+
+func (v *imageJobValidator) ValidateCreate(_ context.Context, obj runtime.Object) error {
+	return v.validate(obj)
+}
+
+func (v *imageJobValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) error {
+	return v.validate(newObj)
+}
+
+func (v *imageJobValidator) ValidateDelete(context.Context, runtime.Object) error {
+	return nil
+}
+
+func (v *imageJobValidator) validate(obj runtime.Object) error {
+	job, ok := obj.(*eraserv1.ImageJob)
+	if !ok {
+		return fmt.Errorf("expected an ImageJob, got %T", obj)
+	}
+
+	cfg, err := v.config.Read()
+	if err != nil {
+		return fmt.Errorf("read configuration: %w", err)
+	}
+
+	if errs := imagejob.ValidateSpec(&job.Spec, &cfg.Manager.ImageJob); len(errs) > 0 {
+		return apierrors.NewInvalid(eraserv1.GroupVersion.WithKind("ImageJob").GroupKind(), job.Name, errs)
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/eraser-dev/eraser/api/unversioned/config"
+	eraserv1 "github.com/eraser-dev/eraser/api/v1"
+)
+
+func TestImageJobValidator(t *testing.T) {
+	cfg := config.Default()
+	cfg.Manager.ImageJob.AllowedImages = []string{"registry.local/remover:dev"}
+	v := &imageJobValidator{config: config.NewManager(cfg)}
+
+	tests := []struct {
+		desc    string
+		spec    eraserv1.ImageJobSpec
+		allowed bool
+	}{
+		{desc: "configured images", spec: eraserv1.ImageJobSpec{Mode: eraserv1.ModeCollect}, allowed: true},
+		{desc: "allowed override", spec: eraserv1.ImageJobSpec{Mode: eraserv1.ModeCollect, RemoverImage: "registry.local/remover:dev"}, allowed: true},
+		{desc: "override which is not allowed", spec: eraserv1.ImageJobSpec{Mode: eraserv1.ModeCollect, ScannerImage: "attacker/scanner"}},
+		{desc: "remove without images", spec: eraserv1.ImageJobSpec{Mode: eraserv1.ModeRemove}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			job := &eraserv1.ImageJob{ObjectMeta: metav1.ObjectMeta{Name: "imagejob-abc"}, Spec: tt.spec}
+
+			if err := v.ValidateCreate(context.Background(), job); (err == nil) != tt.allowed {
+				t.Errorf("expected allowed: %v, got: %v", tt.allowed, err)
+			}
+			if err := v.ValidateUpdate(context.Background(), job, job); (err == nil) != tt.allowed {
+				t.Errorf("expected update allowed: %v, got: %v", tt.allowed, err)
+			}
+		})
+	}
+}
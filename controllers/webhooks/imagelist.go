@@ -0,0 +1,86 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/distribution/reference"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	eraserv1 "github.com/eraser-dev/eraser/api/v1"
+)
+
+//+kubebuilder:webhook:path=/validate-eraser-sh-v1-imagelist,mutating=false,failurePolicy=fail,sideEffects=None,groups=eraser.sh,resources=imagelists,verbs=create;update,versions=v1,name=vimagelist.eraser.sh,admissionReviewVersions=v1
+
+// ValidateImageList checks that each of an ImageList's images is an image
+// reference, digest or ID, or PruneAll, which must be confirmed with
+// ConfirmPruneAnnotation.
+func ValidateImageList(imageList *eraserv1.ImageList) field.ErrorList {
+	var errs field.ErrorList
+	path := field.NewPath("spec", "images")
+
+	if len(imageList.Spec.Images) == 0 {
+		return append(errs, field.Required(path, "at least one image must be listed"))
+	}
+
+	seen := make(map[string]bool, len(imageList.Spec.Images))
+	for i, image := range imageList.Spec.Images {
+		imagePath := path.Index(i)
+
+		if seen[image] {
+			errs = append(errs, field.Duplicate(imagePath, image))
+			continue
+		}
+		seen[image] = true
+
+		switch {
+		case image == eraserv1.PruneAll:
+			if imageList.Annotations[eraserv1.ConfirmPruneAnnotation] != "true" {
+				errs = append(errs, field.Forbidden(imagePath, fmt.Sprintf(
+					"%q removes every non-running image from every node; set the %s annotation to \"true\" to confirm",
+					eraserv1.PruneAll, eraserv1.ConfirmPruneAnnotation)))
+			}
+		case strings.Contains(image, "*"):
+			errs = append(errs, field.Invalid(imagePath, image, fmt.Sprintf(
+				"wildcards are only supported in exclusion lists; use %q on its own to remove every non-running image", eraserv1.PruneAll)))
+		default:
+			if _, err := reference.ParseAnyReference(image); err != nil {
+				errs = append(errs, field.Invalid(imagePath, image, err.Error()))
+			}
+		}
+	}
+
+	return errs
+}
+
+// imageListValidator rejects ImageLists which ValidateImageList finds
+// invalid.
+type imageListValidator struct{}
+
+func (v *imageListValidator) ValidateCreate(_ context.Context, obj runtime.Object) error {
+	return v.validate(obj)
+}
+
+func (v *imageListValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) error {
+	return v.validate(newObj)
+}
+
+func (v *imageListValidator) ValidateDelete(context.Context, runtime.Object) error {
+	return nil
+}
+
+func (v *imageListValidator) validate(obj runtime.Object) error {
+	imageList, ok := obj.(*eraserv1.ImageList)
+	if !ok {
+		return fmt.Errorf("expected an ImageList, got %T", obj)
+	}
+
+	if errs := ValidateImageList(imageList); len(errs) > 0 {
+		return apierrors.NewInvalid(eraserv1.GroupVersion.WithKind("ImageList").GroupKind(), imageList.Name, errs)
+	}
+
+	return nil
+}
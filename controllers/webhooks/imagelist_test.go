@@ -0,0 +1,70 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eraserv1 "github.com/eraser-dev/eraser/api/v1"
+)
+
+func TestValidateImageList(t *testing.T) {
+	confirmed := map[string]string{eraserv1.ConfirmPruneAnnotation: "true"}
+
+	tests := []struct {
+		desc        string
+		images      []string
+		annotations map[string]string
+		wantErrs    int
+	}{
+		{desc: "tagged reference", images: []string{"docker.io/library/alpine:3.7.3"}},
+		{desc: "short name", images: []string{"alpine"}},
+		{desc: "digest reference", images: []string{"alpine@sha256:" + digest}},
+		{desc: "image ID", images: []string{"sha256:" + digest}},
+		{desc: "bare digest", images: []string{digest}},
+		{desc: "confirmed prune", images: []string{"*"}, annotations: confirmed},
+		{desc: "empty", images: nil, wantErrs: 1},
+		{desc: "unconfirmed prune", images: []string{"*"}, wantErrs: 1},
+		{desc: "prune confirmed with another value", images: []string{"*"}, annotations: map[string]string{eraserv1.ConfirmPruneAnnotation: "yes"}, wantErrs: 1},
+		{desc: "wildcard pattern", images: []string{"docker.io/library/*"}, annotations: confirmed, wantErrs: 1},
+		{desc: "upper case repository", images: []string{"docker.io/library/Alpine:3.7.3"}, wantErrs: 1},
+		{desc: "invalid tag", images: []string{"alpine:3.7.3:latest"}, wantErrs: 1},
+		{desc: "duplicate", images: []string{"alpine", "nginx", "alpine"}, wantErrs: 1},
+		{desc: "each invalid entry", images: []string{"Alpine", "alpine", "*", "nginx:"}, wantErrs: 3},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			imageList := &eraserv1.ImageList{
+				ObjectMeta: metav1.ObjectMeta{Name: "imagelist", Annotations: tt.annotations},
+				Spec:       eraserv1.ImageListSpec{Images: tt.images},
+			}
+
+			errs := ValidateImageList(imageList)
+			if len(errs) != tt.wantErrs {
+				t.Errorf("expected %d errors, got %d: %v", tt.wantErrs, len(errs), errs)
+			}
+		})
+	}
+}
+
+func TestImageListValidator(t *testing.T) {
+	v := &imageListValidator{}
+	imageList := &eraserv1.ImageList{
+		ObjectMeta: metav1.ObjectMeta{Name: "imagelist"},
+		Spec:       eraserv1.ImageListSpec{Images: []string{"*"}},
+	}
+
+	if err := v.ValidateCreate(context.Background(), imageList); err == nil {
+		t.Error("expected an unconfirmed prune to be rejected")
+	}
+
+	imageList.Annotations = map[string]string{eraserv1.ConfirmPruneAnnotation: "true"}
+	if err := v.ValidateUpdate(context.Background(), nil, imageList); err != nil {
+		t.Errorf("expected a confirmed prune to be allowed, got: %v", err)
+	}
+}
+
+const digest = "a3ed95caeb02ffe68cdd9fd84406680ae93d633cb16422d00e8a7c22955b46d4"
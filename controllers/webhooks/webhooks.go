@@ -0,0 +1,84 @@
+// Package webhooks serves the admission webhooks which validate ImageLists,
+// ImageJobs and EraserConfigs, whether in the manager's ConfigMap or a resource, so
+// that mistakes are rejected when they are applied rather than when a job
+// runs. It also converts EraserConfig resources between versions.
+package webhooks
+
+import (
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/eraser-dev/eraser/api/unversioned/config"
+	eraserv1 "github.com/eraser-dev/eraser/api/v1"
+	"github.com/eraser-dev/eraser/api/v1alpha3"
+	"github.com/eraser-dev/eraser/pkg/utils"
+)
+
+const (
+	eraserConfigPath         = "/validate-eraser-config"
+	eraserConfigResourcePath = "/validate-eraser-sh-eraserconfig"
+)
+
+var log = logf.Log.WithName("webhooks")
+
+//+kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations,resourceNames=eraser-validating-webhook-configuration,verbs=get;patch
+//+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,resourceNames=eraserconfigs.eraser.sh,verbs=get;patch
+
+// Add registers the webhooks with the manager's webhook server, and gives it
+// a serving certificate signed by a CA which is injected into the webhooks'
+// configuration once the manager starts.
+func Add(mgr manager.Manager, cfg *config.Manager) error {
+	err := ctrl.NewWebhookManagedBy(mgr).
+		For(&eraserv1.ImageList{}).
+		WithValidator(&imageListValidator{}).
+		Complete()
+	if err != nil {
+		return err
+	}
+
+	err = ctrl.NewWebhookManagedBy(mgr).
+		For(&eraserv1.ImageJob{}).
+		WithValidator(&imageJobValidator{config: cfg}).
+		Complete()
+	if err != nil {
+		return err
+	}
+
+	// v1alpha3 is the hub which the other versions of EraserConfig are
+	// converted through, so this registers the conversion webhook
+	err = ctrl.NewWebhookManagedBy(mgr).
+		For(&v1alpha3.EraserConfig{}).
+		Complete()
+	if err != nil {
+		return err
+	}
+
+	server := mgr.GetWebhookServer()
+	server.Register(eraserConfigPath, &webhook.Admission{Handler: &configMapValidator{}})
+	server.Register(eraserConfigResourcePath, &webhook.Admission{Handler: &eraserConfigValidator{}})
+
+	caPEM, certPEM, keyPEM, err := generateCerts(utils.GetNamespace(), time.Now())
+	if err != nil {
+		return err
+	}
+
+	// the certificate directory and file names are defaulted by Register
+	if err := writeCerts(server.CertDir, server.CertName, server.KeyName, certPEM, keyPEM); err != nil {
+		return err
+	}
+
+	injector := &caInjector{client: mgr.GetClient(), reader: mgr.GetAPIReader(), caBundle: caPEM}
+	if err := mgr.Add(injector); err != nil {
+		return err
+	}
+
+	if err := mgr.AddReadyzCheck("webhook-ca", injector.check); err != nil {
+		return err
+	}
+
+	return mgr.AddReadyzCheck("webhook", server.StartedChecker())
+}
@@ -26,6 +26,7 @@ const sidebars = {
       items: [
         'manual-removal',
         'exclusion',
+        'image-policy',
         'customization',
         'metrics'
       ]
@@ -48,6 +49,8 @@ const sidebars = {
       items: [
         'custom-scanner',
         'trivy',
+        'grype',
+        'signature',
       ]
     },
     'faq',
@@ -30,10 +30,9 @@ import (
 
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"k8s.io/utils/inotify"
-	"sigs.k8s.io/yaml"
 
 	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/conversion"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
@@ -46,12 +45,11 @@ import (
 	"github.com/eraser-dev/eraser/api/unversioned/config"
 	eraserv1 "github.com/eraser-dev/eraser/api/v1"
 	eraserv1alpha1 "github.com/eraser-dev/eraser/api/v1alpha1"
-	v1alpha1Config "github.com/eraser-dev/eraser/api/v1alpha1/config"
 	eraserv1alpha2 "github.com/eraser-dev/eraser/api/v1alpha2"
-	v1alpha2Config "github.com/eraser-dev/eraser/api/v1alpha2/config"
 	eraserv1alpha3 "github.com/eraser-dev/eraser/api/v1alpha3"
-	v1alpha3Config "github.com/eraser-dev/eraser/api/v1alpha3/config"
 	"github.com/eraser-dev/eraser/controllers"
+	"github.com/eraser-dev/eraser/controllers/eraserconfig"
+	"github.com/eraser-dev/eraser/pkg/configfile"
 	"github.com/eraser-dev/eraser/pkg/logger"
 	"github.com/eraser-dev/eraser/pkg/utils"
 	"github.com/eraser-dev/eraser/version"
@@ -61,26 +59,19 @@ import (
 var (
 	scheme   = runtime.NewScheme()
 	setupLog = ctrl.Log.WithName("setup")
-
-	fromV1alpha1 = eraserv1alpha1.Convert_v1alpha1_EraserConfig_To_unversioned_EraserConfig
-	fromV1alpha2 = eraserv1alpha2.Convert_v1alpha2_EraserConfig_To_unversioned_EraserConfig
-	fromV1alpha3 = eraserv1alpha3.Convert_v1alpha3_EraserConfig_To_unversioned_EraserConfig
 )
 
 func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(apiextensionsv1.AddToScheme(scheme))
 
 	utilruntime.Must(eraserv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(eraserv1alpha2.AddToScheme(scheme))
+	utilruntime.Must(eraserv1alpha3.AddToScheme(scheme))
 	utilruntime.Must(eraserv1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
-type apiVersion struct {
-	APIVersion string `json:"apiVersion"`
-}
-
-type convertFunc[T any] func(*T, *unversioned.EraserConfig, conversion.Scope) error
-
 func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -114,10 +105,6 @@ func main() {
 				&corev1.Pod{}: {
 					Field: fields.OneTermEqualSelector("metadata.namespace", utils.GetNamespace()),
 				},
-				// to watch eraser podTemplates
-				&corev1.PodTemplate{}: {
-					Field: fields.OneTermEqualSelector("metadata.namespace", utils.GetNamespace()),
-				},
 				// to watch eraser-manager-configs
 				&corev1.ConfigMap{}: {
 					Field: fields.OneTermEqualSelector("metadata.namespace", utils.GetNamespace()),
@@ -149,7 +136,37 @@ func main() {
 	)
 
 	eraserOpts := config.NewManager(cfg)
-	managerOpts := cfg.Manager
+
+	config := ctrl.GetConfigOrDie()
+	config.UserAgent = version.GetUserAgent("manager")
+
+	setupLog.Info("setting up manager", "userAgent", config.UserAgent)
+
+	mgr, err := ctrl.NewManager(config, options)
+	if err != nil {
+		setupLog.Error(err, "unable to start manager")
+		os.Exit(1)
+	}
+
+	// the EraserConfig resource takes precedence over the configuration file,
+	// and is loaded before the controllers are set up from the configuration
+	resourceConfig, err := eraserconfig.Load(ctx, mgr.GetAPIReader())
+	switch {
+	case err != nil:
+		setupLog.Error(err, "unable to load the EraserConfig resource, using the configuration file", "name", eraserconfig.Name)
+	case resourceConfig != nil:
+		setupLog.Info("using the EraserConfig resource instead of the configuration file", "name", eraserconfig.Name)
+		if err := eraserOpts.Override(resourceConfig); err != nil {
+			setupLog.Error(err, "unable to use the EraserConfig resource")
+			os.Exit(1)
+		}
+	}
+
+	eraserOpts.OnRestart(func() {
+		setupLog.Info("configurations differ in an irreconcileable way, restarting")
+		// restarts the manager
+		cancel()
+	})
 
 	watcher, err := setupWatcher(configFile)
 	if err != nil {
@@ -157,12 +174,18 @@ func main() {
 		os.Exit(1)
 	}
 
-	go startConfigWatch(cancel, watcher, eraserOpts, configFile)
+	go startConfigWatch(watcher, eraserOpts, configFile)
 
-	if managerOpts.Profile.Enabled {
+	managerOpts, err := eraserOpts.Read()
+	if err != nil {
+		setupLog.Error(err, "unable to read configuration")
+		os.Exit(1)
+	}
+
+	if managerOpts.Manager.Profile.Enabled {
 		go func() {
 			server := &http.Server{
-				Addr:              fmt.Sprintf("localhost:%d", managerOpts.Profile.Port),
+				Addr:              fmt.Sprintf("localhost:%d", managerOpts.Manager.Profile.Port),
 				ReadHeaderTimeout: 3 * time.Second,
 			}
 			err := server.ListenAndServe()
@@ -170,17 +193,6 @@ func main() {
 		}()
 	}
 
-	config := ctrl.GetConfigOrDie()
-	config.UserAgent = version.GetUserAgent("manager")
-
-	setupLog.Info("setting up manager", "userAgent", config.UserAgent)
-
-	mgr, err := ctrl.NewManager(config, options)
-	if err != nil {
-		setupLog.Error(err, "unable to start manager")
-		os.Exit(1)
-	}
-
 	setupLog.Info("setup controllers")
 	if err = controllers.SetupWithManager(mgr, eraserOpts); err != nil {
 		setupLog.Error(err, "unable to setup controllers")
@@ -212,39 +224,13 @@ func getConfig(configFile string) (*unversioned.EraserConfig, error) {
 		os.Exit(1)
 	}
 
-	var av apiVersion
-	if err := yaml.Unmarshal(fileBytes, &av); err != nil {
-		setupLog.Error(err, "cannot unmarshal yaml", "bytes", string(fileBytes), "apiVersion", av)
-		os.Exit(1)
-	}
-
-	switch av.APIVersion {
-	case "eraser.sh/v1alpha1":
-		return getUnversioned(fileBytes, v1alpha1Config.Default(), fromV1alpha1)
-	case "eraser.sh/v1alpha2":
-		return getUnversioned(fileBytes, v1alpha2Config.Default(), fromV1alpha2)
-	case "eraser.sh/v1alpha3":
-		return getUnversioned(fileBytes, v1alpha3Config.Default(), fromV1alpha3)
-	default:
-		setupLog.Error(fmt.Errorf("unknown api version"), "error", "apiVersion", av.APIVersion)
-		return nil, err
-	}
-}
-
-func getUnversioned[T any](b []byte, defaults *T, convert convertFunc[T]) (*unversioned.EraserConfig, error) {
-	cfg := defaults
-
-	if err := yaml.Unmarshal(b, cfg); err != nil {
-		setupLog.Error(err, "configuration is either missing or invalid")
-		return nil, err
-	}
-
-	var unv unversioned.EraserConfig
-	if err := convert(cfg, &unv, nil); err != nil {
+	cfg, err := configfile.Parse(fileBytes)
+	if err != nil {
+		setupLog.Error(err, "configuration is either missing or invalid", "bytes", string(fileBytes))
 		return nil, err
 	}
 
-	return &unv, nil
+	return cfg, nil
 }
 
 // Kubernetes manages configmap volume updates by creating a new file,
@@ -265,7 +251,7 @@ func setupWatcher(configFile string) (*inotify.Watcher, error) {
 	return watcher, nil
 }
 
-func startConfigWatch(cancel context.CancelFunc, watcher *inotify.Watcher, eraserOpts *config.Manager, filename string) {
+func startConfigWatch(watcher *inotify.Watcher, eraserOpts *config.Manager, filename string) {
 	for {
 		select {
 		case ev := <-watcher.Event:
@@ -285,14 +271,6 @@ func startConfigWatch(cancel context.CancelFunc, watcher *inotify.Watcher, erase
 				continue
 			}
 
-			var err error
-			oldConfig := new(unversioned.EraserConfig)
-
-			*oldConfig, err = eraserOpts.Read()
-			if err != nil {
-				setupLog.Error(err, "configuration could not be read", "event", ev, "filename", filename)
-			}
-
 			newConfig, err := getConfig(filename)
 			if err != nil {
 				setupLog.Error(err, "configuration is missing or invalid", "event", ev, "filename", filename)
@@ -304,33 +282,14 @@ func startConfigWatch(cancel context.CancelFunc, watcher *inotify.Watcher, erase
 				continue
 			}
 
-			// read back the new configuration
-			*newConfig, err = eraserOpts.Read()
-			if err != nil {
-				setupLog.Error(err, "unable to read back new configuration")
+			if eraserOpts.Overridden() {
+				setupLog.Info("configuration file was updated, but the EraserConfig resource takes precedence", "name", eraserconfig.Name)
 				continue
 			}
 
-			if needsRestart(oldConfig, newConfig) {
-				setupLog.Info("configurations differ in an irreconcileable way, restarting", "old", oldConfig.Components, "new", newConfig.Components)
-				// restarts the manager
-				cancel()
-			}
-
 			setupLog.V(1).Info("new configuration", "manager", newConfig.Manager, "components", newConfig.Components)
 		case err := <-watcher.Error:
 			setupLog.Error(err, "file watcher error")
 		}
 	}
 }
-
-func needsRestart(oldConfig, newConfig *unversioned.EraserConfig) bool {
-	type check struct {
-		collector bool
-		scanner   bool
-	}
-
-	oldComponents := check{collector: oldConfig.Components.Collector.Enabled, scanner: oldConfig.Components.Scanner.Enabled}
-	newComponents := check{collector: newConfig.Components.Collector.Enabled, scanner: newConfig.Components.Scanner.Enabled}
-	return oldComponents != newComponents
-}
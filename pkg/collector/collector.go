@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -12,6 +13,9 @@ import (
 
 	"github.com/eraser-dev/eraser/pkg/cri"
 	"github.com/eraser-dev/eraser/pkg/logger"
+	"github.com/eraser-dev/eraser/pkg/policy"
+	"github.com/eraser-dev/eraser/pkg/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/sys/unix"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -27,6 +31,12 @@ var (
 	timeout  = 5 * time.Minute
 	log      = logf.Log.WithName("collector")
 	excluded map[string]struct{}
+
+	// imagePolicy is only set when the scanner is disabled; otherwise the
+	// scanner makes the removal decision with vulnerability data available.
+	imagePolicy *policy.Engine
+	nodeName    string
+	nodeLabels  map[string]string
 )
 
 func main() {
@@ -65,8 +75,30 @@ func main() {
 		log.Info("no images to exclude")
 	}
 
+	if *scanDisabled {
+		imagePolicy, err = policy.FromEnv()
+		if err != nil {
+			log.Error(err, "failed to load image policy")
+			os.Exit(1)
+		}
+		nodeName, nodeLabels = policy.NodeFromEnv()
+	}
+
+	ctx, shutdownTracing, err := tracing.ConfigureFromEnv(context.Background())
+	if err != nil {
+		log.Error(err, "unable to configure tracing")
+	}
+
 	// finalImages of type []Image
+	_, span := tracing.Tracer().Start(ctx, "collector.getImages")
 	finalImages, err := getImages(client)
+	span.SetAttributes(attribute.Int("images.collected", len(finalImages)))
+	tracing.End(span, err)
+
+	if err := shutdownTracing(context.Background()); err != nil {
+		log.Error(err, "unable to export traces")
+	}
+
 	if err != nil {
 		log.Error(err, "failed to list all images")
 		os.Exit(1)
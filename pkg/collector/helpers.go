@@ -5,6 +5,7 @@ import (
 
 	"github.com/eraser-dev/eraser/api/unversioned"
 	"github.com/eraser-dev/eraser/pkg/cri"
+	"github.com/eraser-dev/eraser/pkg/policy"
 	util "github.com/eraser-dev/eraser/pkg/utils"
 )
 
@@ -28,6 +29,8 @@ func getImages(c cri.Collector) ([]unversioned.Image, error) {
 		newImg := unversioned.Image{
 			ImageID: img.Id,
 			Names:   repoTags,
+			Size:    int64(img.Size_),
+			Pinned:  img.Pinned,
 		}
 
 		digests, errs := util.ProcessRepoDigests(img.RepoDigests)
@@ -71,11 +74,30 @@ func getImages(c cri.Collector) ([]unversioned.Image, error) {
 			ImageID: imageID,
 			Names:   img.Names,
 			Digests: img.Digests,
+			Size:    img.Size,
+			Pinned:  img.Pinned,
 		}
 
-		if !util.IsExcluded(excluded, currImage.ImageID, idToImageMap) {
-			finalImages = append(finalImages, currImage)
+		if util.IsExcluded(excluded, currImage.ImageID, idToImageMap) {
+			continue
+		}
+
+		if imagePolicy != nil {
+			remove, rule, err := imagePolicy.Evaluate(&policy.Facts{Image: currImage, NodeName: nodeName, NodeLabels: nodeLabels})
+			if err != nil {
+				log.Error(err, "error evaluating image policy", "imageID", imageID)
+				continue
+			}
+
+			if !remove {
+				log.V(1).Info("image not selected by policy", "imageID", imageID)
+				continue
+			}
+
+			log.Info("image selected by policy", "imageID", imageID, "rule", rule)
 		}
+
+		finalImages = append(finalImages, currImage)
 	}
 
 	return finalImages, nil
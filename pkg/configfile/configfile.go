@@ -0,0 +1,72 @@
+// Package configfile parses the EraserConfig which the manager reads from
+// its configuration file, in any of the versions it has been released in.
+package configfile
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/conversion"
+	"sigs.k8s.io/yaml"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+	"github.com/eraser-dev/eraser/api/v1alpha1"
+	v1alpha1Config "github.com/eraser-dev/eraser/api/v1alpha1/config"
+	"github.com/eraser-dev/eraser/api/v1alpha2"
+	v1alpha2Config "github.com/eraser-dev/eraser/api/v1alpha2/config"
+	"github.com/eraser-dev/eraser/api/v1alpha3"
+	v1alpha3Config "github.com/eraser-dev/eraser/api/v1alpha3/config"
+)
+
+// Key is the key of the configuration file in the manager's ConfigMap.
+const Key = "controller_manager_config.yaml"
+
+type apiVersion struct {
+	APIVersion string `json:"apiVersion"`
+}
+
+type convertFunc[T any] func(*T, *unversioned.EraserConfig, conversion.Scope) error
+
+type unmarshalFunc func([]byte, interface{}, ...yaml.JSONOpt) error
+
+// Parse parses an EraserConfig of any version, filling in the defaults of
+// that version. Unknown fields are ignored.
+func Parse(b []byte) (*unversioned.EraserConfig, error) {
+	return parse(b, yaml.Unmarshal)
+}
+
+// ParseStrict parses an EraserConfig like Parse, but rejects unknown and
+// duplicate fields, which are most likely typos.
+func ParseStrict(b []byte) (*unversioned.EraserConfig, error) {
+	return parse(b, yaml.UnmarshalStrict)
+}
+
+func parse(b []byte, unmarshal unmarshalFunc) (*unversioned.EraserConfig, error) {
+	var av apiVersion
+	if err := yaml.Unmarshal(b, &av); err != nil {
+		return nil, err
+	}
+
+	switch av.APIVersion {
+	case "eraser.sh/v1alpha1":
+		return toUnversioned(b, v1alpha1Config.Default(), v1alpha1.Convert_v1alpha1_EraserConfig_To_unversioned_EraserConfig, unmarshal)
+	case "eraser.sh/v1alpha2":
+		return toUnversioned(b, v1alpha2Config.Default(), v1alpha2.Convert_v1alpha2_EraserConfig_To_unversioned_EraserConfig, unmarshal)
+	case "eraser.sh/v1alpha3":
+		return toUnversioned(b, v1alpha3Config.Default(), v1alpha3.Convert_v1alpha3_EraserConfig_To_unversioned_EraserConfig, unmarshal)
+	default:
+		return nil, fmt.Errorf("unknown apiVersion %q", av.APIVersion)
+	}
+}
+
+func toUnversioned[T any](b []byte, defaults *T, convert convertFunc[T], unmarshal unmarshalFunc) (*unversioned.EraserConfig, error) {
+	if err := unmarshal(b, defaults); err != nil {
+		return nil, err
+	}
+
+	var unv unversioned.EraserConfig
+	if err := convert(defaults, &unv, nil); err != nil {
+		return nil, err
+	}
+
+	return &unv, nil
+}
@@ -0,0 +1,68 @@
+package configfile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		desc           string
+		config         string
+		repeatInterval time.Duration
+		wantErr        bool
+		wantStrictErr  bool
+	}{
+		{
+			desc:           "v1alpha1",
+			config:         "apiVersion: eraser.sh/v1alpha1\nkind: EraserConfig\nmanager:\n  scheduling:\n    repeatInterval: 1h\n",
+			repeatInterval: time.Hour,
+		},
+		{
+			desc:           "v1alpha2",
+			config:         "apiVersion: eraser.sh/v1alpha2\nkind: EraserConfig\nmanager:\n  scheduling:\n    repeatInterval: 2h\n",
+			repeatInterval: 2 * time.Hour,
+		},
+		{
+			desc:           "v1alpha3 defaults",
+			config:         "apiVersion: eraser.sh/v1alpha3\nkind: EraserConfig\n",
+			repeatInterval: 24 * time.Hour,
+		},
+		{
+			desc:           "unknown field",
+			config:         "apiVersion: eraser.sh/v1alpha3\nkind: EraserConfig\nmanager:\n  schedulng: {}\n",
+			repeatInterval: 24 * time.Hour,
+			wantStrictErr:  true,
+		},
+		{
+			desc:    "unknown apiVersion",
+			config:  "apiVersion: eraser.sh/v2\nkind: EraserConfig\n",
+			wantErr: true,
+		},
+		{
+			desc:    "not yaml",
+			config:  "apiVersion: [",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			cfg, err := Parse([]byte(tt.config))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error: %v, got: %v", tt.wantErr, err)
+			}
+			if err == nil && cfg.Manager.Scheduling.RepeatInterval != unversioned.Duration(tt.repeatInterval) {
+				t.Errorf("expected repeatInterval %s, got %s", tt.repeatInterval, time.Duration(cfg.Manager.Scheduling.RepeatInterval))
+			}
+
+			_, err = ParseStrict([]byte(tt.config))
+			if wantErr := tt.wantErr || tt.wantStrictErr; (err != nil) != wantErr {
+				t.Errorf("expected strict error: %v, got: %v", wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package metrics
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"google.golang.org/grpc/credentials"
+	// registers the gzip compressor of the gRPC exporter
+	_ "google.golang.org/grpc/encoding/gzip"
+)
+
+// The exporter of job pods is configured with the environment variables
+// defined by the OpenTelemetry specification.
+const (
+	EnvOTLPEndpoint          = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	EnvOTLPProtocol          = "OTEL_EXPORTER_OTLP_PROTOCOL"
+	EnvOTLPInsecure          = "OTEL_EXPORTER_OTLP_INSECURE"
+	EnvOTLPCertificate       = "OTEL_EXPORTER_OTLP_CERTIFICATE"
+	EnvOTLPClientCertificate = "OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE"
+	EnvOTLPClientKey         = "OTEL_EXPORTER_OTLP_CLIENT_KEY"
+	EnvOTLPHeaders           = "OTEL_EXPORTER_OTLP_HEADERS"
+	EnvOTLPCompression       = "OTEL_EXPORTER_OTLP_COMPRESSION"
+	EnvMetricExportInterval  = "OTEL_METRIC_EXPORT_INTERVAL"
+
+	ProtocolHTTP = "http/protobuf"
+	ProtocolGRPC = "grpc"
+
+	CompressionGzip = "gzip"
+	CompressionNone = "none"
+
+	// The keys of the TLS Secret, which are those of a kubernetes.io/tls
+	// Secret and its CA.
+	TLSCAKey          = "ca.crt"
+	TLSCertificateKey = "tls.crt"
+	TLSKeyKey         = "tls.key"
+
+	// HeadersKey is the key of the headers Secret, which holds the headers
+	// in the format of OTEL_EXPORTER_OTLP_HEADERS.
+	HeadersKey = "headers"
+)
+
+// ExporterConfig configures the OTLP exporter.
+type ExporterConfig struct {
+	Endpoint string
+	Protocol string
+	Insecure bool
+	// CA, Certificate and Key are PEM encoded. The system's CAs are used if
+	// CA is empty, and no client certificate is sent if Certificate is empty.
+	CA          []byte
+	Certificate []byte
+	Key         []byte
+	Headers     map[string]string
+	Compression string
+	Interval    time.Duration
+}
+
+// ExporterConfigFromEnv reads the exporter configuration of a job pod from
+// its environment. Certificate files which do not exist are ignored, so that
+// the pod can be given the paths of every key the TLS Secret may hold.
+func ExporterConfigFromEnv() (ExporterConfig, error) {
+	cfg := ExporterConfig{
+		Endpoint:    os.Getenv(EnvOTLPEndpoint),
+		Protocol:    os.Getenv(EnvOTLPProtocol),
+		Compression: os.Getenv(EnvOTLPCompression),
+		// before the exporter was configurable, it never used TLS
+		Insecure: true,
+	}
+
+	if v := os.Getenv(EnvOTLPInsecure); v != "" {
+		insecure, err := strconv.ParseBool(v)
+		if err != nil {
+			return cfg, fmt.Errorf("parse %s: %w", EnvOTLPInsecure, err)
+		}
+		cfg.Insecure = insecure
+	}
+
+	if v := os.Getenv(EnvMetricExportInterval); v != "" {
+		ms, err := strconv.Atoi(v)
+		if err != nil {
+			return cfg, fmt.Errorf("parse %s: %w", EnvMetricExportInterval, err)
+		}
+		cfg.Interval = time.Duration(ms) * time.Millisecond
+	}
+
+	headers, err := ParseHeaders(os.Getenv(EnvOTLPHeaders))
+	if err != nil {
+		return cfg, fmt.Errorf("parse %s: %w", EnvOTLPHeaders, err)
+	}
+	cfg.Headers = headers
+
+	for env, pem := range map[string]*[]byte{
+		EnvOTLPCertificate:       &cfg.CA,
+		EnvOTLPClientCertificate: &cfg.Certificate,
+		EnvOTLPClientKey:         &cfg.Key,
+	} {
+		path := os.Getenv(env)
+		if path == "" {
+			continue
+		}
+
+		b, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return cfg, err
+		}
+		*pem = b
+	}
+
+	return cfg, nil
+}
+
+// ParseHeaders parses headers in the format of OTEL_EXPORTER_OTLP_HEADERS,
+// a comma separated list of key=value pairs with URL encoded values.
+func ParseHeaders(s string) (map[string]string, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid header %q", pair)
+		}
+
+		value, err := url.PathUnescape(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("invalid header %q: %w", k, err)
+		}
+		headers[k] = value
+	}
+
+	return headers, nil
+}
+
+// FormatHeaders formats headers for OTEL_EXPORTER_OTLP_HEADERS.
+func FormatHeaders(headers map[string]string) string {
+	pairs := make([]string, 0, len(headers))
+	for k, v := range headers {
+		pairs = append(pairs, k+"="+url.PathEscape(v))
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+// TLSConfig returns the TLS configuration of the exporter.
+func (c *ExporterConfig) TLSConfig() (*tls.Config, error) {
+	tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if len(c.CA) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(c.CA) {
+			return nil, errors.New("no certificates found in CA")
+		}
+		tlsCfg.RootCAs = pool
+	}
+
+	if len(c.Certificate) > 0 {
+		cert, err := tls.X509KeyPair(c.Certificate, c.Key)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsCfg, nil
+}
+
+func newExporter(ctx context.Context, cfg *ExporterConfig) (sdkmetric.Exporter, error) {
+	var tlsCfg *tls.Config
+	if !cfg.Insecure {
+		var err error
+		if tlsCfg, err = cfg.TLSConfig(); err != nil {
+			return nil, err
+		}
+	}
+
+	switch cfg.Protocol {
+	case ProtocolGRPC:
+		opts := []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpoint(cfg.Endpoint)}
+		if tlsCfg == nil {
+			opts = append(opts, otlpmetricgrpc.WithInsecure())
+		} else {
+			opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(tlsCfg)))
+		}
+		if len(cfg.Headers) > 0 {
+			opts = append(opts, otlpmetricgrpc.WithHeaders(cfg.Headers))
+		}
+		if cfg.Compression == CompressionGzip {
+			opts = append(opts, otlpmetricgrpc.WithCompressor(CompressionGzip))
+		}
+
+		return otlpmetricgrpc.New(ctx, opts...)
+	case ProtocolHTTP, "":
+		opts := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpoint(cfg.Endpoint)}
+		if tlsCfg == nil {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		} else {
+			opts = append(opts, otlpmetrichttp.WithTLSClientConfig(tlsCfg))
+		}
+		if len(cfg.Headers) > 0 {
+			opts = append(opts, otlpmetrichttp.WithHeaders(cfg.Headers))
+		}
+		if cfg.Compression == CompressionGzip {
+			opts = append(opts, otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression))
+		}
+
+		return otlpmetrichttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unsupported OTLP protocol %q", cfg.Protocol)
+	}
+}
@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExporterConfigFromEnv(t *testing.T) {
+	dir := t.TempDir()
+	ca := filepath.Join(dir, TLSCAKey)
+	require.NoError(t, os.WriteFile(ca, []byte("ca"), 0o600))
+
+	t.Setenv(EnvOTLPEndpoint, "otel-collector:4317")
+	t.Setenv(EnvOTLPProtocol, ProtocolGRPC)
+	t.Setenv(EnvOTLPInsecure, "false")
+	t.Setenv(EnvOTLPCompression, CompressionGzip)
+	t.Setenv(EnvMetricExportInterval, "30000")
+	t.Setenv(EnvOTLPHeaders, "x-api-key=abc%3D%3D,x-tenant=eraser")
+	t.Setenv(EnvOTLPCertificate, ca)
+	// the Secret has no client certificate
+	t.Setenv(EnvOTLPClientCertificate, filepath.Join(dir, TLSCertificateKey))
+	t.Setenv(EnvOTLPClientKey, filepath.Join(dir, TLSKeyKey))
+
+	cfg, err := ExporterConfigFromEnv()
+	require.NoError(t, err)
+
+	assert.Equal(t, ExporterConfig{
+		Endpoint:    "otel-collector:4317",
+		Protocol:    ProtocolGRPC,
+		Insecure:    false,
+		CA:          []byte("ca"),
+		Headers:     map[string]string{"x-api-key": "abc==", "x-tenant": "eraser"},
+		Compression: CompressionGzip,
+		Interval:    30 * time.Second,
+	}, cfg)
+}
+
+func TestExporterConfigFromEnvDefaults(t *testing.T) {
+	t.Setenv(EnvOTLPEndpoint, "otel-collector:4318")
+
+	cfg, err := ExporterConfigFromEnv()
+	require.NoError(t, err)
+	assert.True(t, cfg.Insecure)
+	assert.Nil(t, cfg.Headers)
+}
+
+func TestFormatHeaders(t *testing.T) {
+	headers := map[string]string{"authorization": "Bearer a,b=c", "x-tenant": "eraser"}
+
+	s := FormatHeaders(headers)
+	assert.Equal(t, "authorization=Bearer%20a%2Cb=c,x-tenant=eraser", s)
+
+	parsed, err := ParseHeaders(s)
+	require.NoError(t, err)
+	assert.Equal(t, headers, parsed)
+
+	_, err = ParseHeaders("no-value")
+	assert.Error(t, err)
+}
+
+func TestNewExporter(t *testing.T) {
+	ctx := context.Background()
+
+	for _, protocol := range []string{ProtocolHTTP, ProtocolGRPC} {
+		exporter, err := newExporter(ctx, &ExporterConfig{Endpoint: "otel-collector:4317", Protocol: protocol, Compression: CompressionGzip})
+		require.NoError(t, err, protocol)
+		require.NoError(t, exporter.Shutdown(ctx))
+	}
+
+	_, err := newExporter(ctx, &ExporterConfig{Protocol: "http/json", Insecure: true})
+	assert.Error(t, err)
+
+	_, err = newExporter(ctx, &ExporterConfig{Protocol: ProtocolGRPC, CA: []byte("not a certificate")})
+	assert.Error(t, err)
+}
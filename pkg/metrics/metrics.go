@@ -2,11 +2,14 @@ package metrics
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
 	metric "go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/metric/instrument"
 	"go.opentelemetry.io/otel/metric/unit"
@@ -18,17 +21,68 @@ import (
 const (
 	ImagesRemovedCounter     = "images_removed_run_total"
 	ImagesRemovedDescription = "total images removed"
+
+	VulnerableImagesCounter = "vulnerable_images_run_total"
+	ImageJobCounter         = "imagejob_run_total"
+	PodsCompletedCounter    = "pods_completed_run_total"
+	PodsFailedCounter       = "pods_failed_run_total"
+
+	nodeNameAttribute = "node name"
 )
 
-func ConfigureMetrics(ctx context.Context, log logr.Logger, endpoint string) (sdkmetric.Exporter, sdkmetric.Reader, *sdkmetric.MeterProvider) {
-	exporter, err := otlpmetrichttp.New(ctx, otlpmetrichttp.WithInsecure(), otlpmetrichttp.WithEndpoint(endpoint))
+// counters are renamed for Prometheus, which appends _total to the name of
+// every counter, so that they are scraped under the same names as they are
+// exported over OTLP.
+var counters = []string{
+	ImagesRemovedCounter,
+	VulnerableImagesCounter,
+	ImageJobCounter,
+	PodsCompletedCounter,
+	PodsFailedCounter,
+	ImageRemovalsCounter,
+	ScanFailuresCounter,
+}
+
+// ConfigureMetrics returns a MeterProvider whose metrics are exported over
+// OTLP, along with its exporter and reader. If the exporter cannot be
+// created, it returns an error and no provider.
+func ConfigureMetrics(ctx context.Context, cfg ExporterConfig) (sdkmetric.Exporter, sdkmetric.Reader, *sdkmetric.MeterProvider, error) {
+	exporter, err := newExporter(ctx, &cfg)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("initialize exporter: %w", err)
+	}
+
+	var readerOpts []sdkmetric.PeriodicReaderOption
+	if cfg.Interval > 0 {
+		readerOpts = append(readerOpts, sdkmetric.WithInterval(cfg.Interval))
+	}
+
+	reader := sdkmetric.NewPeriodicReader(exporter, readerOpts...)
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader), sdkmetric.WithView(durationView(), scanDurationView()))
+
+	return exporter, reader, provider, nil
+}
+
+// ConfigurePrometheus returns a MeterProvider whose metrics are collected by
+// reg, so that they can be scraped from the manager's /metrics endpoint.
+func ConfigurePrometheus(reg prometheus.Registerer) (*sdkmetric.MeterProvider, error) {
+	exporter, err := otelprom.New(otelprom.WithRegisterer(reg), otelprom.WithoutUnits(), otelprom.WithoutScopeInfo())
 	if err != nil {
-		log.Error(err, "error initializing exporter")
-		return nil, nil, nil
+		return nil, err
 	}
 
-	reader := sdkmetric.NewPeriodicReader(exporter)
+	views := []sdkmetric.View{durationView(), scanDurationView()}
+	for _, name := range counters {
+		views = append(views, sdkmetric.NewView(
+			sdkmetric.Instrument{Name: name, Scope: instrumentation.Scope{Name: "eraser"}},
+			sdkmetric.Stream{Name: strings.TrimSuffix(name, "_total")},
+		))
+	}
+
+	return sdkmetric.NewMeterProvider(sdkmetric.WithReader(exporter), sdkmetric.WithView(views...)), nil
+}
 
+func durationView() sdkmetric.View {
 	durationInstrument := sdkmetric.Instrument{
 		Name:  "imagejob_duration_run_seconds",
 		Scope: instrumentation.Scope{Name: "eraser"},
@@ -42,14 +96,15 @@ func ConfigureMetrics(ctx context.Context, log logr.Logger, endpoint string) (sd
 		},
 	}
 
-	histogramView := sdkmetric.NewView(durationInstrument, durationStream)
-
-	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader), sdkmetric.WithView(histogramView))
-
-	return exporter, reader, provider
+	return sdkmetric.NewView(durationInstrument, durationStream)
 }
 
 func ExportMetrics(log logr.Logger, exporter sdkmetric.Exporter, reader sdkmetric.Reader) {
+	// there is nothing to export if the exporter could not be created
+	if exporter == nil || reader == nil {
+		return
+	}
+
 	ctxB := context.Background()
 
 	m, err := reader.Collect(ctxB)
@@ -63,22 +118,32 @@ func ExportMetrics(log logr.Logger, exporter sdkmetric.Exporter, reader sdkmetri
 }
 
 func RecordMetricsRemover(ctx context.Context, p metric.MeterProvider, totalRemoved int64) error {
+	return RecordMetricsRemoverNode(ctx, p, os.Getenv("NODE_NAME"), totalRemoved)
+}
+
+// RecordMetricsRemoverNode records images removed on the given node.
+func RecordMetricsRemoverNode(ctx context.Context, p metric.MeterProvider, nodeName string, totalRemoved int64) error {
 	counter, err := p.Meter("eraser").SyncInt64().Counter(ImagesRemovedCounter, instrument.WithDescription(ImagesRemovedDescription), instrument.WithUnit("1"))
 	if err != nil {
 		return err
 	}
 
-	counter.Add(ctx, totalRemoved, attribute.String("node name", os.Getenv("NODE_NAME")))
+	counter.Add(ctx, totalRemoved, attribute.String(nodeNameAttribute, nodeName))
 	return nil
 }
 
 func RecordMetricsScanner(ctx context.Context, p metric.MeterProvider, totalVulnerable int) error {
-	counter, err := p.Meter("eraser").SyncInt64().Counter("vulnerable_images_run_total", instrument.WithDescription("total vulnerable images"), instrument.WithUnit("1"))
+	return RecordMetricsScannerNode(ctx, p, os.Getenv("NODE_NAME"), totalVulnerable)
+}
+
+// RecordMetricsScannerNode records vulnerable images found on the given node.
+func RecordMetricsScannerNode(ctx context.Context, p metric.MeterProvider, nodeName string, totalVulnerable int) error {
+	counter, err := p.Meter("eraser").SyncInt64().Counter(VulnerableImagesCounter, instrument.WithDescription("total vulnerable images"), instrument.WithUnit("1"))
 	if err != nil {
 		return err
 	}
 
-	counter.Add(ctx, int64(totalVulnerable), attribute.String("node name", os.Getenv("NODE_NAME")))
+	counter.Add(ctx, int64(totalVulnerable), attribute.String(nodeNameAttribute, nodeName))
 	return nil
 }
 
@@ -89,19 +154,19 @@ func RecordMetricsController(ctx context.Context, p metric.MeterProvider, jobDur
 	}
 	duration.Record(ctx, jobDuration)
 
-	completed, err := p.Meter("eraser").SyncInt64().Counter("pods_completed_run_total", instrument.WithDescription("total pods completed"), instrument.WithUnit("1"))
+	completed, err := p.Meter("eraser").SyncInt64().Counter(PodsCompletedCounter, instrument.WithDescription("total pods completed"), instrument.WithUnit("1"))
 	if err != nil {
 		return err
 	}
 	completed.Add(ctx, podsCompleted)
 
-	failed, err := p.Meter("eraser").SyncInt64().Counter("pods_failed_run_total", instrument.WithDescription("total pods failed"), instrument.WithUnit("1"))
+	failed, err := p.Meter("eraser").SyncInt64().Counter(PodsFailedCounter, instrument.WithDescription("total pods failed"), instrument.WithUnit("1"))
 	if err != nil {
 		return err
 	}
 	failed.Add(ctx, podsFailed)
 
-	jobTotal, err := p.Meter("eraser").SyncInt64().Counter("imagejob_run_total", instrument.WithDescription("total number of imagejobs completed"), instrument.WithUnit("1"))
+	jobTotal, err := p.Meter("eraser").SyncInt64().Counter(ImageJobCounter, instrument.WithDescription("total number of imagejobs completed"), instrument.WithUnit("1"))
 	if err != nil {
 		return err
 	}
@@ -4,7 +4,7 @@ import (
 	"context"
 	"testing"
 
-	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metric "go.opentelemetry.io/otel/metric"
@@ -13,7 +13,10 @@ import (
 )
 
 func TestConfigureMetrics(t *testing.T) {
-	exporter, reader, provider := ConfigureMetrics(context.Background(), logr.Discard(), "otel-collector:4318")
+	exporter, reader, provider, err := ConfigureMetrics(context.Background(), ExporterConfig{Endpoint: "otel-collector:4318", Insecure: true})
+	if err != nil {
+		t.Fatal(err)
+	}
 	if exporter == nil {
 		t.Fatal("unable to configure exporter")
 	}
@@ -27,6 +30,16 @@ func TestConfigureMetrics(t *testing.T) {
 	global.SetMeterProvider(provider)
 }
 
+func TestConfigureMetricsError(t *testing.T) {
+	_, _, provider, err := ConfigureMetrics(context.Background(), ExporterConfig{Endpoint: "otel-collector:4318", CA: []byte("not a certificate")})
+	if err == nil {
+		t.Error("expected an error for an invalid CA")
+	}
+	if provider != nil {
+		t.Error("expected no provider")
+	}
+}
+
 func TestRecordMetrics(t *testing.T) {
 	if err := RecordMetricsRemover(context.Background(), global.MeterProvider(), 1); err != nil {
 		t.Fatal("could not record eraser metrics")
@@ -78,3 +91,36 @@ func TestMeterCreatesInstrument(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigurePrometheus(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	provider, err := ConfigurePrometheus(reg)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, RecordMetricsRemoverNode(ctx, provider, "node-1", 2))
+	require.NoError(t, RecordMetricsRemoverNode(ctx, provider, "node-1", 3))
+	require.NoError(t, RecordMetricsScannerNode(ctx, provider, "node-2", 1))
+	require.NoError(t, RecordMetricsController(ctx, provider, 12, 2, 0))
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	values := map[string]float64{}
+	for _, mf := range families {
+		for _, m := range mf.GetMetric() {
+			if c := m.GetCounter(); c != nil {
+				values[mf.GetName()] += c.GetValue()
+			}
+			if h := m.GetHistogram(); h != nil {
+				values[mf.GetName()] += float64(h.GetSampleCount())
+			}
+		}
+	}
+
+	assert.Equal(t, float64(5), values[ImagesRemovedCounter])
+	assert.Equal(t, float64(1), values["vulnerable_images_run_total"])
+	assert.Equal(t, float64(2), values["pods_completed_run_total"])
+	assert.Equal(t, float64(1), values["imagejob_run_total"])
+	assert.Equal(t, float64(1), values["imagejob_duration_run_seconds"])
+}
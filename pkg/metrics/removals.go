@@ -0,0 +1,195 @@
+package metrics
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	metric "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+)
+
+const (
+	ImageRemovalsCounter     = "image_removals_run_total"
+	ImageRemovalsDescription = "total images considered for removal, by outcome"
+
+	OutcomeRemoved  = "removed"
+	OutcomeExcluded = "excluded"
+	OutcomeRunning  = "running"
+	OutcomeNotFound = "not-found"
+	OutcomeError    = "error"
+
+	SourceImageList      = "imagelist"
+	SourceCollectorPrune = "collector-prune"
+	SourceScanner        = "scanner"
+
+	// OtherRepository is the label of repositories beyond the limit.
+	OtherRepository = "other"
+	// UnknownRepository is the label of images which have no name.
+	UnknownRepository = "unknown"
+
+	outcomeAttribute    = "outcome"
+	sourceAttribute     = "source"
+	repositoryAttribute = "repository"
+)
+
+type (
+	// Removal is the number of images with the same outcome and, if
+	// repository labels are enabled, the same repository.
+	Removal struct {
+		Outcome    string `json:"outcome"`
+		Repository string `json:"repository,omitempty"`
+		Count      int64  `json:"count"`
+	}
+
+	// Removals tallies the outcome of every image the remover considered.
+	Removals struct {
+		maxRepositories int
+		repositories    *RepositoryLimiter
+		counts          map[Removal]int64
+	}
+
+	// RepositoryLimiter bounds the number of distinct repository labels.
+	// Repositories are labelled in the order they are first seen.
+	RepositoryLimiter struct {
+		mu   sync.Mutex
+		seen map[string]struct{}
+	}
+)
+
+// NewRemovals returns a tally which labels removals with the repository of
+// each image, up to maxRepositories of them. Zero disables repository labels.
+func NewRemovals(maxRepositories int) *Removals {
+	return &Removals{
+		maxRepositories: maxRepositories,
+		repositories:    NewRepositoryLimiter(),
+		counts:          make(map[Removal]int64),
+	}
+}
+
+// Add counts one image with the given outcome.
+func (r *Removals) Add(outcome string, img *unversioned.Image) {
+	key := Removal{Outcome: outcome}
+	if r.maxRepositories > 0 {
+		key.Repository = r.repositories.Limit(Repository(img), r.maxRepositories)
+	}
+
+	r.counts[key]++
+}
+
+// Count returns the number of images with the given outcome.
+func (r *Removals) Count(outcome string) int64 {
+	var total int64
+	for key, n := range r.counts {
+		if key.Outcome == outcome {
+			total += n
+		}
+	}
+
+	return total
+}
+
+// List returns the tally sorted by outcome and repository.
+func (r *Removals) List() []Removal {
+	list := make([]Removal, 0, len(r.counts))
+	for key, n := range r.counts {
+		key.Count = n
+		list = append(list, key)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Outcome != list[j].Outcome {
+			return list[i].Outcome < list[j].Outcome
+		}
+		return list[i].Repository < list[j].Repository
+	})
+
+	return list
+}
+
+func NewRepositoryLimiter() *RepositoryLimiter {
+	return &RepositoryLimiter{seen: make(map[string]struct{})}
+}
+
+// Limit returns repo if it is one of the first max repositories seen, and
+// OtherRepository otherwise.
+func (l *RepositoryLimiter) Limit(repo string, max int) string {
+	if repo == "" || repo == OtherRepository {
+		return repo
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.seen[repo]; ok {
+		return repo
+	}
+
+	if len(l.seen) >= max {
+		return OtherRepository
+	}
+
+	l.seen[repo] = struct{}{}
+	return repo
+}
+
+// Repository returns the registry and repository of img, without its tag or
+// digest, from its first name or digest.
+func Repository(img *unversioned.Image) string {
+	var ref string
+	switch {
+	case img == nil:
+	case len(img.Names) > 0:
+		ref = img.Names[0]
+	case len(img.Digests) > 0:
+		ref = img.Digests[0]
+	}
+
+	// an image ID says nothing about where the image came from
+	if strings.HasPrefix(ref, "sha256:") {
+		return UnknownRepository
+	}
+
+	if i := strings.Index(ref, "@"); i >= 0 {
+		ref = ref[:i]
+	}
+
+	// a colon after the last slash separates the tag, while one before it
+	// separates the registry's port
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		ref = ref[:i]
+	}
+
+	if ref == "" {
+		return UnknownRepository
+	}
+
+	return ref
+}
+
+// RecordMetricsRemovals records the outcome of removals on the given node.
+func RecordMetricsRemovals(ctx context.Context, p metric.MeterProvider, nodeName, source string, removals []Removal) error {
+	counter, err := p.Meter("eraser").SyncInt64().Counter(ImageRemovalsCounter, instrument.WithDescription(ImageRemovalsDescription), instrument.WithUnit("1"))
+	if err != nil {
+		return err
+	}
+
+	for _, r := range removals {
+		attrs := []attribute.KeyValue{
+			attribute.String(nodeNameAttribute, nodeName),
+			attribute.String(outcomeAttribute, r.Outcome),
+			attribute.String(sourceAttribute, source),
+		}
+		if r.Repository != "" {
+			attrs = append(attrs, attribute.String(repositoryAttribute, r.Repository))
+		}
+
+		counter.Add(ctx, r.Count, attrs...)
+	}
+
+	return nil
+}
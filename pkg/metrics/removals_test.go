@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+)
+
+func TestRepository(t *testing.T) {
+	tests := map[string]*unversioned.Image{
+		"docker.io/library/nginx":     {Names: []string{"docker.io/library/nginx:1.25"}},
+		"registry.local:5000/app":     {Names: []string{"registry.local:5000/app"}},
+		"registry.local:5000/app/web": {Digests: []string{"registry.local:5000/app/web@sha256:abc"}},
+		UnknownRepository:             {ImageID: "sha256:abc"},
+	}
+
+	for expected, img := range tests {
+		assert.Equal(t, expected, Repository(img))
+	}
+
+	assert.Equal(t, UnknownRepository, Repository(&unversioned.Image{Names: []string{"sha256:abc"}}))
+}
+
+func TestRemovals(t *testing.T) {
+	nginx := &unversioned.Image{Names: []string{"docker.io/library/nginx:1.25"}}
+	redis := &unversioned.Image{Names: []string{"docker.io/library/redis:7"}}
+	envoy := &unversioned.Image{Names: []string{"docker.io/envoyproxy/envoy:v1.28"}}
+
+	r := NewRemovals(2)
+	r.Add(OutcomeRemoved, nginx)
+	r.Add(OutcomeRemoved, nginx)
+	r.Add(OutcomeError, redis)
+	r.Add(OutcomeRemoved, envoy)
+
+	assert.Equal(t, int64(3), r.Count(OutcomeRemoved))
+	assert.Equal(t, []Removal{
+		{Outcome: OutcomeError, Repository: "docker.io/library/redis", Count: 1},
+		{Outcome: OutcomeRemoved, Repository: "docker.io/library/nginx", Count: 2},
+		{Outcome: OutcomeRemoved, Repository: OtherRepository, Count: 1},
+	}, r.List())
+
+	r = NewRemovals(0)
+	r.Add(OutcomeRemoved, nginx)
+	r.Add(OutcomeRemoved, envoy)
+	assert.Equal(t, []Removal{{Outcome: OutcomeRemoved, Count: 2}}, r.List())
+}
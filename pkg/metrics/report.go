@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"encoding/json"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TerminationMessagePath is where job containers write their Report. The
+// kubelet copies it into the container's status when the container exits,
+// so that the manager can read it without any other channel to the pod.
+var TerminationMessagePath = corev1.TerminationMessagePathDefault
+
+// maxReportSize is the size above which the kubelet truncates termination
+// messages.
+const maxReportSize = 4096
+
+// Report holds the counters of one job container.
+type Report struct {
+	ImagesRemoved    int64      `json:"imagesRemoved,omitempty"`
+	VulnerableImages int64      `json:"vulnerableImages,omitempty"`
+	Source           string     `json:"source,omitempty"`
+	Removals         []Removal  `json:"removals,omitempty"`
+	Scan             *ScanStats `json:"scan,omitempty"`
+}
+
+// WriteReport writes r as the container's termination message. If it is
+// too large, repository labels are dropped from the removals.
+func WriteReport(r *Report) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	if len(b) > maxReportSize {
+		collapsed := *r
+		collapsed.Removals = withoutRepositories(r.Removals)
+		if b, err = json.Marshal(&collapsed); err != nil {
+			return err
+		}
+	}
+
+	return os.WriteFile(TerminationMessagePath, b, 0o644)
+}
+
+func withoutRepositories(removals []Removal) []Removal {
+	var collapsed []Removal
+	index := make(map[string]int)
+	for _, r := range removals {
+		i, ok := index[r.Outcome]
+		if !ok {
+			index[r.Outcome] = len(collapsed)
+			collapsed = append(collapsed, Removal{Outcome: r.Outcome, Count: r.Count})
+			continue
+		}
+		collapsed[i].Count += r.Count
+	}
+
+	return collapsed
+}
+
+// PodReport sums the reports of every terminated container in pod.
+// Containers which wrote no report, or something else, are ignored. Only
+// the remover reports removals and only the scanner reports scan stats, so
+// they are not merged.
+func PodReport(pod *corev1.Pod) Report {
+	var total Report
+	for i := range pod.Status.ContainerStatuses {
+		terminated := pod.Status.ContainerStatuses[i].State.Terminated
+		if terminated == nil || terminated.Message == "" {
+			continue
+		}
+
+		var r Report
+		if err := json.Unmarshal([]byte(terminated.Message), &r); err != nil {
+			continue
+		}
+
+		total.ImagesRemoved += r.ImagesRemoved
+		total.VulnerableImages += r.VulnerableImages
+		if r.Source != "" {
+			total.Source = r.Source
+			total.Removals = r.Removals
+		}
+		if r.Scan != nil {
+			total.Scan = r.Scan
+		}
+	}
+
+	return total
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+)
+
+func terminated(message string) corev1.ContainerStatus {
+	return corev1.ContainerStatus{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: message}}}
+}
+
+func TestPodReport(t *testing.T) {
+	pod := &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+		terminated(`{"imagesRemoved":3,"source":"scanner","removals":[{"outcome":"removed","count":3}]}`),
+		terminated(`{"vulnerableImages":2}`),
+		terminated("collector exited"),
+		{State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+	}}}
+
+	assert.Equal(t, Report{
+		ImagesRemoved:    3,
+		VulnerableImages: 2,
+		Source:           SourceScanner,
+		Removals:         []Removal{{Outcome: OutcomeRemoved, Count: 3}},
+	}, PodReport(pod))
+}
+
+func TestWriteReport(t *testing.T) {
+	TerminationMessagePath = filepath.Join(t.TempDir(), "termination-log")
+	t.Cleanup(func() { TerminationMessagePath = corev1.TerminationMessagePathDefault })
+
+	require.NoError(t, WriteReport(&Report{ImagesRemoved: 4}))
+
+	b, err := os.ReadFile(TerminationMessagePath)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"imagesRemoved":4}`, string(b))
+}
+
+func TestWriteReportTooLarge(t *testing.T) {
+	TerminationMessagePath = filepath.Join(t.TempDir(), "termination-log")
+	t.Cleanup(func() { TerminationMessagePath = corev1.TerminationMessagePathDefault })
+
+	r := NewRemovals(1000)
+	for i := 0; i < 200; i++ {
+		r.Add(OutcomeRemoved, &unversioned.Image{Names: []string{fmt.Sprintf("registry.local/team/app-%d:latest", i)}})
+	}
+	r.Add(OutcomeError, &unversioned.Image{Names: []string{"registry.local/team/app-0:latest"}})
+
+	require.NoError(t, WriteReport(&Report{ImagesRemoved: 200, Source: SourceImageList, Removals: r.List()}))
+
+	b, err := os.ReadFile(TerminationMessagePath)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(b), maxReportSize)
+
+	var report Report
+	require.NoError(t, json.Unmarshal(b, &report))
+	assert.Equal(t, []Removal{{Outcome: OutcomeError, Count: 1}, {Outcome: OutcomeRemoved, Count: 200}}, report.Removals)
+}
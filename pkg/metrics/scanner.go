@@ -0,0 +1,172 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	metric "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/metric/instrument/asyncfloat64"
+	"go.opentelemetry.io/otel/metric/instrument/asyncint64"
+	"go.opentelemetry.io/otel/metric/unit"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/aggregation"
+)
+
+const (
+	ScanDurationHistogram = "scanner_scan_duration_seconds"
+	ScanFailuresCounter   = "scanner_failures_run_total"
+	VulnerableImagesGauge = "scanner_vulnerable_images"
+	EOLImagesGauge        = "scanner_eol_images"
+	DBAgeGauge            = "scanner_db_age_seconds"
+
+	severityAttribute = "severity"
+	reasonAttribute   = "reason"
+)
+
+// ScanDurationBuckets are the upper bounds, in seconds, of the scan duration
+// histogram.
+var ScanDurationBuckets = []float64{1, 5, 10, 30, 60, 120, 300, 600, 1800, 3600}
+
+type (
+	// ScanStats summarizes a scanner run on one node.
+	ScanStats struct {
+		// Durations holds one bucket for each of ScanDurationBuckets, and a
+		// last one for longer scans.
+		Durations []DurationBucket `json:"durations,omitempty"`
+		// Failures counts failed scans by reason.
+		Failures map[string]int64 `json:"failures,omitempty"`
+		// Severities counts the images with at least one vulnerability of
+		// each severity.
+		Severities map[string]int64 `json:"severities,omitempty"`
+		EOLImages  int64            `json:"eolImages,omitempty"`
+		DBAge      float64          `json:"dbAgeSeconds,omitempty"`
+	}
+
+	// DurationBucket is the number and total duration of the scans which
+	// fall in one bucket of the histogram.
+	DurationBucket struct {
+		Count int64   `json:"count,omitempty"`
+		Sum   float64 `json:"sum,omitempty"`
+	}
+
+	// ScanRecorder records ScanStats. Its gauges report the latest stats of
+	// each node until they are replaced.
+	ScanRecorder struct {
+		provider metric.MeterProvider
+
+		vulnerable asyncint64.Gauge
+		eol        asyncint64.Gauge
+		dbAge      asyncfloat64.Gauge
+
+		mu    sync.Mutex
+		nodes map[string]*ScanStats
+	}
+)
+
+// ObserveDuration adds the duration of one scan.
+func (s *ScanStats) ObserveDuration(d time.Duration) {
+	if len(s.Durations) == 0 {
+		s.Durations = make([]DurationBucket, len(ScanDurationBuckets)+1)
+	}
+
+	seconds := d.Seconds()
+	i := 0
+	for i < len(ScanDurationBuckets) && seconds > ScanDurationBuckets[i] {
+		i++
+	}
+
+	s.Durations[i].Count++
+	s.Durations[i].Sum += seconds
+}
+
+func scanDurationView() sdkmetric.View {
+	return sdkmetric.NewView(
+		sdkmetric.Instrument{Name: ScanDurationHistogram, Scope: instrumentation.Scope{Name: "eraser"}},
+		sdkmetric.Stream{
+			Name:        ScanDurationHistogram,
+			Unit:        unit.Unit("s"),
+			Aggregation: aggregation.ExplicitBucketHistogram{Boundaries: ScanDurationBuckets},
+		},
+	)
+}
+
+// NewScanRecorder registers the scanner gauges with p.
+func NewScanRecorder(p metric.MeterProvider) (*ScanRecorder, error) {
+	meter := p.Meter("eraser")
+	r := &ScanRecorder{provider: p, nodes: make(map[string]*ScanStats)}
+
+	var err error
+	r.vulnerable, err = meter.AsyncInt64().Gauge(VulnerableImagesGauge, instrument.WithDescription("images with at least one vulnerability of each severity"), instrument.WithUnit("1"))
+	if err != nil {
+		return nil, err
+	}
+
+	r.eol, err = meter.AsyncInt64().Gauge(EOLImagesGauge, instrument.WithDescription("images whose operating system has reached its end of life"), instrument.WithUnit("1"))
+	if err != nil {
+		return nil, err
+	}
+
+	r.dbAge, err = meter.AsyncFloat64().Gauge(DBAgeGauge, instrument.WithDescription("age of the vulnerability DB when the scan finished"), instrument.WithUnit(unit.Unit("s")))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := meter.RegisterCallback([]instrument.Asynchronous{r.vulnerable, r.eol, r.dbAge}, r.observe); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func (r *ScanRecorder) observe(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for node, stats := range r.nodes {
+		nodeAttr := attribute.String(nodeNameAttribute, node)
+		for severity, n := range stats.Severities {
+			r.vulnerable.Observe(ctx, n, nodeAttr, attribute.String(severityAttribute, severity))
+		}
+		r.eol.Observe(ctx, stats.EOLImages, nodeAttr)
+		r.dbAge.Observe(ctx, stats.DBAge, nodeAttr)
+	}
+}
+
+// Record records the scan durations and failures of a run on the given
+// node, and replaces the node's gauges.
+func (r *ScanRecorder) Record(ctx context.Context, nodeName string, stats *ScanStats) error {
+	meter := r.provider.Meter("eraser")
+	nodeAttr := attribute.String(nodeNameAttribute, nodeName)
+
+	duration, err := meter.SyncFloat64().Histogram(ScanDurationHistogram, instrument.WithDescription("duration of each image scan"), instrument.WithUnit(unit.Unit("s")))
+	if err != nil {
+		return err
+	}
+
+	// only the count and sum of each bucket are known, so each scan in a
+	// bucket is recorded as the bucket's mean, which keeps both exact
+	for _, b := range stats.Durations {
+		for i := int64(0); i < b.Count; i++ {
+			duration.Record(ctx, b.Sum/float64(b.Count), nodeAttr)
+		}
+	}
+
+	failures, err := meter.SyncInt64().Counter(ScanFailuresCounter, instrument.WithDescription("total failed image scans, by reason"), instrument.WithUnit("1"))
+	if err != nil {
+		return err
+	}
+
+	for reason, n := range stats.Failures {
+		failures.Add(ctx, n, nodeAttr, attribute.String(reasonAttribute, reason))
+	}
+
+	r.mu.Lock()
+	r.nodes[nodeName] = stats
+	r.mu.Unlock()
+
+	return nil
+}
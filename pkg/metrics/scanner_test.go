@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserveDuration(t *testing.T) {
+	var stats ScanStats
+	stats.ObserveDuration(500 * time.Millisecond)
+	stats.ObserveDuration(time.Second)
+	stats.ObserveDuration(20 * time.Second)
+	stats.ObserveDuration(2 * time.Hour)
+
+	require.Len(t, stats.Durations, len(ScanDurationBuckets)+1)
+	assert.Equal(t, DurationBucket{Count: 2, Sum: 1.5}, stats.Durations[0])
+	assert.Equal(t, DurationBucket{Count: 1, Sum: 20}, stats.Durations[3])
+	assert.Equal(t, DurationBucket{Count: 1, Sum: 7200}, stats.Durations[len(ScanDurationBuckets)])
+}
+
+func TestScanRecorder(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	provider, err := ConfigurePrometheus(reg)
+	require.NoError(t, err)
+
+	recorder, err := NewScanRecorder(provider)
+	require.NoError(t, err)
+
+	stats := &ScanStats{
+		Failures:   map[string]int64{"auth": 2},
+		Severities: map[string]int64{"CRITICAL": 1, "HIGH": 3},
+		EOLImages:  1,
+		DBAge:      3600,
+	}
+	stats.ObserveDuration(2 * time.Second)
+	stats.ObserveDuration(4 * time.Second)
+	stats.ObserveDuration(45 * time.Second)
+
+	ctx := context.Background()
+	require.NoError(t, recorder.Record(ctx, "node-1", stats))
+	// the gauges of a node are replaced by its latest run
+	require.NoError(t, recorder.Record(ctx, "node-1", &ScanStats{Severities: map[string]int64{"CRITICAL": 2}}))
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	byName := map[string]*dto.MetricFamily{}
+	for _, mf := range families {
+		byName[mf.GetName()] = mf
+	}
+
+	require.Contains(t, byName, ScanDurationHistogram)
+	h := byName[ScanDurationHistogram].GetMetric()[0].GetHistogram()
+	assert.Equal(t, uint64(3), h.GetSampleCount())
+	assert.InDelta(t, 51, h.GetSampleSum(), 1e-9)
+
+	require.Contains(t, byName, ScanFailuresCounter)
+	assert.Equal(t, float64(2), byName[ScanFailuresCounter].GetMetric()[0].GetCounter().GetValue())
+
+	require.Contains(t, byName, VulnerableImagesGauge)
+	vulnerable := byName[VulnerableImagesGauge].GetMetric()
+	require.Len(t, vulnerable, 1)
+	assert.Equal(t, float64(2), vulnerable[0].GetGauge().GetValue())
+
+	require.Contains(t, byName, EOLImagesGauge)
+	assert.Equal(t, float64(0), byName[EOLImagesGauge].GetMetric()[0].GetGauge().GetValue())
+}
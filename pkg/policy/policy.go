@@ -0,0 +1,211 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/cel-go/cel"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+	"github.com/eraser-dev/eraser/pkg/utils"
+)
+
+const (
+	varImage   = "image"
+	varVulns   = "vulns"
+	varEOL     = "eol"
+	varAge     = "age"
+	varNode    = "node"
+	varScanned = "scanned"
+
+	SeverityCritical = "critical"
+	SeverityHigh     = "high"
+	SeverityMedium   = "medium"
+	SeverityLow      = "low"
+	SeverityUnknown  = "unknown"
+)
+
+// Rule is a single named CEL expression. An image is removed when any rule
+// evaluates to true.
+type Rule struct {
+	Name       string `json:"name"`
+	Expression string `json:"expression"`
+}
+
+// Facts are the attributes of an image which are exposed to rule expressions.
+// Vulnerability counts and EOL status are only known to the scanner; in the
+// collector and remover they are left empty and Scanned is false.
+type Facts struct {
+	Image      unversioned.Image
+	Created    time.Time
+	Vulns      map[string]int64
+	EOL        bool
+	Scanned    bool
+	NodeName   string
+	NodeLabels map[string]string
+}
+
+type program struct {
+	name string
+	prg  cel.Program
+}
+
+// Engine evaluates a compiled set of rules against image facts.
+type Engine struct {
+	programs []program
+	now      func() time.Time
+}
+
+func newEnv() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable(varImage, cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable(varVulns, cel.MapType(cel.StringType, cel.IntType)),
+		cel.Variable(varEOL, cel.BoolType),
+		cel.Variable(varAge, cel.DurationType),
+		cel.Variable(varNode, cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable(varScanned, cel.BoolType),
+	)
+}
+
+// New compiles the given rules. Every rule must evaluate to a bool.
+func New(rules []Rule) (*Engine, error) {
+	env, err := newEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	e := &Engine{now: time.Now}
+	for _, r := range rules {
+		ast, iss := env.Compile(r.Expression)
+		if iss.Err() != nil {
+			return nil, fmt.Errorf("rule %q: %w", r.Name, iss.Err())
+		}
+
+		// fields of image and node are dyn and can only be checked at runtime
+		if t := ast.OutputType().String(); t != cel.BoolType.String() && t != cel.DynType.String() {
+			return nil, fmt.Errorf("rule %q: expression must evaluate to bool, got %s", r.Name, ast.OutputType())
+		}
+
+		prg, err := env.Program(ast)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", r.Name, err)
+		}
+
+		e.programs = append(e.programs, program{name: r.Name, prg: prg})
+	}
+
+	return e, nil
+}
+
+// FromEnv builds an Engine from the JSON rule list in the ERASER_IMAGE_POLICY
+// environment variable. It returns a nil Engine if no policy was configured.
+func FromEnv() (*Engine, error) {
+	raw := os.Getenv(utils.EnvEraserImagePolicy)
+	if raw == "" {
+		return nil, nil
+	}
+
+	var rules []Rule
+	if err := json.Unmarshal([]byte(raw), &rules); err != nil {
+		return nil, fmt.Errorf("unable to parse image policy: %w", err)
+	}
+
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	return New(rules)
+}
+
+// NodeFromEnv returns the node name and labels made available to the pod by
+// the imagejob controller.
+func NodeFromEnv() (string, map[string]string) {
+	labels := map[string]string{}
+	if raw := os.Getenv(utils.EnvEraserNodeLabels); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &labels); err != nil {
+			labels = map[string]string{}
+		}
+	}
+
+	return os.Getenv("NODE_NAME"), labels
+}
+
+// Evaluate runs each rule in order and returns the name of the first rule
+// which matched. An error from any rule aborts evaluation, and the image is
+// kept.
+func (e *Engine) Evaluate(f *Facts) (bool, string, error) {
+	if e == nil {
+		return true, "", nil
+	}
+
+	activation := e.activation(f)
+	for _, p := range e.programs {
+		out, _, err := p.prg.Eval(activation)
+		if err != nil {
+			return false, p.name, fmt.Errorf("rule %q: %w", p.name, err)
+		}
+
+		matched, ok := out.Value().(bool)
+		if !ok {
+			return false, p.name, fmt.Errorf("rule %q: expression did not evaluate to bool", p.name)
+		}
+
+		if matched {
+			return true, p.name, nil
+		}
+	}
+
+	return false, "", nil
+}
+
+func (e *Engine) activation(f *Facts) map[string]interface{} {
+	names := f.Image.Names
+	if names == nil {
+		names = []string{}
+	}
+
+	digests := f.Image.Digests
+	if digests == nil {
+		digests = []string{}
+	}
+
+	var age time.Duration
+	if !f.Created.IsZero() {
+		age = e.now().Sub(f.Created)
+	}
+
+	vulns := map[string]int64{
+		SeverityCritical: 0,
+		SeverityHigh:     0,
+		SeverityMedium:   0,
+		SeverityLow:      0,
+		SeverityUnknown:  0,
+	}
+	for k, v := range f.Vulns {
+		vulns[strings.ToLower(k)] += v
+	}
+
+	nodeLabels := f.NodeLabels
+	if nodeLabels == nil {
+		nodeLabels = map[string]string{}
+	}
+
+	return map[string]interface{}{
+		varImage: map[string]interface{}{
+			"id":      f.Image.ImageID,
+			"names":   names,
+			"digests": digests,
+			"size":    f.Image.Size,
+			"pinned":  f.Image.Pinned,
+			"created": f.Created,
+		},
+		varVulns:   vulns,
+		varEOL:     f.EOL,
+		varAge:     age,
+		varNode:    map[string]interface{}{"name": f.NodeName, "labels": nodeLabels},
+		varScanned: f.Scanned,
+	}
+}
@@ -0,0 +1,132 @@
+package policy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		desc       string
+		expression string
+		wantErr    bool
+	}{
+		{desc: "valid expression", expression: "vulns.critical > 0 && age > duration('720h')"},
+		{desc: "syntax error", expression: "vulns.critical >", wantErr: true},
+		{desc: "undeclared variable", expression: "foo > 0", wantErr: true},
+		{desc: "non-bool result", expression: "vulns.critical", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			_, err := New([]Rule{{Name: "test", Expression: tt.expression}})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error: %v, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	now := time.Date(2023, time.January, 31, 0, 0, 0, 0, time.UTC)
+	img := unversioned.Image{
+		ImageID: "sha256:abc",
+		Names:   []string{"docker.io/library/alpine:3.7"},
+		Digests: []string{"sha256:def"},
+		Size:    5 * 1024 * 1024,
+	}
+
+	tests := []struct {
+		desc     string
+		rules    []Rule
+		facts    Facts
+		expected bool
+		rule     string
+		wantErr  bool
+	}{
+		{
+			desc:     "critical and old",
+			rules:    []Rule{{Name: "old-critical", Expression: "vulns.critical > 0 && age > duration('720h')"}},
+			facts:    Facts{Image: img, Created: now.Add(-31 * 24 * time.Hour), Vulns: map[string]int64{"CRITICAL": 1}},
+			expected: true,
+			rule:     "old-critical",
+		},
+		{
+			desc:     "critical but new",
+			rules:    []Rule{{Name: "old-critical", Expression: "vulns.critical > 0 && age > duration('720h')"}},
+			facts:    Facts{Image: img, Created: now.Add(-time.Hour), Vulns: map[string]int64{"CRITICAL": 1}},
+			expected: false,
+		},
+		{
+			desc:     "missing severities default to zero",
+			rules:    []Rule{{Name: "clean", Expression: "vulns.high == 0 && vulns.unknown == 0"}},
+			facts:    Facts{Image: img},
+			expected: true,
+			rule:     "clean",
+		},
+		{
+			desc: "first matching rule wins",
+			rules: []Rule{
+				{Name: "pinned", Expression: "image.pinned"},
+				{Name: "alpine", Expression: "image.names.exists(n, n.startsWith('docker.io/library/alpine'))"},
+				{Name: "large", Expression: "image.size > 1024"},
+			},
+			facts:    Facts{Image: img},
+			expected: true,
+			rule:     "alpine",
+		},
+		{
+			desc:     "node labels",
+			rules:    []Rule{{Name: "pool", Expression: "node.labels['pool'] == 'batch'"}},
+			facts:    Facts{Image: img, NodeLabels: map[string]string{"pool": "batch"}},
+			expected: true,
+			rule:     "pool",
+		},
+		{
+			desc:     "unscanned images",
+			rules:    []Rule{{Name: "unscanned-eol", Expression: "scanned && eol"}},
+			facts:    Facts{Image: img, EOL: true},
+			expected: false,
+		},
+		{
+			desc:    "missing map key",
+			rules:   []Rule{{Name: "missing", Expression: "node.labels['pool'] == 'batch'"}},
+			facts:   Facts{Image: img},
+			rule:    "missing",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			e, err := New(tt.rules)
+			if err != nil {
+				t.Fatal(err)
+			}
+			e.now = func() time.Time { return now }
+
+			actual, rule, err := e.Evaluate(&tt.facts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error: %v, got: %v", tt.wantErr, err)
+			}
+			if actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+			if rule != tt.rule {
+				t.Errorf("expected rule %q, got %q", tt.rule, rule)
+			}
+		})
+	}
+}
+
+func TestNilEngine(t *testing.T) {
+	var e *Engine
+	remove, _, err := e.Evaluate(&Facts{})
+	if err != nil || !remove {
+		t.Errorf("expected nil engine to select every image, got %v, %v", remove, err)
+	}
+}
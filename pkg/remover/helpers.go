@@ -3,15 +3,23 @@ package main
 import (
 	"context"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/eraser-dev/eraser/api/unversioned"
 	"github.com/eraser-dev/eraser/pkg/cri"
+	"github.com/eraser-dev/eraser/pkg/metrics"
+	"github.com/eraser-dev/eraser/pkg/policy"
+	"github.com/eraser-dev/eraser/pkg/tracing"
 	util "github.com/eraser-dev/eraser/pkg/utils"
 )
 
-func removeImages(c cri.Remover, targetImages []string) (int, error) {
+// removeImages removes targetImages from the node and counts the outcome
+// for each image in removals.
+func removeImages(ctx context.Context, c cri.Remover, targetImages []string, removals *metrics.Removals) (int, error) {
 	removed := 0
 
-	backgroundContext, cancel := context.WithTimeout(context.Background(), timeout)
+	backgroundContext, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	images, err := c.ListImages(backgroundContext)
@@ -30,6 +38,8 @@ func removeImages(c cri.Remover, targetImages []string) (int, error) {
 		newImg := unversioned.Image{
 			ImageID: img.Id,
 			Names:   repoTags,
+			Size:    int64(img.Size_),
+			Pinned:  img.Pinned,
 		}
 
 		digests, errs := util.ProcessRepoDigests(img.RepoDigests)
@@ -70,52 +80,80 @@ func removeImages(c cri.Remover, targetImages []string) (int, error) {
 		}
 
 		if imageID, isNonRunning := nonRunningImages[imgDigestOrTag]; isNonRunning {
+			img := idToImageMap[imageID]
 			if ex := util.IsExcluded(excluded, imgDigestOrTag, idToImageMap); ex {
-				log.Info("image is excluded", "given", imgDigestOrTag, "imageID", imageID, "name", idToImageMap[imageID])
+				log.Info("image is excluded", "given", imgDigestOrTag, "imageID", imageID, "name", img)
+				removals.Add(metrics.OutcomeExcluded, &img)
+				continue
+			}
+
+			if !selectedByPolicy(imageID, img) {
+				removals.Add(metrics.OutcomeExcluded, &img)
 				continue
 			}
 
-			err = c.DeleteImage(backgroundContext, imageID)
+			err = deleteImage(backgroundContext, c, imageID, &img)
 			if err != nil {
-				log.Error(err, "error removing image", "given", imgDigestOrTag, "imageID", imageID, "name", idToImageMap[imageID])
+				log.Error(err, "error removing image", "given", imgDigestOrTag, "imageID", imageID, "name", img)
+				removals.Add(metrics.OutcomeError, &img)
 				continue
 			}
 
 			deletedImages[imgDigestOrTag] = struct{}{}
-			log.Info("removed image", "given", imgDigestOrTag, "imageID", imageID, "name", idToImageMap[imageID])
+			log.Info("removed image", "given", imgDigestOrTag, "imageID", imageID, "name", img)
+			removals.Add(metrics.OutcomeRemoved, &img)
 			removed++
 			continue
 		}
 
 		imageID, isRunning := runningImages[imgDigestOrTag]
 		if isRunning {
-			log.Info("image is running", "given", imgDigestOrTag, "imageID", imageID, "name", idToImageMap[imageID])
+			img := idToImageMap[imageID]
+			log.Info("image is running", "given", imgDigestOrTag, "imageID", imageID, "name", img)
+			removals.Add(metrics.OutcomeRunning, &img)
 			continue
 		}
 
 		log.Info("image is not on node", "given", imgDigestOrTag)
+		removals.Add(metrics.OutcomeNotFound, &unversioned.Image{Names: []string{imgDigestOrTag}})
 	}
 
 	if prune {
 		success := true
+		// nonRunningImages has an entry for each name and digest of an image,
+		// so each image is only considered once
+		considered := make(map[string]struct{}, len(nonRunningImages))
 		for _, imageID := range nonRunningImages {
 			if _, deleted := deletedImages[imageID]; deleted {
 				continue
 			}
+			if _, ok := considered[imageID]; ok {
+				continue
+			}
+			considered[imageID] = struct{}{}
 
+			img := idToImageMap[imageID]
 			if util.IsExcluded(excluded, imageID, idToImageMap) {
-				log.Info("image is excluded", "imageID", imageID, "name", idToImageMap[imageID])
+				log.Info("image is excluded", "imageID", imageID, "name", img)
+				removals.Add(metrics.OutcomeExcluded, &img)
 				continue
 			}
 
-			if err := c.DeleteImage(backgroundContext, imageID); err != nil {
+			if !selectedByPolicy(imageID, img) {
+				removals.Add(metrics.OutcomeExcluded, &img)
+				continue
+			}
+
+			if err := deleteImage(backgroundContext, c, imageID, &img); err != nil {
 				success = false
-				log.Error(err, "error removing image", "imageID", imageID, "name", idToImageMap[imageID])
+				log.Error(err, "error removing image", "imageID", imageID, "name", img)
+				removals.Add(metrics.OutcomeError, &img)
 				continue
 			}
 
 			log.Info("removed image", "digest", imageID)
 			deletedImages[imageID] = struct{}{}
+			removals.Add(metrics.OutcomeRemoved, &img)
 			removed++
 		}
 		if success {
@@ -127,3 +165,36 @@ func removeImages(c cri.Remover, targetImages []string) (int, error) {
 
 	return removed, nil
 }
+
+// deleteImage deletes an image in a span of its own.
+func deleteImage(ctx context.Context, c cri.Remover, imageID string, img *unversioned.Image) error {
+	ctx, span := tracing.Tracer().Start(ctx, "remover.deleteImage", trace.WithAttributes(
+		attribute.String("image.id", imageID),
+		attribute.StringSlice("image.names", img.Names),
+	))
+
+	err := c.DeleteImage(ctx, imageID)
+	tracing.End(span, err)
+
+	return err
+}
+
+func selectedByPolicy(imageID string, img unversioned.Image) bool {
+	if imagePolicy == nil {
+		return true
+	}
+
+	remove, rule, err := imagePolicy.Evaluate(&policy.Facts{Image: img, NodeName: nodeName, NodeLabels: nodeLabels})
+	if err != nil {
+		log.Error(err, "error evaluating image policy", "imageID", imageID)
+		return false
+	}
+
+	if !remove {
+		log.Info("image not selected by policy", "imageID", imageID, "name", img.Names)
+		return false
+	}
+
+	log.V(1).Info("image selected by policy", "imageID", imageID, "rule", rule)
+	return true
+}
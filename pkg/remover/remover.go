@@ -11,15 +11,20 @@ import (
 	_ "net/http/pprof"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric/global"
+	"go.opentelemetry.io/otel/trace"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/eraser-dev/eraser/pkg/cri"
 	"github.com/eraser-dev/eraser/pkg/logger"
 	"github.com/eraser-dev/eraser/pkg/metrics"
+	"github.com/eraser-dev/eraser/pkg/policy"
+	"github.com/eraser-dev/eraser/pkg/tracing"
 
 	"github.com/eraser-dev/eraser/api/unversioned"
 	util "github.com/eraser-dev/eraser/pkg/utils"
@@ -29,11 +34,18 @@ var (
 	imageListPtr  = flag.String("imagelist", "", "name of ImageList")
 	enableProfile = flag.Bool("enable-pprof", false, "enable pprof profiling")
 	profilePort   = flag.Int("pprof-port", 6060, "port for pprof profiling. defaulted to 6060 if unspecified")
+	scanDisabled  = flag.Bool("scan-disabled", false, "whether the images to remove were collected without scanning")
 
 	// Timeout  of connecting to server (default: 5m).
 	timeout  = 5 * time.Minute
 	log      = logf.Log.WithName("remover")
 	excluded map[string]struct{}
+
+	// imagePolicy is only set when removing images from an ImageList; in the
+	// collector flow the collector or scanner has already applied it.
+	imagePolicy *policy.Engine
+	nodeName    string
+	nodeLabels  map[string]string
 )
 
 const (
@@ -110,6 +122,13 @@ func main() {
 			os.Exit(generalErr)
 		}
 		log.Info("successfully parsed image list file")
+
+		imagePolicy, err = policy.FromEnv()
+		if err != nil {
+			log.Error(err, "failed to load image policy")
+			os.Exit(generalErr)
+		}
+		nodeName, nodeLabels = policy.NodeFromEnv()
 	}
 
 	excluded, err = util.ParseExcluded()
@@ -123,23 +142,55 @@ func main() {
 		log.Info("no images to exclude")
 	}
 
-	removed, err := removeImages(client, imagelist)
+	// repository labels are disabled unless the manager sets a limit
+	maxRepositories, _ := strconv.Atoi(os.Getenv(util.EnvEraserMetricsMaxRepositories))
+	removals := metrics.NewRemovals(maxRepositories)
+
+	ctx, shutdownTracing, err := tracing.ConfigureFromEnv(context.Background())
+	if err != nil {
+		log.Error(err, "unable to configure tracing")
+	}
+
+	ctx, span := tracing.Tracer().Start(ctx, "remover.removeImages", trace.WithAttributes(attribute.String("source", removalSource())))
+	removed, err := removeImages(ctx, client, imagelist, removals)
+	span.SetAttributes(attribute.Int("images.removed", removed))
+	tracing.End(span, err)
+
+	if err := shutdownTracing(context.Background()); err != nil {
+		log.Error(err, "unable to export traces")
+	}
+
 	if err != nil {
 		log.Error(err, "failed to remove images")
 		os.Exit(generalErr)
 	}
 
-	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" {
+	source := removalSource()
+	report := metrics.Report{ImagesRemoved: int64(removed), Source: source, Removals: removals.List()}
+	if err := metrics.WriteReport(&report); err != nil {
+		log.Error(err, "unable to write metrics report", "path", metrics.TerminationMessagePath)
+	}
+
+	if os.Getenv(metrics.EnvOTLPEndpoint) != "" {
 		// record metrics
 		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 
-		exporter, reader, provider := metrics.ConfigureMetrics(ctx, log, os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"))
-		global.SetMeterProvider(provider)
-
-		if err := metrics.RecordMetricsRemover(ctx, global.MeterProvider(), int64(removed)); err != nil {
-			log.Error(err, "error recording metrics")
+		exporterCfg, err := metrics.ExporterConfigFromEnv()
+		if err != nil {
+			log.Error(err, "error configuring exporter")
+		} else if exporter, reader, provider, err := metrics.ConfigureMetrics(ctx, exporterCfg); err != nil {
+			log.Error(err, "error configuring metrics")
+		} else {
+			global.SetMeterProvider(provider)
+
+			if err := metrics.RecordMetricsRemover(ctx, global.MeterProvider(), int64(removed)); err != nil {
+				log.Error(err, "error recording metrics")
+			}
+			if err := metrics.RecordMetricsRemovals(ctx, global.MeterProvider(), os.Getenv("NODE_NAME"), source, report.Removals); err != nil {
+				log.Error(err, "error recording metrics")
+			}
+			metrics.ExportMetrics(log, exporter, reader)
 		}
-		metrics.ExportMetrics(log, exporter, reader)
 		cancel()
 	}
 
@@ -175,3 +226,15 @@ func main() {
 		file.Close()
 	}
 }
+
+// removalSource returns where the images to remove came from.
+func removalSource() string {
+	switch {
+	case *imageListPtr != "":
+		return metrics.SourceImageList
+	case *scanDisabled:
+		return metrics.SourceCollectorPrune
+	default:
+		return metrics.SourceScanner
+	}
+}
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"context"
 	"testing"
 
 	v1 "k8s.io/cri-api/pkg/apis/runtime/v1"
+
+	"github.com/eraser-dev/eraser/pkg/metrics"
 )
 
 func TestRemoveImages(t *testing.T) {
@@ -51,7 +54,7 @@ func TestRemoveImages(t *testing.T) {
 				}
 			}
 
-			_, err := removeImages(client, tc.remove)
+			_, err := removeImages(context.Background(), client, tc.remove, metrics.NewRemovals(0))
 			if tc.shouldErr && err == nil {
 				t.Fatal("expected error, got none")
 			}
@@ -86,3 +89,41 @@ func TestRemoveImages(t *testing.T) {
 		})
 	}
 }
+
+func TestRemoveImagesOutcomes(t *testing.T) {
+	excluded = map[string]struct{}{image2.RepoTags[0]: {}}
+	t.Cleanup(func() { excluded = nil })
+
+	client := &testClient{
+		t:          t,
+		containers: []*v1.Container{&container1},
+		images:     []*v1.Image{&image1, &image2, &image3},
+	}
+
+	removals := metrics.NewRemovals(10)
+	removed, err := removeImages(context.Background(), client, []string{image1.Id, image2.Id, image3.Id, "docker.io/library/missing:1.0"}, removals)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if removed != 1 {
+		t.Errorf("expected 1 image to be removed, got %d", removed)
+	}
+
+	expected := []metrics.Removal{
+		{Outcome: metrics.OutcomeExcluded, Repository: "mcr.microsoft.com/containernetworking/azure-npm", Count: 1},
+		{Outcome: metrics.OutcomeNotFound, Repository: "docker.io/library/missing", Count: 1},
+		{Outcome: metrics.OutcomeRemoved, Repository: "mcr.microsoft.com/aks/acc/sgx-webhook", Count: 1},
+		{Outcome: metrics.OutcomeRunning, Repository: "mcr.microsoft.com/oss/kubernetes/ip-masq-agent", Count: 1},
+	}
+
+	actual := removals.List()
+	if len(actual) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected[i], actual[i])
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+
+	"github.com/eraser-dev/eraser/pkg/logger"
+	"github.com/eraser-dev/eraser/pkg/policy"
+	"github.com/eraser-dev/eraser/pkg/scanners/template"
+	"github.com/eraser-dev/eraser/pkg/utils"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	generalErr = 1
+
+	severityCritical   = "CRITICAL"
+	severityHigh       = "HIGH"
+	severityMedium     = "MEDIUM"
+	severityLow        = "LOW"
+	severityNegligible = "NEGLIGIBLE"
+	severityUnknown    = "UNKNOWN"
+
+	fixStateFixed    = "fixed"
+	fixStateNotFixed = "not-fixed"
+	fixStateWontFix  = "wont-fix"
+	fixStateUnknown  = "unknown"
+)
+
+var (
+	config        = flag.String("config", "", "path to the configuration file")
+	enableProfile = flag.Bool("enable-pprof", false, "enable pprof profiling")
+	profilePort   = flag.Int("pprof-port", 6060, "port for pprof profiling. defaulted to 6060 if unspecified")
+
+	log = logf.Log.WithName("scanner").WithValues("provider", "grype")
+
+	// This can be overwritten by the linker.
+	grypeVersion = "dev"
+)
+
+func main() {
+	flag.Parse()
+
+	err := logger.Configure()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error setting up logger: %s", err)
+		os.Exit(generalErr)
+	}
+
+	log.Info("grype version", "grype version", grypeVersion)
+	log.Info("config", "config", *config)
+
+	userConfig := *DefaultConfig()
+	if err := template.LoadConfig(*config, &userConfig); err != nil {
+		log.Error(err, "unable to read config")
+		os.Exit(generalErr)
+	}
+
+	log.V(1).Info("userConfig",
+		"json", userConfig,
+		"struct", fmt.Sprintf("%#v\n", userConfig),
+	)
+
+	if *enableProfile {
+		go template.RunProfileServer(log, *profilePort)
+	}
+
+	ctx := context.Background()
+	provider := template.NewImageProvider(
+		template.WithContext(ctx),
+		template.WithLogger(log),
+		template.WithMetrics(os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != ""),
+		template.WithDeleteScanFailedImages(userConfig.DeleteFailedImages),
+	)
+
+	err = template.Run(ctx, provider, log, func() (template.ScanFunc, error) {
+		s, err := initScanner(&userConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(ctx context.Context, allImages []unversioned.Image) ([]unversioned.Image, []unversioned.Image, error) {
+			return scan(ctx, s, allImages)
+		}, nil
+	})
+	if err != nil {
+		log.Error(err, "unable to scan images")
+		os.Exit(generalErr)
+	}
+}
+
+func initScanner(userConfig *Config) (Scanner, error) {
+	if userConfig == nil {
+		return nil, fmt.Errorf("invalid grype scanner config")
+	}
+
+	userConfig.Runtime = unversioned.RuntimeSpec{
+		Name:    unversioned.Runtime(os.Getenv(utils.EnvEraserRuntimeName)),
+		Address: utils.CRIPath,
+	}
+
+	imagePolicy, err := policy.FromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("error loading image policy: %w", err)
+	}
+	nodeName, nodeLabels := policy.NodeFromEnv()
+
+	totalTimeout := time.Duration(userConfig.Timeout.Total)
+	timer := time.NewTimer(totalTimeout)
+
+	var s Scanner = &ImageScanner{
+		config:     *userConfig,
+		timer:      timer,
+		policy:     imagePolicy,
+		nodeName:   nodeName,
+		nodeLabels: nodeLabels,
+	}
+	return s, nil
+}
+
+func scan(ctx context.Context, s Scanner, allImages []unversioned.Image) ([]unversioned.Image, []unversioned.Image, error) {
+	results, err := template.ScanImages(ctx, log, s.Timer(), allImages, 1, s.Scan)
+	vulnerableImages, failedImages := template.SplitResults(results)
+
+	for _, img := range vulnerableImages {
+		log.Info("vulnerable image found", "img", img)
+	}
+
+	log.Info("Vulnerable", "Images", vulnerableImages, "Total count", len(vulnerableImages))
+
+	if len(failedImages) > 0 {
+		log.Info("Failed", "Images", failedImages)
+	}
+
+	return vulnerableImages, failedImages, err
+}
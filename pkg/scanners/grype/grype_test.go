@@ -0,0 +1,31 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/eraser-dev/eraser/pkg/utils"
+)
+
+func TestInitScannerInvalidPolicy(t *testing.T) {
+	t.Setenv(utils.EnvEraserImagePolicy, `[{"name":"old",`)
+
+	s, err := initScanner(DefaultConfig())
+	if err == nil {
+		t.Fatal("expected an error for a malformed image policy")
+	}
+	if s != nil {
+		t.Errorf("expected no scanner, got %v", s)
+	}
+}
+
+func TestInitScanner(t *testing.T) {
+	t.Setenv(utils.EnvEraserImagePolicy, `[{"name":"critical","expression":"vulns.CRITICAL > 0"}]`)
+
+	s, err := initScanner(DefaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s == nil || s.Timer() == nil {
+		t.Error("expected a scanner with a timer")
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+// The types below mirror the subset of grype's JSON output (`--output=json`)
+// that the scanner needs in order to reach a verdict. They are declared here
+// instead of importing grype so the scanner does not pull in grype's module
+// graph; unknown fields are ignored when unmarshaling.
+type (
+	document struct {
+		Matches    []match    `json:"matches"`
+		Distro     distro     `json:"distro"`
+		Descriptor descriptor `json:"descriptor"`
+	}
+
+	match struct {
+		Vulnerability vulnerability `json:"vulnerability"`
+		Artifact      artifact      `json:"artifact"`
+	}
+
+	vulnerability struct {
+		ID       string `json:"id"`
+		Severity string `json:"severity"`
+		Fix      fix    `json:"fix"`
+	}
+
+	fix struct {
+		Versions []string `json:"versions"`
+		State    string   `json:"state"`
+	}
+
+	artifact struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+		Type    string `json:"type"`
+	}
+
+	distro struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+
+	descriptor struct {
+		Name    string         `json:"name"`
+		Version string         `json:"version"`
+		DB      dbStatusReport `json:"db"`
+	}
+
+	dbStatusReport struct {
+		Built         string `json:"built"`
+		SchemaVersion int    `json:"schemaVersion"`
+		Location      string `json:"location"`
+	}
+)
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+	"github.com/eraser-dev/eraser/pkg/policy"
+	"github.com/eraser-dev/eraser/pkg/scanners/template"
+	"github.com/eraser-dev/eraser/pkg/utils"
+)
+
+const (
+	StatusFailed       = template.StatusFailed
+	StatusNonCompliant = template.StatusNonCompliant
+	StatusOK           = template.StatusOK
+	ImgSrcPodman       = "podman"
+	ImgSrcDocker       = "docker"
+	ImgSrcContainerd   = "containerd"
+)
+
+const (
+	grypeCommandName      = "/grype"
+	grypeJSONOutputFlag   = "--output=json"
+	grypeOnlyFixedFlag    = "--only-fixed"
+	grypeDBCacheDirEnv    = "GRYPE_DB_CACHE_DIR"
+	grypeDBUpdateURLEnv   = "GRYPE_DB_UPDATE_URL"
+	grypeDBAutoUpdateEnv  = "GRYPE_DB_AUTO_UPDATE"
+	grypeCheckForAppUpEnv = "GRYPE_CHECK_FOR_APP_UPDATE"
+)
+
+type (
+	Config struct {
+		Runtime            unversioned.RuntimeSpec `json:"runtime,omitempty"`
+		DBCacheDir         string                  `json:"dbCacheDir,omitempty"`
+		DBUpdateURL        string                  `json:"dbUpdateURL,omitempty"`
+		DeleteFailedImages bool                    `json:"deleteFailedImages,omitempty"`
+		Vulnerabilities    VulnConfig              `json:"vulnerabilities,omitempty"`
+		Timeout            TimeoutConfig           `json:"timeout,omitempty"`
+	}
+
+	VulnConfig struct {
+		IgnoreUnfixed bool     `json:"ignoreUnfixed,omitempty"`
+		Severities    []string `json:"severities,omitempty"`
+		IgnoredStates []string `json:"ignoredStates,omitempty"`
+	}
+
+	TimeoutConfig = template.TimeoutConfig
+
+	ScanStatus = template.ScanStatus
+
+	Scanner interface {
+		Scan(context.Context, unversioned.Image) (ScanStatus, error)
+		Timer() *time.Timer
+	}
+)
+
+func DefaultConfig() *Config {
+	return &Config{
+		Runtime: unversioned.RuntimeSpec{
+			Name:    unversioned.RuntimeContainerd,
+			Address: utils.CRIPath,
+		},
+		DBCacheDir:         "/var/lib/grype",
+		DBUpdateURL:        "https://toolbox-data.anchore.io/grype/databases/listing.json",
+		DeleteFailedImages: true,
+		Vulnerabilities: VulnConfig{
+			IgnoreUnfixed: false,
+			Severities:    []string{severityCritical, severityHigh, severityMedium, severityLow},
+			IgnoredStates: []string{},
+		},
+		Timeout: TimeoutConfig{
+			Total:    unversioned.Duration(time.Hour * 23),
+			PerImage: unversioned.Duration(time.Hour),
+		},
+	}
+}
+
+func (c *Config) cliArgs(ref string) []string {
+	args := []string{}
+
+	runtimeVar, err := c.getRuntimeVar()
+	if err != nil {
+		log.Error(err, "invalid runtime provided")
+	}
+
+	args = append(args, fmt.Sprintf("%s:%s", runtimeVar, ref), grypeJSONOutputFlag)
+
+	if c.Vulnerabilities.IgnoreUnfixed {
+		args = append(args, grypeOnlyFixedFlag)
+	}
+
+	return args
+}
+
+// grype reads its database settings from the environment rather than from
+// flags, so the configured DB location is passed along this way.
+func (c *Config) envVars() []string {
+	env := []string{fmt.Sprintf("%s=false", grypeCheckForAppUpEnv)}
+
+	if c.DBCacheDir != "" {
+		env = append(env, fmt.Sprintf("%s=%s", grypeDBCacheDirEnv, c.DBCacheDir))
+	}
+
+	if c.DBUpdateURL != "" {
+		env = append(env, fmt.Sprintf("%s=%s", grypeDBUpdateURLEnv, c.DBUpdateURL))
+	} else {
+		env = append(env, fmt.Sprintf("%s=false", grypeDBAutoUpdateEnv))
+	}
+
+	return env
+}
+
+func (c *Config) getRuntimeVar() (string, error) {
+	var imgsrc string
+	runtimeName := c.Runtime.Name
+	switch runtimeName {
+	case unversioned.RuntimeCrio:
+		imgsrc = ImgSrcPodman
+	case unversioned.RuntimeDockerShim:
+		imgsrc = ImgSrcDocker
+	case unversioned.RuntimeContainerd, unversioned.Runtime(""):
+		imgsrc = ImgSrcContainerd
+	default:
+		return "", fmt.Errorf("invalid runtime provided: %q", runtimeName)
+	}
+	return imgsrc, nil
+}
+
+// countsAsVulnerable reports whether a single grype match should make an
+// image non-compliant under the configured severities and ignored fix states.
+func (c *Config) countsAsVulnerable(m *match) bool {
+	for _, state := range c.Vulnerabilities.IgnoredStates {
+		if strings.EqualFold(state, m.Vulnerability.Fix.State) {
+			return false
+		}
+	}
+
+	if len(c.Vulnerabilities.Severities) == 0 {
+		return true
+	}
+
+	for _, severity := range c.Vulnerabilities.Severities {
+		if strings.EqualFold(severity, m.Vulnerability.Severity) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type ImageScanner struct {
+	config Config
+	timer  *time.Timer
+
+	policy     *policy.Engine
+	nodeName   string
+	nodeLabels map[string]string
+}
+
+func (s *ImageScanner) Scan(ctx context.Context, img unversioned.Image) (ScanStatus, error) {
+	refs := make([]string, 0, len(img.Names)+len(img.Digests))
+	refs = append(refs, img.Digests...)
+	refs = append(refs, img.Names...)
+	scanSucceeded := false
+
+	log.Info("scanning image with id", "imageID", img.ImageID, "refs", refs)
+	for i := 0; i < len(refs) && !scanSucceeded; i++ {
+		log.Info("scanning image with ref", "ref", refs[i])
+
+		stdout := new(bytes.Buffer)
+		stderr := new(bytes.Buffer)
+
+		refCtx, cancel := ctx, context.CancelFunc(func() {})
+		if s.config.Timeout.PerImage != 0 {
+			refCtx, cancel = context.WithTimeout(ctx, time.Duration(s.config.Timeout.PerImage))
+		}
+
+		cliArgs := s.config.cliArgs(refs[i])
+		cmd := exec.CommandContext(refCtx, grypeCommandName, cliArgs...)
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		cmd.Env = append(cmd.Env, os.Environ()...)
+		cmd.Env = append(cmd.Env, s.config.envVars()...)
+		cmd.Env = append(cmd.Env, template.RuntimeSocketEnv(log, s.config.Runtime)...)
+
+		log.V(1).Info("scanning image ref", "ref", refs[i], "cli_invocation", fmt.Sprintf("%s %s", grypeCommandName, strings.Join(cliArgs, " ")), "env", cmd.Env)
+		err := cmd.Run()
+		cancel()
+		if err != nil {
+			log.Error(err, "error scanning image", "imageID", img.ImageID, "reference", refs[i], "stderr", stderr.String())
+			continue
+		}
+
+		var doc document
+		if err := json.Unmarshal(stdout.Bytes(), &doc); err != nil {
+			log.Error(err, "error unmarshaling report", "imageID", img.ImageID, "reference", refs[i], "report", stdout.String(), "stderr", stderr.String())
+			continue
+		}
+
+		if s.policy != nil {
+			return s.evaluatePolicy(img, &doc), nil
+		}
+
+		for j := range doc.Matches {
+			if s.config.countsAsVulnerable(&doc.Matches[j]) {
+				return StatusNonCompliant, nil
+			}
+		}
+
+		// causes a break from the loop
+		scanSucceeded = true
+	}
+
+	status := StatusOK
+	if !scanSucceeded {
+		status = StatusFailed
+	}
+
+	return status, nil
+}
+
+// evaluatePolicy replaces the default "any vulnerability" verdict with the
+// configured ImagePolicy. Grype does not report EOL status or the image
+// creation time, so eol is always false and age is always zero.
+func (s *ImageScanner) evaluatePolicy(img unversioned.Image, doc *document) ScanStatus {
+	facts := policy.Facts{
+		Image:      img,
+		Vulns:      map[string]int64{},
+		Scanned:    true,
+		NodeName:   s.nodeName,
+		NodeLabels: s.nodeLabels,
+	}
+
+	for j := range doc.Matches {
+		if s.config.countsAsVulnerable(&doc.Matches[j]) {
+			facts.Vulns[doc.Matches[j].Vulnerability.Severity]++
+		}
+	}
+
+	remove, rule, err := s.policy.Evaluate(&facts)
+	if err != nil {
+		// the image is kept, as it is by the collector and remover, rather
+		// than counted as a failed scan, which may be deleted
+		log.Error(err, "error evaluating image policy, keeping image", "imageID", img.ImageID)
+		return StatusOK
+	}
+
+	if remove {
+		log.Info("image selected by policy", "imageID", img.ImageID, "rule", rule)
+		return StatusNonCompliant
+	}
+
+	return StatusOK
+}
+
+func (s *ImageScanner) Timer() *time.Timer {
+	return s.timer
+}
+
+var _ Scanner = &ImageScanner{}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+	"github.com/eraser-dev/eraser/pkg/policy"
+)
+
+const ref = "image:tag"
+
+func TestCLIArgs(t *testing.T) {
+	type testCell struct {
+		desc     string
+		config   Config
+		expected []string
+	}
+
+	tests := []testCell{
+		{
+			desc:   "empty config",
+			config: Config{},
+			// default container runtime is containerd
+			expected: []string{"containerd:" + ref, "--output=json"},
+		},
+		{
+			desc:     "DeleteFailedImages has no effect",
+			config:   Config{DeleteFailedImages: true},
+			expected: []string{"containerd:" + ref, "--output=json"},
+		},
+		{
+			desc:     "alternative runtime crio",
+			config:   Config{Runtime: unversioned.RuntimeSpec{Name: unversioned.RuntimeCrio, Address: unversioned.CrioPath}},
+			expected: []string{"podman:" + ref, "--output=json"},
+		},
+		{
+			desc:     "alternative runtime dockershim",
+			config:   Config{Runtime: unversioned.RuntimeSpec{Name: unversioned.RuntimeDockerShim, Address: unversioned.DockerPath}},
+			expected: []string{"docker:" + ref, "--output=json"},
+		},
+		{
+			desc:     "DB location has no effect",
+			config:   Config{DBCacheDir: "/var/lib/grype", DBUpdateURL: "http://example.test/listing.json"},
+			expected: []string{"containerd:" + ref, "--output=json"},
+		},
+		{
+			desc:     "ignore unfixed",
+			config:   Config{Vulnerabilities: VulnConfig{IgnoreUnfixed: true}},
+			expected: []string{"containerd:" + ref, "--output=json", "--only-fixed"},
+		},
+		{
+			desc:     "severities and states have no effect",
+			config:   Config{Vulnerabilities: VulnConfig{Severities: []string{"LOW"}, IgnoredStates: []string{fixStateWontFix}}},
+			expected: []string{"containerd:" + ref, "--output=json"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			actual := tt.config.cliArgs(ref)
+			if strings.Join(actual, " ") != strings.Join(tt.expected, " ") {
+				t.Errorf("expected result `%s`, but got `%s`", strings.Join(tt.expected, " "), strings.Join(actual, " "))
+			}
+		})
+	}
+}
+
+func TestEnvVars(t *testing.T) {
+	tests := []struct {
+		desc     string
+		config   Config
+		expected []string
+	}{
+		{
+			desc:     "empty config disables DB updates",
+			config:   Config{},
+			expected: []string{"GRYPE_CHECK_FOR_APP_UPDATE=false", "GRYPE_DB_AUTO_UPDATE=false"},
+		},
+		{
+			desc:   "cache dir and update URL",
+			config: Config{DBCacheDir: "/var/lib/grype", DBUpdateURL: "http://example.test/listing.json"},
+			expected: []string{
+				"GRYPE_CHECK_FOR_APP_UPDATE=false",
+				"GRYPE_DB_CACHE_DIR=/var/lib/grype",
+				"GRYPE_DB_UPDATE_URL=http://example.test/listing.json",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			actual := tt.config.envVars()
+			if strings.Join(actual, " ") != strings.Join(tt.expected, " ") {
+				t.Errorf("expected env `%s`, but got `%s`", strings.Join(tt.expected, " "), strings.Join(actual, " "))
+			}
+		})
+	}
+}
+
+func TestCountsAsVulnerable(t *testing.T) {
+	critical := match{Vulnerability: vulnerability{ID: "CVE-1", Severity: "Critical", Fix: fix{State: fixStateFixed}}}
+	negligible := match{Vulnerability: vulnerability{ID: "CVE-2", Severity: "Negligible", Fix: fix{State: fixStateWontFix}}}
+
+	tests := []struct {
+		desc     string
+		vulns    VulnConfig
+		match    match
+		expected bool
+	}{
+		{desc: "no severities counts everything", vulns: VulnConfig{}, match: negligible, expected: true},
+		{desc: "severity matched case-insensitively", vulns: VulnConfig{Severities: []string{severityCritical}}, match: critical, expected: true},
+		{desc: "severity not configured", vulns: VulnConfig{Severities: []string{severityCritical, severityHigh}}, match: negligible, expected: false},
+		{desc: "ignored fix state", vulns: VulnConfig{IgnoredStates: []string{fixStateWontFix}}, match: negligible, expected: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			c := Config{Vulnerabilities: tt.vulns}
+			if actual := c.countsAsVulnerable(&tt.match); actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestEvaluatePolicy(t *testing.T) {
+	tests := []struct {
+		desc       string
+		expression string
+		expected   ScanStatus
+	}{
+		{desc: "selected", expression: "node.name == 'node-1'", expected: StatusNonCompliant},
+		{desc: "not selected", expression: "node.name == 'node-2'", expected: StatusOK},
+		// reading a label which the node does not have fails to evaluate
+		{desc: "unset node label", expression: "node.labels['team'] == 'a'", expected: StatusOK},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			engine, err := policy.New([]policy.Rule{{Name: "test", Expression: tt.expression}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := &ImageScanner{policy: engine, nodeName: "node-1", nodeLabels: map[string]string{}}
+			if got := s.evaluatePolicy(unversioned.Image{ImageID: "sha256:abc"}, &document{}); got != tt.expected {
+				t.Errorf("expected status %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
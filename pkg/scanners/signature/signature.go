@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+
+	"github.com/eraser-dev/eraser/pkg/logger"
+	"github.com/eraser-dev/eraser/pkg/scanners/template"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	generalErr = 1
+
+	verifierCosign   = "cosign"
+	verifierNotation = "notation"
+)
+
+var (
+	config        = flag.String("config", "", "path to the configuration file")
+	enableProfile = flag.Bool("enable-pprof", false, "enable pprof profiling")
+	profilePort   = flag.Int("pprof-port", 6060, "port for pprof profiling. defaulted to 6060 if unspecified")
+
+	log = logf.Log.WithName("scanner").WithValues("provider", "signature")
+
+	// These can be overwritten by the linker.
+	cosignVersion   = "dev"
+	notationVersion = "dev"
+)
+
+func main() {
+	flag.Parse()
+
+	err := logger.Configure()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error setting up logger: %s", err)
+		os.Exit(generalErr)
+	}
+
+	log.Info("verifier versions", "cosign version", cosignVersion, "notation version", notationVersion)
+	log.Info("config", "config", *config)
+
+	userConfig := *DefaultConfig()
+	if err := template.LoadConfig(*config, &userConfig); err != nil {
+		log.Error(err, "unable to read config")
+		os.Exit(generalErr)
+	}
+
+	log.V(1).Info("userConfig",
+		"json", userConfig,
+		"struct", fmt.Sprintf("%#v\n", userConfig),
+	)
+
+	if *enableProfile {
+		go template.RunProfileServer(log, *profilePort)
+	}
+
+	ctx := context.Background()
+	provider := template.NewImageProvider(
+		template.WithContext(ctx),
+		template.WithLogger(log),
+		template.WithMetrics(os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != ""),
+		template.WithDeleteScanFailedImages(userConfig.DeleteFailedImages),
+	)
+
+	err = template.Run(ctx, provider, log, func() (template.ScanFunc, error) {
+		s, err := initScanner(&userConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(ctx context.Context, allImages []unversioned.Image) ([]unversioned.Image, []unversioned.Image, error) {
+			return scan(ctx, s, allImages)
+		}, nil
+	})
+	if err != nil {
+		log.Error(err, "unable to scan images")
+		os.Exit(generalErr)
+	}
+}
+
+func initScanner(userConfig *Config) (Scanner, error) {
+	if userConfig == nil {
+		return nil, fmt.Errorf("invalid signature scanner config")
+	}
+
+	if err := userConfig.validate(); err != nil {
+		return nil, err
+	}
+
+	totalTimeout := time.Duration(userConfig.Timeout.Total)
+	timer := time.NewTimer(totalTimeout)
+
+	var s Scanner = &ImageScanner{
+		config: *userConfig,
+		timer:  timer,
+		run:    runCommand,
+	}
+	return s, nil
+}
+
+func scan(ctx context.Context, s Scanner, allImages []unversioned.Image) ([]unversioned.Image, []unversioned.Image, error) {
+	results, err := template.ScanImages(ctx, log, s.Timer(), allImages, 1, s.Scan)
+	untrustedImages, failedImages := template.SplitResults(results)
+
+	for _, img := range untrustedImages {
+		log.Info("untrusted image found", "img", img)
+	}
+
+	log.Info("Untrusted", "Images", untrustedImages, "Total count", len(untrustedImages))
+
+	if len(failedImages) > 0 {
+		log.Info("Failed", "Images", failedImages)
+	}
+
+	return untrustedImages, failedImages, err
+}
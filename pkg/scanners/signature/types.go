@@ -0,0 +1,332 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/docker/distribution/reference"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+	"github.com/eraser-dev/eraser/pkg/scanners/template"
+)
+
+const (
+	StatusFailed       = template.StatusFailed
+	StatusNonCompliant = template.StatusNonCompliant
+	StatusOK           = template.StatusOK
+)
+
+const (
+	cosignCommandName               = "/cosign"
+	cosignVerifyArg                 = "verify"
+	cosignKeyFlag                   = "--key"
+	cosignIgnoreTlogFlag            = "--insecure-ignore-tlog=true"
+	cosignAllowInsecureRegistryFlag = "--allow-insecure-registry"
+	cosignAllowHTTPRegistryFlag     = "--allow-http-registry"
+	cosignRepositoryEnv             = "COSIGN_REPOSITORY"
+
+	notationCommandName   = "/notation"
+	notationVerifyArg     = "verify"
+	notationPlainHTTPFlag = "--plain-http"
+	notationConfigEnv     = "XDG_CONFIG_HOME"
+)
+
+// messages printed by the verifiers when an image is unsigned or its
+// signatures are not trusted, as opposed to when verification could not be
+// attempted, for example because the registry was unreachable.
+var untrustedMessages = map[string][]string{
+	verifierCosign: {
+		"no matching signatures",
+		"no signatures found",
+		"invalid signature",
+	},
+	verifierNotation: {
+		"no signature is associated with",
+		"signature verification failed",
+		"no applicable trust policy",
+	},
+}
+
+type (
+	Config struct {
+		Verifiers          []string       `json:"verifiers,omitempty"`
+		Cosign             CosignConfig   `json:"cosign,omitempty"`
+		Notation           NotationConfig `json:"notation,omitempty"`
+		Mirrors            []Mirror       `json:"mirrors,omitempty"`
+		DeleteFailedImages bool           `json:"deleteFailedImages,omitempty"`
+		Timeout            TimeoutConfig  `json:"timeout,omitempty"`
+	}
+
+	// CosignConfig verifies signatures against public keys. An image is
+	// trusted if any of the keys verifies it.
+	CosignConfig struct {
+		Keys                  []string `json:"keys,omitempty"`
+		Repository            string   `json:"repository,omitempty"`
+		IgnoreTlog            bool     `json:"ignoreTlog,omitempty"`
+		AllowInsecureRegistry bool     `json:"allowInsecureRegistry,omitempty"`
+		AllowHTTPRegistry     bool     `json:"allowHTTPRegistry,omitempty"`
+	}
+
+	// NotationConfig verifies signatures against the trust policy and trust
+	// store in ConfigHome/notation.
+	NotationConfig struct {
+		ConfigHome string `json:"configHome,omitempty"`
+		PlainHTTP  bool   `json:"plainHTTP,omitempty"`
+	}
+
+	// Mirror rewrites image references starting with From to start with To
+	// instead, so that signatures are fetched from another registry.
+	Mirror struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+	}
+
+	TimeoutConfig = template.TimeoutConfig
+
+	ScanStatus = template.ScanStatus
+
+	Scanner interface {
+		Scan(context.Context, unversioned.Image) (ScanStatus, error)
+		Timer() *time.Timer
+	}
+
+	// runFunc runs a verifier and returns its stderr.
+	runFunc func(ctx context.Context, name string, args, env []string) (string, error)
+)
+
+func DefaultConfig() *Config {
+	return &Config{
+		Verifiers:          []string{verifierCosign},
+		DeleteFailedImages: false,
+		Timeout: TimeoutConfig{
+			Total:    unversioned.Duration(time.Hour * 23),
+			PerImage: unversioned.Duration(time.Minute * 5),
+		},
+	}
+}
+
+func (c *Config) validate() error {
+	if len(c.Verifiers) == 0 {
+		return fmt.Errorf("no verifiers configured")
+	}
+
+	for _, v := range c.Verifiers {
+		switch v {
+		case verifierCosign:
+			if len(c.Cosign.Keys) == 0 {
+				return fmt.Errorf("cosign verifier requires at least one key in cosign.keys")
+			}
+		case verifierNotation:
+		default:
+			return fmt.Errorf("invalid verifier %q: must be %s or %s", v, verifierCosign, verifierNotation)
+		}
+	}
+
+	return nil
+}
+
+func (c *Config) cosignArgs(key, ref string) []string {
+	args := []string{cosignVerifyArg, cosignKeyFlag, key}
+
+	if c.Cosign.IgnoreTlog {
+		args = append(args, cosignIgnoreTlogFlag)
+	}
+
+	if c.Cosign.AllowInsecureRegistry {
+		args = append(args, cosignAllowInsecureRegistryFlag)
+	}
+
+	if c.Cosign.AllowHTTPRegistry {
+		args = append(args, cosignAllowHTTPRegistryFlag)
+	}
+
+	return append(args, ref)
+}
+
+func (c *Config) cosignEnv() []string {
+	if c.Cosign.Repository == "" {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("%s=%s", cosignRepositoryEnv, c.Cosign.Repository)}
+}
+
+func (c *Config) notationArgs(ref string) []string {
+	args := []string{notationVerifyArg}
+
+	if c.Notation.PlainHTTP {
+		args = append(args, notationPlainHTTPFlag)
+	}
+
+	return append(args, ref)
+}
+
+func (c *Config) notationEnv() []string {
+	if c.Notation.ConfigHome == "" {
+		return nil
+	}
+
+	return []string{fmt.Sprintf("%s=%s", notationConfigEnv, c.Notation.ConfigHome)}
+}
+
+// mirrored applies the first matching mirror to ref.
+func (c *Config) mirrored(ref string) string {
+	for _, m := range c.Mirrors {
+		if strings.HasPrefix(ref, m.From) {
+			return m.To + strings.TrimPrefix(ref, m.From)
+		}
+	}
+
+	return ref
+}
+
+type ImageScanner struct {
+	config Config
+	timer  *time.Timer
+	run    runFunc
+}
+
+// Scan verifies each of the image's digest references with each verifier. The
+// image is trusted if any verification succeeds. Images without a digest
+// reference, such as images built or imported on the node, cannot be
+// verified, and are reported as failed so that they are only removed when
+// deleteFailedImages is set.
+func (s *ImageScanner) Scan(ctx context.Context, img unversioned.Image) (ScanStatus, error) {
+	refs := digestRefs(img)
+	if len(refs) == 0 {
+		log.Info("image has no digest reference and cannot be verified", "imageID", img.ImageID, "names", img.Names)
+		return StatusFailed, nil
+	}
+
+	failed := false
+	for _, ref := range refs {
+		ref = s.config.mirrored(ref)
+		for _, verifier := range s.config.Verifiers {
+			status := s.verify(ctx, verifier, ref)
+			switch status {
+			case StatusOK:
+				log.Info("image signature verified", "imageID", img.ImageID, "reference", ref, "verifier", verifier)
+				return StatusOK, nil
+			case StatusFailed:
+				failed = true
+			}
+		}
+	}
+
+	if failed {
+		return StatusFailed, nil
+	}
+
+	return StatusNonCompliant, nil
+}
+
+// digestRefs returns the repository@digest references of img. The collector
+// reports bare digests, so they are combined with the repository of each of
+// the image's names.
+func digestRefs(img unversioned.Image) []string {
+	refs := []string{}
+	seen := map[string]struct{}{}
+	add := func(ref string) {
+		if _, ok := seen[ref]; !ok {
+			seen[ref] = struct{}{}
+			refs = append(refs, ref)
+		}
+	}
+
+	for _, digest := range img.Digests {
+		// already a full reference
+		if strings.Contains(digest, "@") {
+			add(digest)
+			continue
+		}
+
+		for _, name := range img.Names {
+			named, err := reference.ParseNormalizedNamed(name)
+			if err != nil {
+				log.V(1).Info("skipping unparsable image name", "name", name, "error", err.Error())
+				continue
+			}
+
+			add(fmt.Sprintf("%s@%s", reference.TrimNamed(named).String(), digest))
+		}
+	}
+
+	return refs
+}
+
+func (s *ImageScanner) verify(ctx context.Context, verifier, ref string) ScanStatus {
+	switch verifier {
+	case verifierCosign:
+		// an image is trusted if any key verifies it
+		status := StatusNonCompliant
+		for _, key := range s.config.Cosign.Keys {
+			switch s.runVerifier(ctx, verifier, cosignCommandName, s.config.cosignArgs(key, ref), s.config.cosignEnv()) {
+			case StatusOK:
+				return StatusOK
+			case StatusFailed:
+				status = StatusFailed
+			}
+		}
+		return status
+	case verifierNotation:
+		return s.runVerifier(ctx, verifier, notationCommandName, s.config.notationArgs(ref), s.config.notationEnv())
+	default:
+		return StatusFailed
+	}
+}
+
+func (s *ImageScanner) runVerifier(ctx context.Context, verifier, name string, args, env []string) ScanStatus {
+	cancel := context.CancelFunc(func() {})
+	if s.config.Timeout.PerImage != 0 {
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(s.config.Timeout.PerImage))
+	}
+	defer cancel()
+
+	log.V(1).Info("verifying image ref", "cli_invocation", fmt.Sprintf("%s %s", name, strings.Join(args, " ")), "env", env)
+	stderr, err := s.run(ctx, name, args, env)
+	if err == nil {
+		return StatusOK
+	}
+
+	if ctx.Err() == nil && untrusted(verifier, stderr) {
+		log.Info("image signature not trusted", "verifier", verifier, "reference", args[len(args)-1], "stderr", stderr)
+		return StatusNonCompliant
+	}
+
+	log.Error(err, "error verifying image", "verifier", verifier, "reference", args[len(args)-1], "stderr", stderr)
+	return StatusFailed
+}
+
+func untrusted(verifier, stderr string) bool {
+	lower := strings.ToLower(stderr)
+	for _, msg := range untrustedMessages[verifier] {
+		if strings.Contains(lower, msg) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func runCommand(ctx context.Context, name string, args, env []string) (string, error) {
+	stderr := new(bytes.Buffer)
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stderr = stderr
+	cmd.Env = append(cmd.Env, os.Environ()...)
+	cmd.Env = append(cmd.Env, env...)
+
+	err := cmd.Run()
+	return stderr.String(), err
+}
+
+func (s *ImageScanner) Timer() *time.Timer {
+	return s.timer
+}
+
+var _ Scanner = &ImageScanner{}
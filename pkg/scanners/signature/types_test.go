@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+)
+
+const ref = "registry.local/app@sha256:abc"
+
+func TestCLIArgs(t *testing.T) {
+	tests := []struct {
+		desc     string
+		actual   []string
+		expected []string
+	}{
+		{
+			desc:     "cosign defaults",
+			actual:   (&Config{}).cosignArgs("/keys/cosign.pub", ref),
+			expected: []string{"verify", "--key", "/keys/cosign.pub", ref},
+		},
+		{
+			desc: "cosign offline registry",
+			actual: (&Config{Cosign: CosignConfig{IgnoreTlog: true, AllowInsecureRegistry: true, AllowHTTPRegistry: true}}).
+				cosignArgs("/keys/cosign.pub", ref),
+			expected: []string{"verify", "--key", "/keys/cosign.pub", "--insecure-ignore-tlog=true", "--allow-insecure-registry", "--allow-http-registry", ref},
+		},
+		{
+			desc:     "notation defaults",
+			actual:   (&Config{}).notationArgs(ref),
+			expected: []string{"verify", ref},
+		},
+		{
+			desc:     "notation plain HTTP",
+			actual:   (&Config{Notation: NotationConfig{PlainHTTP: true}}).notationArgs(ref),
+			expected: []string{"verify", "--plain-http", ref},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			if strings.Join(tt.actual, " ") != strings.Join(tt.expected, " ") {
+				t.Errorf("expected `%s`, got `%s`", strings.Join(tt.expected, " "), strings.Join(tt.actual, " "))
+			}
+		})
+	}
+}
+
+func TestMirrored(t *testing.T) {
+	c := &Config{Mirrors: []Mirror{
+		{From: "docker.io/library/", To: "registry.local:5000/library/"},
+		{From: "ghcr.io/", To: "registry.local:5000/ghcr/"},
+	}}
+
+	tests := map[string]string{
+		"docker.io/library/alpine@sha256:abc": "registry.local:5000/library/alpine@sha256:abc",
+		"ghcr.io/org/app@sha256:abc":          "registry.local:5000/ghcr/org/app@sha256:abc",
+		"quay.io/org/app@sha256:abc":          "quay.io/org/app@sha256:abc",
+	}
+
+	for in, expected := range tests {
+		if actual := c.mirrored(in); actual != expected {
+			t.Errorf("expected %s to be mirrored to %s, got %s", in, expected, actual)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		desc    string
+		cfg     Config
+		wantErr bool
+	}{
+		{desc: "no verifiers", cfg: Config{}, wantErr: true},
+		{desc: "cosign without keys", cfg: Config{Verifiers: []string{verifierCosign}}, wantErr: true},
+		{desc: "cosign with keys", cfg: Config{Verifiers: []string{verifierCosign}, Cosign: CosignConfig{Keys: []string{"k"}}}, wantErr: false},
+		{desc: "notation", cfg: Config{Verifiers: []string{verifierNotation}}, wantErr: false},
+		{desc: "unknown verifier", cfg: Config{Verifiers: []string{"gpg"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			if err := tt.cfg.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("expected error: %v, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// fakeVerifier trusts the given key and reference, reports every other
+// reference as unsigned, and fails for unreachable references.
+type fakeVerifier struct {
+	trustedKey  string
+	trustedRef  string
+	unreachable string
+}
+
+func (f *fakeVerifier) run(_ context.Context, name string, args, _ []string) (string, error) {
+	ref := args[len(args)-1]
+	switch {
+	case ref == f.unreachable:
+		return "dial tcp: connection refused", errors.New("exit status 1")
+	case ref != f.trustedRef:
+		return "Error: no signatures found", errors.New("exit status 1")
+	case name == cosignCommandName && args[2] != f.trustedKey:
+		return "Error: no matching signatures: invalid signature when validating ASN.1 encoded signature", errors.New("exit status 1")
+	}
+	return "", nil
+}
+
+func TestScan(t *testing.T) {
+	f := &fakeVerifier{trustedKey: "/keys/b.pub", trustedRef: "registry.local/signed@sha256:a", unreachable: "registry.local/down@sha256:c"}
+	cfg := Config{Verifiers: []string{verifierCosign}, Cosign: CosignConfig{Keys: []string{"/keys/a.pub", "/keys/b.pub"}}}
+
+	tests := []struct {
+		desc     string
+		cfg      Config
+		img      unversioned.Image
+		expected ScanStatus
+	}{
+		{desc: "signed with any key", cfg: cfg, img: unversioned.Image{Digests: []string{"registry.local/signed@sha256:a"}}, expected: StatusOK},
+		{desc: "unsigned", cfg: cfg, img: unversioned.Image{Digests: []string{"registry.local/unsigned@sha256:b"}}, expected: StatusNonCompliant},
+		{desc: "no digest", cfg: cfg, img: unversioned.Image{Names: []string{"local/app:latest"}}, expected: StatusFailed},
+		{desc: "registry unreachable", cfg: cfg, img: unversioned.Image{Digests: []string{"registry.local/down@sha256:c"}}, expected: StatusFailed},
+		{
+			desc:     "any reference may be signed",
+			cfg:      cfg,
+			img:      unversioned.Image{Digests: []string{"registry.local/down@sha256:c", "registry.local/signed@sha256:a"}},
+			expected: StatusOK,
+		},
+		{
+			desc: "collector image with bare digests",
+			cfg:  cfg,
+			img: unversioned.Image{
+				ImageID: "sha256:1",
+				Names:   []string{"registry.local/unsigned:v1", "registry.local/signed:v1"},
+				Digests: []string{"sha256:a"},
+			},
+			expected: StatusOK,
+		},
+		{
+			desc:     "collector image not signed under any name",
+			cfg:      cfg,
+			img:      unversioned.Image{ImageID: "sha256:2", Names: []string{"registry.local/unsigned:v1"}, Digests: []string{"sha256:b"}},
+			expected: StatusNonCompliant,
+		},
+		{
+			desc:     "wrong key",
+			cfg:      Config{Verifiers: []string{verifierCosign}, Cosign: CosignConfig{Keys: []string{"/keys/a.pub"}}},
+			img:      unversioned.Image{Digests: []string{"registry.local/signed@sha256:a"}},
+			expected: StatusNonCompliant,
+		},
+		{
+			desc:     "notation",
+			cfg:      Config{Verifiers: []string{verifierNotation}},
+			img:      unversioned.Image{Digests: []string{"registry.local/signed@sha256:a"}},
+			expected: StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			s := &ImageScanner{config: tt.cfg, run: f.run}
+			status, err := s.Scan(context.Background(), tt.img)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, status)
+			}
+		})
+	}
+}
+
+func TestDigestRefs(t *testing.T) {
+	tests := []struct {
+		desc     string
+		img      unversioned.Image
+		expected []string
+	}{
+		{
+			desc:     "tagged names",
+			img:      unversioned.Image{Names: []string{"alpine:3.18", "ghcr.io/org/app:v1"}, Digests: []string{"sha256:abc"}},
+			expected: []string{"docker.io/library/alpine@sha256:abc", "ghcr.io/org/app@sha256:abc"},
+		},
+		{
+			desc:     "duplicate repositories",
+			img:      unversioned.Image{Names: []string{"ghcr.io/org/app:v1", "ghcr.io/org/app:latest"}, Digests: []string{"sha256:abc"}},
+			expected: []string{"ghcr.io/org/app@sha256:abc"},
+		},
+		{
+			desc:     "full digest references",
+			img:      unversioned.Image{Digests: []string{"ghcr.io/org/app@sha256:abc"}},
+			expected: []string{"ghcr.io/org/app@sha256:abc"},
+		},
+		{
+			desc:     "no names",
+			img:      unversioned.Image{Digests: []string{"sha256:abc"}},
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			if actual := digestRefs(tt.img); strings.Join(actual, " ") != strings.Join(tt.expected, " ") {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestScanMirroredCollectorImage(t *testing.T) {
+	f := &fakeVerifier{trustedRef: "registry.local:5000/library/alpine@sha256:a"}
+	s := &ImageScanner{
+		config: Config{
+			Verifiers: []string{verifierNotation},
+			Mirrors:   []Mirror{{From: "docker.io/library/", To: "registry.local:5000/library/"}},
+		},
+		run: f.run,
+	}
+
+	img := unversioned.Image{ImageID: "sha256:1", Names: []string{"alpine:3.18"}, Digests: []string{"sha256:a"}}
+	status, err := s.Scan(context.Background(), img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != StatusOK {
+		t.Errorf("expected %v, got %v", StatusOK, status)
+	}
+}
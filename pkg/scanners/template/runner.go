@@ -0,0 +1,259 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	_ "net/http/pprof"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+	util "github.com/eraser-dev/eraser/pkg/utils"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const (
+	StatusFailed ScanStatus = iota
+	StatusNonCompliant
+	StatusOK
+)
+
+const podmanSocketPath = "/run/podman/podman.sock"
+
+// ErrTotalTimeout is returned by ScanImages when the total timeout fires
+// before every image has been scanned.
+var ErrTotalTimeout = errors.New("image scan total timeout exceeded")
+
+type (
+	// ScanStatus is the verdict of scanning a single image.
+	ScanStatus int
+
+	// TimeoutConfig bounds the whole scan and the scan of a single image.
+	TimeoutConfig struct {
+		Total    unversioned.Duration `json:"total,omitempty"`
+		PerImage unversioned.Duration `json:"perImage,omitempty"`
+	}
+
+	// ScanResult is the outcome of scanning a single image. Err is
+	// ErrTotalTimeout for images which were never scanned.
+	ScanResult struct {
+		Image  unversioned.Image
+		Status ScanStatus
+		Err    error
+	}
+
+	// ScanFunc scans every image and returns the non-compliant images, and
+	// the failed images to hand to the provider.
+	ScanFunc func(ctx context.Context, images []unversioned.Image) (nonCompliant, failed []unversioned.Image, err error)
+)
+
+// Run receives the images from the collector, scans them with the ScanFunc
+// returned by newScan, sends the results to the remover and waits for it to
+// finish. If newScan fails, no images are sent rather than every image being
+// reported as failed, which would remove them all when failed images are
+// deleted. The returned error is only set if the images cannot be received.
+func Run(ctx context.Context, provider ImageProvider, log logr.Logger, newScan func() (ScanFunc, error)) error {
+	allImages, err := provider.ReceiveImages()
+	if err != nil {
+		return fmt.Errorf("unable to read images from provider: %w", err)
+	}
+
+	nonCompliant, failed := []unversioned.Image{}, []unversioned.Image{}
+	scan, err := newScan()
+	if err != nil {
+		log.Error(err, "error initializing scanner, no images will be removed")
+	} else {
+		nonCompliant, failed, err = scan(ctx, allImages)
+		if err != nil {
+			log.Error(err, "total image scan timed out")
+		}
+	}
+
+	if err := provider.SendImages(nonCompliant, failed); err != nil {
+		log.Error(err, "unable to write images")
+	}
+
+	log.Info("scanning complete, waiting for remover to finish...")
+	if err := provider.Finish(); err != nil {
+		log.Error(err, "unable to complete scanning process")
+	}
+
+	log.Info("remover job completed, shutting down...")
+	return nil
+}
+
+// ScanImages distributes images over the given number of workers. Once the
+// timer fires, no further images are dispatched, in-flight scans are
+// cancelled, and every image which was not dispatched is reported with
+// ErrTotalTimeout. Results are in completion order.
+func ScanImages(ctx context.Context, log logr.Logger, timer *time.Timer, images []unversioned.Image, workers int, scan func(context.Context, unversioned.Image) (ScanStatus, error)) ([]ScanResult, error) {
+	results := make([]ScanResult, 0, len(images))
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan unversioned.Image)
+	outcomes := make(chan ScanResult)
+	undispatched := make(chan []unversioned.Image, 1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for img := range jobs {
+				status, err := scan(ctx, img)
+				outcomes <- ScanResult{Image: img, Status: status, Err: err}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for idx, img := range images {
+			select {
+			case <-timer.C:
+				cancel()
+				undispatched <- images[idx:]
+				return
+			case jobs <- img:
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(outcomes)
+	}()
+
+	for o := range outcomes {
+		if o.Err != nil {
+			log.Error(o.Err, "scan failed", "img", o.Image)
+		}
+		results = append(results, o)
+	}
+
+	select {
+	case remaining := <-undispatched:
+		for _, img := range remaining {
+			results = append(results, ScanResult{Image: img, Status: StatusFailed, Err: ErrTotalTimeout})
+		}
+		return results, ErrTotalTimeout
+	default:
+	}
+
+	return results, nil
+}
+
+// SplitResults returns the non-compliant images, and the images which could
+// not be scanned.
+func SplitResults(results []ScanResult) (nonCompliant, failed []unversioned.Image) {
+	nonCompliant = make([]unversioned.Image, 0, len(results))
+	failed = make([]unversioned.Image, 0, len(results))
+
+	for _, r := range results {
+		switch {
+		case r.Err != nil, r.Status == StatusFailed:
+			failed = append(failed, r.Image)
+		case r.Status == StatusNonCompliant:
+			nonCompliant = append(nonCompliant, r.Image)
+		}
+	}
+
+	return nonCompliant, failed
+}
+
+// LoadConfig reads the scanner config from the eraser config file into
+// scannerConfig, which should hold the scanner's defaults. An empty filename
+// leaves scannerConfig unchanged.
+func LoadConfig(filename string, scannerConfig interface{}) error {
+	if filename == "" {
+		return nil
+	}
+
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("unable to read eraser config: %w", err)
+	}
+
+	var eraserConfig unversioned.EraserConfig
+	if err := yaml.Unmarshal(b, &eraserConfig); err != nil {
+		return fmt.Errorf("unable to unmarshal eraser config: %w", err)
+	}
+
+	scanCfgYaml := eraserConfig.Components.Scanner.Config
+	if scanCfgYaml == nil {
+		return nil
+	}
+
+	if err := yaml.Unmarshal([]byte(*scanCfgYaml), scannerConfig); err != nil {
+		return fmt.Errorf("unable to unmarshal scanner config: %w", err)
+	}
+
+	return nil
+}
+
+// RunProfileServer serves pprof on localhost until the server fails.
+func RunProfileServer(log logr.Logger, port int) {
+	server := &http.Server{
+		Addr:              fmt.Sprintf("localhost:%d", port),
+		ReadHeaderTimeout: 3 * time.Second,
+	}
+	err := server.ListenAndServe()
+	log.Error(err, "pprof server failed")
+}
+
+// RuntimeSocketEnv returns the environment variables which point docker,
+// containerd and podman clients at the runtime's socket. For CRI-O, the
+// socket is linked to where podman clients look for it.
+func RuntimeSocketEnv(log logr.Logger, runtime unversioned.RuntimeSpec) []string {
+	switch runtime.Name {
+	case unversioned.RuntimeDockerShim:
+		return []string{"DOCKER_HOST=unix://" + util.CRIPath}
+	case unversioned.RuntimeCrio:
+		linkPodmanSocket(log)
+		return []string{
+			"XDG_RUNTIME_DIR=/run",
+			"CONTAINER_HOST=unix://" + podmanSocketPath,
+		}
+	default:
+		return []string{"CONTAINERD_ADDRESS=" + util.CRIPath}
+	}
+}
+
+func linkPodmanSocket(log logr.Logger) {
+	dirMode, socketMode := os.FileMode(0o755), os.FileMode(0o660)
+
+	if info, err := os.Stat("/run/cri"); err != nil {
+		log.Error(err, "unable to get permissions for cri directory")
+	} else {
+		dirMode = info.Mode().Perm()
+	}
+
+	if info, err := os.Stat(util.CRIPath); err != nil {
+		log.Error(err, "unable to get permissions for cri socket")
+	} else {
+		socketMode = info.Mode().Perm()
+	}
+
+	if err := os.Mkdir("/run/podman", dirMode); err != nil && !os.IsExist(err) {
+		log.Error(err, "unable to create /run/podman dir")
+	}
+
+	if err := os.Symlink(util.CRIPath, podmanSocketPath); err != nil && !os.IsExist(err) {
+		log.Error(err, "unable to create symlink between CRI path and "+podmanSocketPath)
+	}
+
+	if err := os.Chmod(podmanSocketPath, socketMode); err != nil {
+		log.Error(err, "unable to change "+podmanSocketPath+" permissions")
+	}
+}
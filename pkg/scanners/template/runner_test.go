@@ -0,0 +1,220 @@
+package template
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+	"github.com/eraser-dev/eraser/pkg/metrics"
+	util "github.com/eraser-dev/eraser/pkg/utils"
+	"github.com/go-logr/logr"
+)
+
+type fakeProvider struct {
+	images       []unversioned.Image
+	nonCompliant []unversioned.Image
+	failed       []unversioned.Image
+	sent         bool
+	finished     bool
+}
+
+func (p *fakeProvider) ReceiveImages() ([]unversioned.Image, error) {
+	return p.images, nil
+}
+
+func (p *fakeProvider) SendImages(nonCompliantImages, failedImages []unversioned.Image) error {
+	p.nonCompliant, p.failed, p.sent = nonCompliantImages, failedImages, true
+	return nil
+}
+
+func (p *fakeProvider) RecordStats(*metrics.ScanStats) {}
+
+func (p *fakeProvider) Finish() error {
+	p.finished = true
+	return nil
+}
+
+func ids(images []unversioned.Image) []string {
+	ret := make([]string, 0, len(images))
+	for _, img := range images {
+		ret = append(ret, img.ImageID)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func TestRun(t *testing.T) {
+	images := []unversioned.Image{{ImageID: "a"}, {ImageID: "b"}}
+
+	tests := []struct {
+		desc         string
+		newScan      func() (ScanFunc, error)
+		nonCompliant int
+		failed       int
+	}{
+		{
+			desc: "scan",
+			newScan: func() (ScanFunc, error) {
+				return func(_ context.Context, images []unversioned.Image) ([]unversioned.Image, []unversioned.Image, error) {
+					return images[:1], images[1:], nil
+				}, nil
+			},
+			nonCompliant: 1,
+			failed:       1,
+		},
+		{
+			desc: "scanner cannot be initialized",
+			newScan: func() (ScanFunc, error) {
+				return nil, errors.New("invalid config")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			p := &fakeProvider{images: images}
+			if err := Run(context.Background(), p, logr.Discard(), tt.newScan); err != nil {
+				t.Fatal(err)
+			}
+
+			if !p.sent || !p.finished {
+				t.Errorf("expected images to be sent and the provider to finish")
+			}
+			if len(p.nonCompliant) != tt.nonCompliant || len(p.failed) != tt.failed {
+				t.Errorf("expected %d non-compliant and %d failed images, got %v and %v", tt.nonCompliant, tt.failed, ids(p.nonCompliant), ids(p.failed))
+			}
+		})
+	}
+}
+
+func TestScanImages(t *testing.T) {
+	images := []unversioned.Image{{ImageID: "a"}, {ImageID: "b"}, {ImageID: "c"}, {ImageID: "d"}}
+	statuses := map[string]ScanStatus{"a": StatusNonCompliant, "b": StatusOK, "c": StatusFailed}
+
+	scan := func(_ context.Context, img unversioned.Image) (ScanStatus, error) {
+		if img.ImageID == "d" {
+			return StatusFailed, errors.New("scan failed")
+		}
+		return statuses[img.ImageID], nil
+	}
+
+	results, err := ScanImages(context.Background(), logr.Discard(), time.NewTimer(time.Hour), images, 2, scan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nonCompliant, failed := SplitResults(results)
+	if got := ids(nonCompliant); len(got) != 1 || got[0] != "a" {
+		t.Errorf("unexpected non-compliant images: %v", got)
+	}
+	if got := ids(failed); len(got) != 2 || got[0] != "c" || got[1] != "d" {
+		t.Errorf("unexpected failed images: %v", got)
+	}
+}
+
+func TestScanImagesTotalTimeout(t *testing.T) {
+	images := []unversioned.Image{{ImageID: "a"}, {ImageID: "b"}, {ImageID: "c"}}
+
+	scan := func(ctx context.Context, _ unversioned.Image) (ScanStatus, error) {
+		<-ctx.Done()
+		return StatusFailed, ctx.Err()
+	}
+
+	results, err := ScanImages(context.Background(), logr.Discard(), time.NewTimer(10*time.Millisecond), images, 1, scan)
+	if !errors.Is(err, ErrTotalTimeout) {
+		t.Errorf("expected %v, got %v", ErrTotalTimeout, err)
+	}
+
+	undispatched := 0
+	for _, r := range results {
+		if errors.Is(r.Err, ErrTotalTimeout) {
+			undispatched++
+		}
+	}
+	if len(results) != len(images) || undispatched != len(images)-1 {
+		t.Errorf("expected %d results, %d of them undispatched, got %d and %d", len(images), len(images)-1, len(results), undispatched)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	type scannerConfig struct {
+		DeleteFailedImages bool   `json:"deleteFailedImages"`
+		CacheDir           string `json:"cacheDir"`
+	}
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		desc     string
+		filename string
+		expected scannerConfig
+		wantErr  bool
+	}{
+		{
+			desc:     "no config file",
+			expected: scannerConfig{CacheDir: "/default"},
+		},
+		{
+			desc:     "scanner config",
+			filename: write("scanner.yaml", "components:\n  scanner:\n    config: |\n      deleteFailedImages: true\n"),
+			expected: scannerConfig{DeleteFailedImages: true, CacheDir: "/default"},
+		},
+		{
+			desc:     "no scanner config",
+			filename: write("empty.yaml", "components: {}\n"),
+			expected: scannerConfig{CacheDir: "/default"},
+		},
+		{
+			desc:     "missing file",
+			filename: filepath.Join(dir, "missing.yaml"),
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			cfg := scannerConfig{CacheDir: "/default"}
+			err := LoadConfig(tt.filename, &cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error: %v, got: %v", tt.wantErr, err)
+			}
+			if !tt.wantErr && cfg != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, cfg)
+			}
+		})
+	}
+}
+
+func TestRuntimeSocketEnv(t *testing.T) {
+	tests := []struct {
+		runtime  unversioned.Runtime
+		expected string
+	}{
+		{runtime: unversioned.RuntimeContainerd, expected: "CONTAINERD_ADDRESS=" + util.CRIPath},
+		{runtime: unversioned.RuntimeDockerShim, expected: "DOCKER_HOST=unix://" + util.CRIPath},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(string(tt.runtime), func(t *testing.T) {
+			env := RuntimeSocketEnv(logr.Discard(), unversioned.RuntimeSpec{Name: tt.runtime})
+			if len(env) != 1 || env[0] != tt.expected {
+				t.Errorf("expected [%s], got %v", tt.expected, env)
+			}
+		})
+	}
+}
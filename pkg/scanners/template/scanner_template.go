@@ -25,6 +25,10 @@ type ImageProvider interface {
 	// sends non-compliant images found to remover container for removal.
 	SendImages(nonCompliantImages, failedImages []unversioned.Image) error
 
+	// optionally records statistics of the scan, such as scan durations and
+	// vulnerable images per severity. must be called before SendImages.
+	RecordStats(stats *metrics.ScanStats)
+
 	// completes scanner communication process - required after custom scanning finishes.
 	Finish() error
 }
@@ -35,6 +39,7 @@ type config struct {
 	deleteScanFailedImages bool
 	deleteEOLImages        bool
 	reportMetrics          bool
+	stats                  *metrics.ScanStats
 }
 
 type ConfigFunc func(*config)
@@ -80,6 +85,8 @@ func (cfg *config) ReceiveImages() ([]unversioned.Image, error) {
 }
 
 func (cfg *config) SendImages(nonCompliantImages, failedImages []unversioned.Image) error {
+	// failed images which are removed are not counted as non-compliant
+	nonCompliant := len(nonCompliantImages)
 	if cfg.deleteScanFailedImages {
 		nonCompliantImages = append(nonCompliantImages, failedImages...)
 	}
@@ -89,23 +96,54 @@ func (cfg *config) SendImages(nonCompliantImages, failedImages []unversioned.Ima
 		return err
 	}
 
+	if err := metrics.WriteReport(&metrics.Report{VulnerableImages: int64(nonCompliant), Scan: cfg.stats}); err != nil {
+		cfg.log.Error(err, "unable to write metrics report", "path", metrics.TerminationMessagePath)
+	}
+
 	if cfg.reportMetrics {
 		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 		defer cancel()
 
-		exporter, reader, provider := metrics.ConfigureMetrics(ctx, cfg.log, os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"))
+		exporterCfg, err := metrics.ExporterConfigFromEnv()
+		if err != nil {
+			cfg.log.Error(err, "error configuring exporter")
+			return err
+		}
+
+		exporter, reader, provider, err := metrics.ConfigureMetrics(ctx, exporterCfg)
+		if err != nil {
+			cfg.log.Error(err, "error configuring metrics")
+			return err
+		}
 		global.SetMeterProvider(provider)
 
-		if err := metrics.RecordMetricsScanner(ctx, global.MeterProvider(), len(nonCompliantImages)); err != nil {
+		if err := metrics.RecordMetricsScanner(ctx, global.MeterProvider(), nonCompliant); err != nil {
 			cfg.log.Error(err, "error recording metrics")
 			return err
 		}
 
+		if cfg.stats != nil {
+			recorder, err := metrics.NewScanRecorder(global.MeterProvider())
+			if err != nil {
+				cfg.log.Error(err, "error recording metrics")
+				return err
+			}
+
+			if err := recorder.Record(ctx, os.Getenv("NODE_NAME"), cfg.stats); err != nil {
+				cfg.log.Error(err, "error recording metrics")
+				return err
+			}
+		}
+
 		metrics.ExportMetrics(cfg.log, exporter, reader)
 	}
 	return nil
 }
 
+func (cfg *config) RecordStats(stats *metrics.ScanStats) {
+	cfg.stats = stats
+}
+
 func (cfg *config) Finish() error {
 	file, err := os.OpenFile(util.EraseCompleteScanPath, os.O_RDONLY, 0)
 	if err != nil {
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	cacheFileSuffix = ".json"
+	// cacheReportSuffix ends in cacheFileSuffix, so that cached reports are
+	// pruned along with their results.
+	cacheReportSuffix = ".report" + cacheFileSuffix
+
+	// cacheFormat is part of every key. Bump it whenever scanResult changes
+	// so that entries written by an older scanner are not misread.
+	cacheFormat = 4
+)
+
+type (
+	// scanResult is the part of a trivy report which is needed to reach a
+	// verdict. It is what gets cached, so that the verdict is recomputed with
+	// the current thresholds and policy on every run.
+	scanResult struct {
+		Findings []finding `json:"findings"`
+		EOL      bool      `json:"eol"`
+		Created  time.Time `json:"created,omitempty"`
+
+		// DB is the vulnerability DB the image was scanned against.
+		DB dbMetadata `json:"db"`
+	}
+
+	// finding is a vulnerability, secret, misconfiguration or license
+	// reported by trivy.
+	finding struct {
+		Kind      string    `json:"kind"`
+		ID        string    `json:"id"`
+		Package   string    `json:"package,omitempty"`
+		Severity  string    `json:"severity"`
+		CVSS      float64   `json:"cvss,omitempty"`
+		Fixable   bool      `json:"fixable,omitempty"`
+		Published time.Time `json:"published,omitempty"`
+	}
+
+	resultCache struct {
+		dir    string
+		prefix string
+	}
+)
+
+// newResultCache returns a cache whose keys are scoped to the given vulnerability
+// DB and scanner configuration. A change to either misses every existing entry.
+func newResultCache(cfg *Config, db *dbMetadata) (*resultCache, error) {
+	if err := os.MkdirAll(cfg.Cache.Dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	hash, err := cfg.hash()
+	if err != nil {
+		return nil, err
+	}
+
+	return &resultCache{
+		dir:    cfg.Cache.Dir,
+		prefix: fmt.Sprintf("%d|%d|%s|%s", cacheFormat, db.Version, db.UpdatedAt.UTC().Format(time.RFC3339), hash),
+	}, nil
+}
+
+func (c *resultCache) key(imageID string) string {
+	sum := sha256.Sum256([]byte(c.prefix + "|" + imageID))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *resultCache) path(imageID string) string {
+	return filepath.Join(c.dir, c.key(imageID)+cacheFileSuffix)
+}
+
+func (c *resultCache) reportPath(imageID string) string {
+	return filepath.Join(c.dir, c.key(imageID)+cacheReportSuffix)
+}
+
+func (c *resultCache) get(imageID string) (*scanResult, bool) {
+	p := c.path(imageID)
+
+	b, err := os.ReadFile(p)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Error(err, "unable to read cached result", "imageID", imageID)
+		}
+		return nil, false
+	}
+
+	var res scanResult
+	if err := json.Unmarshal(b, &res); err != nil {
+		log.Error(err, "unable to unmarshal cached result", "imageID", imageID)
+		return nil, false
+	}
+
+	// keep entries which are still in use from being pruned
+	now := time.Now()
+	if err := os.Chtimes(p, now, now); err != nil {
+		log.V(1).Info("unable to update cache entry time", "imageID", imageID, "error", err.Error())
+	}
+
+	return &res, true
+}
+
+// put caches the result of an image. The raw trivy report is cached with it
+// if it is not nil, so that it can be persisted again on later runs.
+func (c *resultCache) put(imageID string, res *scanResult, raw []byte) {
+	b, err := json.Marshal(res)
+	if err != nil {
+		log.Error(err, "unable to marshal result for cache", "imageID", imageID)
+		return
+	}
+
+	// the report is written first, so that a result is never cached without
+	// the report it came with
+	if raw != nil {
+		if err := c.write(c.reportPath(imageID), raw); err != nil {
+			log.Error(err, "unable to write cached report", "imageID", imageID)
+		}
+	}
+
+	if err := c.write(c.path(imageID), b); err != nil {
+		log.Error(err, "unable to write cache entry", "imageID", imageID)
+	}
+}
+
+// report returns the raw trivy report cached with the result of an image,
+// or nil if there is none, such as when reports were disabled when the image
+// was scanned.
+func (c *resultCache) report(imageID string) []byte {
+	p := c.reportPath(imageID)
+
+	b, err := os.ReadFile(p)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Error(err, "unable to read cached report", "imageID", imageID)
+		}
+		return nil
+	}
+
+	now := time.Now()
+	if err := os.Chtimes(p, now, now); err != nil {
+		log.V(1).Info("unable to update cached report time", "imageID", imageID, "error", err.Error())
+	}
+
+	return b
+}
+
+// write writes to a temporary file first so a concurrent reader never sees a
+// partial entry.
+func (c *resultCache) write(name string, b []byte) error {
+	tmp, err := os.CreateTemp(c.dir, "tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), name)
+}
+
+// prune removes entries which have not been used within maxAge. Entries for
+// an older DB or configuration are never hit again, so this is what bounds
+// the size of the cache.
+func (c *resultCache) prune(maxAge time.Duration) {
+	if maxAge <= 0 {
+		return
+	}
+
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		log.Error(err, "unable to list cache entries")
+		return
+	}
+
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), cacheFileSuffix) {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+
+		if time.Since(info.ModTime()) > maxAge {
+			if err := os.Remove(filepath.Join(c.dir, e.Name())); err != nil {
+				log.Error(err, "unable to prune cache entry", "entry", e.Name())
+			}
+		}
+	}
+}
+
+// hash covers every option which changes the contents of a trivy report.
+// Thresholds are applied to cached results, so they are left out.
+func (c *Config) hash() (string, error) {
+	vulns := c.Vulnerabilities
+	vulns.Thresholds = ThresholdConfig{}
+
+	b, err := json.Marshal(struct {
+		DBRepo          string
+		Vulnerabilities VulnConfig
+	}{c.DBRepo, vulns})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+)
+
+func TestResultCacheKey(t *testing.T) {
+	updatedAt := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	db := &dbMetadata{Version: 2, UpdatedAt: updatedAt}
+
+	base := DefaultConfig()
+	base.Cache.Dir = t.TempDir()
+
+	otherSeverities := DefaultConfig()
+	otherSeverities.Cache.Dir = base.Cache.Dir
+	otherSeverities.Vulnerabilities.Severities = []string{severityCritical}
+
+	otherTimeout := DefaultConfig()
+	otherTimeout.Cache.Dir = base.Cache.Dir
+	otherTimeout.Timeout.PerImage = 0
+
+	tests := []struct {
+		desc  string
+		cfg   *Config
+		db    *dbMetadata
+		image string
+		same  bool
+	}{
+		{desc: "identical inputs", cfg: base, db: db, image: "sha256:a", same: true},
+		{desc: "different image", cfg: base, db: db, image: "sha256:b", same: false},
+		{desc: "newer DB", cfg: base, db: &dbMetadata{Version: 2, UpdatedAt: updatedAt.Add(time.Hour)}, image: "sha256:a", same: false},
+		{desc: "different severities", cfg: otherSeverities, db: db, image: "sha256:a", same: false},
+		{desc: "timeout does not affect reports", cfg: otherTimeout, db: db, image: "sha256:a", same: true},
+	}
+
+	reference, err := newResultCache(base, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			c, err := newResultCache(tt.cfg, tt.db)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if same := c.key(tt.image) == reference.key("sha256:a"); same != tt.same {
+				t.Errorf("expected same key: %v, got: %v", tt.same, same)
+			}
+		})
+	}
+}
+
+func TestResultCacheGetPut(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Cache.Dir = t.TempDir()
+
+	c, err := newResultCache(cfg, &dbMetadata{Version: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := c.get("sha256:a"); ok {
+		t.Fatal("expected cache miss")
+	}
+
+	c.put("sha256:a", &scanResult{Findings: []finding{{ID: "CVE-1", Severity: severityHigh, CVSS: 7.5}}, EOL: true}, nil)
+
+	res, ok := c.get("sha256:a")
+	if !ok {
+		t.Fatal("expected cache hit")
+	}
+	if len(res.Findings) != 1 || res.Findings[0].CVSS != 7.5 || !res.EOL {
+		t.Errorf("unexpected cached result: %#v", res)
+	}
+
+	if raw := c.report("sha256:a"); raw != nil {
+		t.Errorf("expected no cached report, got: %s", raw)
+	}
+
+	c.put("sha256:b", &scanResult{}, []byte(`{"ArtifactName":"b"}`))
+	if raw := c.report("sha256:b"); string(raw) != `{"ArtifactName":"b"}` {
+		t.Errorf("unexpected cached report: %s", raw)
+	}
+}
+
+func TestResultCachePrune(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Cache.Dir = t.TempDir()
+
+	c, err := newResultCache(cfg, &dbMetadata{Version: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.put("sha256:old", &scanResult{}, []byte("{}"))
+	c.put("sha256:new", &scanResult{}, nil)
+
+	old := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(c.path("sha256:old"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(c.reportPath("sha256:old"), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	unrelated := filepath.Join(cfg.Cache.Dir, "unrelated")
+	if err := os.WriteFile(unrelated, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(unrelated, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	c.prune(24 * time.Hour)
+
+	if _, err := os.Stat(c.path("sha256:old")); !os.IsNotExist(err) {
+		t.Errorf("expected stale entry to be pruned, got: %v", err)
+	}
+	if _, err := os.Stat(c.reportPath("sha256:old")); !os.IsNotExist(err) {
+		t.Errorf("expected stale report to be pruned, got: %v", err)
+	}
+	if _, err := os.Stat(c.path("sha256:new")); err != nil {
+		t.Errorf("expected recent entry to be kept, got: %v", err)
+	}
+	if _, err := os.Stat(unrelated); err != nil {
+		t.Errorf("expected unrelated file to be kept, got: %v", err)
+	}
+}
+
+func TestVerdict(t *testing.T) {
+	tests := []struct {
+		desc     string
+		eolCfg   bool
+		res      scanResult
+		expected ScanStatus
+	}{
+		{desc: "no vulnerabilities", res: scanResult{}, expected: StatusOK},
+		{desc: "vulnerable", res: scanResult{Findings: []finding{{Kind: findingVulnerability, ID: "CVE-1", Severity: severityLow}}}, expected: StatusNonCompliant},
+		{desc: "EOL ignored", res: scanResult{EOL: true}, expected: StatusOK},
+		{desc: "EOL deleted", eolCfg: true, res: scanResult{EOL: true}, expected: StatusNonCompliant},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			s := &ImageScanner{config: Config{DeleteEOLImages: tt.eolCfg}}
+			if actual := s.verdict(unversioned.Image{ImageID: "sha256:a"}, &tt.res); actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
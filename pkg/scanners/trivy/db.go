@@ -0,0 +1,133 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+const (
+	dbDir          = "db"
+	dbFile         = "trivy.db"
+	dbMetadataFile = "metadata.json"
+)
+
+// dbMetadata is the subset of trivy's db/metadata.json which identifies the
+// DB version.
+type dbMetadata struct {
+	Version   int       `json:"Version"`
+	UpdatedAt time.Time `json:"UpdatedAt"`
+}
+
+var errStaleDB = errors.New("vulnerability DB is older than db.maxAge")
+
+// prepareDB makes the vulnerability DB available in CacheDir before the first
+// scan, either by installing the configured bundle or by downloading it, and
+// returns its metadata. Scans then run without updating the DB, so that every
+// image is scanned against the same version.
+func prepareDB(cfg *Config, now time.Time) (*dbMetadata, error) {
+	switch {
+	case cfg.DB.BundleDir != "":
+		if err := installDBBundle(cfg.DB.BundleDir, cfg.CacheDir); err != nil {
+			return nil, fmt.Errorf("unable to install vulnerability DB bundle from %s: %w", cfg.DB.BundleDir, err)
+		}
+	case !cfg.DB.SkipUpdate:
+		stderr := new(bytes.Buffer)
+		cmd := exec.Command(trivyCommandName, cfg.downloadDBArgs()...)
+		cmd.Stderr = stderr
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("unable to download vulnerability DB from %s: %w: %s", cfg.DBRepo, err, stderr.String())
+		}
+	}
+
+	db, err := readDBMetadata(cfg.CacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read vulnerability DB metadata: %w", err)
+	}
+
+	if err := checkDBAge(db, &cfg.DB, now); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// checkDBAge compares the time the DB was built against DB.MaxAge. A stale
+// DB is only an error if DB.RefuseStale is set; otherwise it is logged.
+func checkDBAge(db *dbMetadata, cfg *DBConfig, now time.Time) error {
+	if cfg.MaxAge <= 0 {
+		return nil
+	}
+
+	age := now.Sub(db.UpdatedAt)
+	if age <= time.Duration(cfg.MaxAge) {
+		return nil
+	}
+
+	if cfg.RefuseStale {
+		return fmt.Errorf("%w: built at %s, %s ago", errStaleDB, db.UpdatedAt.UTC().Format(time.RFC3339), age.Round(time.Minute))
+	}
+
+	log.Info("WARNING: vulnerability DB is older than db.maxAge, results may miss recent vulnerabilities",
+		"dbVersion", db.Version, "dbUpdatedAt", db.UpdatedAt, "maxAge", time.Duration(cfg.MaxAge).String())
+	return nil
+}
+
+// installDBBundle copies trivy.db and metadata.json from bundleDir into the
+// layout trivy expects under cacheDir. The files are copied rather than
+// linked because trivy opens the DB read-write, and bundles are usually
+// mounted read-only.
+func installDBBundle(bundleDir, cacheDir string) error {
+	dst := filepath.Join(cacheDir, dbDir)
+	if err := os.MkdirAll(dst, 0o755); err != nil {
+		return err
+	}
+
+	for _, name := range []string{dbFile, dbMetadataFile} {
+		if err := copyFile(filepath.Join(bundleDir, name), filepath.Join(dst, name)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+func readDBMetadata(cacheDir string) (*dbMetadata, error) {
+	b, err := os.ReadFile(filepath.Join(cacheDir, dbDir, dbMetadataFile))
+	if err != nil {
+		return nil, err
+	}
+
+	var m dbMetadata
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+
+	return &m, nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+)
+
+func writeDBBundle(t *testing.T, dir string, updatedAt time.Time) {
+	t.Helper()
+
+	metadata := `{"Version": 2, "UpdatedAt": "` + updatedAt.Format(time.RFC3339) + `"}`
+	if err := os.WriteFile(filepath.Join(dir, dbMetadataFile), []byte(metadata), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, dbFile), []byte("db"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckDBAge(t *testing.T) {
+	now := time.Date(2023, time.June, 15, 0, 0, 0, 0, time.UTC)
+	db := &dbMetadata{Version: 2, UpdatedAt: now.Add(-72 * time.Hour)}
+
+	tests := []struct {
+		desc    string
+		cfg     DBConfig
+		wantErr bool
+	}{
+		{desc: "no max age", cfg: DBConfig{RefuseStale: true}, wantErr: false},
+		{desc: "fresh", cfg: DBConfig{MaxAge: unversioned.Duration(96 * time.Hour), RefuseStale: true}, wantErr: false},
+		{desc: "stale, warn only", cfg: DBConfig{MaxAge: unversioned.Duration(24 * time.Hour)}, wantErr: false},
+		{desc: "stale, refused", cfg: DBConfig{MaxAge: unversioned.Duration(24 * time.Hour), RefuseStale: true}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			err := checkDBAge(db, &tt.cfg, now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error: %v, got: %v", tt.wantErr, err)
+			}
+			if err != nil && !errors.Is(err, errStaleDB) {
+				t.Errorf("expected a stale DB error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestPrepareDBFromBundle(t *testing.T) {
+	now := time.Date(2023, time.June, 15, 0, 0, 0, 0, time.UTC)
+	bundle := t.TempDir()
+	writeDBBundle(t, bundle, now.Add(-time.Hour))
+
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.DB.BundleDir = bundle
+
+	db, err := prepareDB(cfg, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db.Version != 2 || !db.UpdatedAt.Equal(now.Add(-time.Hour)) {
+		t.Errorf("unexpected DB metadata: %#v", db)
+	}
+	if _, err := os.Stat(filepath.Join(cfg.CacheDir, dbDir, dbFile)); err != nil {
+		t.Errorf("expected DB to be installed in the cache dir: %v", err)
+	}
+}
+
+func TestPrepareDBSkipUpdate(t *testing.T) {
+	now := time.Date(2023, time.June, 15, 0, 0, 0, 0, time.UTC)
+
+	cfg := DefaultConfig()
+	cfg.CacheDir = t.TempDir()
+	cfg.DB.SkipUpdate = true
+
+	if _, err := prepareDB(cfg, now); err == nil {
+		t.Error("expected an error when no DB is present")
+	}
+
+	if err := os.MkdirAll(filepath.Join(cfg.CacheDir, dbDir), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeDBBundle(t, filepath.Join(cfg.CacheDir, dbDir), now.Add(-30*24*time.Hour))
+
+	cfg.DB.MaxAge = unversioned.Duration(7 * 24 * time.Hour)
+	cfg.DB.RefuseStale = true
+	if _, err := prepareDB(cfg, now); !errors.Is(err, errStaleDB) {
+		t.Errorf("expected a stale DB error, got: %v", err)
+	}
+}
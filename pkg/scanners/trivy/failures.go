@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+)
+
+const (
+	failureAuth     = "auth"
+	failureNotFound = "notFound"
+	failureTimeout  = "timeout"
+	failureDB       = "db"
+	failureCrash    = "crash"
+	failureUnknown  = "unknown"
+
+	failureActionDelete = "delete"
+	failureActionKeep   = "keep"
+)
+
+// failureMessages maps substrings of trivy's stderr to a failure class. They
+// are checked in order, so that DB errors which mention a missing file are
+// not mistaken for a missing image. Only the registry's own errors count as
+// not found, since trivy reports many unrelated failures as "not found".
+var failureMessages = []struct {
+	class    string
+	messages []string
+}{
+	{failureCrash, []string{"panic:", "fatal error:", "signal: killed"}},
+	{failureDB, []string{"vulnerability db", "failed to open db", "db error", "trivy.db", "metadata.json"}},
+	{failureAuth, []string{"unauthorized", "authentication required", "access denied", "requested access to the resource is denied", "status code 401", "status code 403"}},
+	{failureNotFound, []string{"manifest unknown", "name unknown"}},
+}
+
+type (
+	// FailurePolicy decides what happens to an image whose scan failed. The
+	// scan is attempted Retries more times before the failure is final.
+	// Action is delete or keep; if empty, deleteFailedImages decides.
+	FailurePolicy struct {
+		Retries int    `json:"retries,omitempty"`
+		Action  string `json:"action,omitempty"`
+	}
+
+	FailureConfig struct {
+		Auth       FailurePolicy        `json:"auth,omitempty"`
+		NotFound   FailurePolicy        `json:"notFound,omitempty"`
+		Timeout    FailurePolicy        `json:"timeout,omitempty"`
+		DB         FailurePolicy        `json:"db,omitempty"`
+		Crash      FailurePolicy        `json:"crash,omitempty"`
+		Unknown    FailurePolicy        `json:"unknown,omitempty"`
+		RetryDelay unversioned.Duration `json:"retryDelay,omitempty"`
+	}
+
+	// scanError is a failed scan of an image and the class of the failure.
+	scanError struct {
+		class string
+		err   error
+	}
+
+	scanFailure struct {
+		img   unversioned.Image
+		class string
+	}
+)
+
+func (e *scanError) Error() string {
+	return fmt.Sprintf("%s: %v", e.class, e.err)
+}
+
+func (e *scanError) Unwrap() error {
+	return e.err
+}
+
+func failureClass(err error) string {
+	var se *scanError
+	if errors.As(err, &se) {
+		return se.class
+	}
+
+	return failureUnknown
+}
+
+// classify returns the class of a failed trivy invocation from its context,
+// error and stderr.
+func classify(ctx context.Context, err error, stderr string) string {
+	// the per-image or total timeout elapsed
+	if ctx.Err() != nil {
+		return failureTimeout
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ProcessState != nil && !exitErr.Exited() {
+		// killed by a signal, for example by the OOM killer
+		return failureCrash
+	}
+
+	lower := strings.ToLower(stderr)
+	if strings.Contains(lower, "context deadline exceeded") {
+		return failureTimeout
+	}
+
+	for _, fm := range failureMessages {
+		for _, msg := range fm.messages {
+			if strings.Contains(lower, msg) {
+				return fm.class
+			}
+		}
+	}
+
+	return failureUnknown
+}
+
+func (c *FailureConfig) policy(class string) *FailurePolicy {
+	switch class {
+	case failureAuth:
+		return &c.Auth
+	case failureNotFound:
+		return &c.NotFound
+	case failureTimeout:
+		return &c.Timeout
+	case failureDB:
+		return &c.DB
+	case failureCrash:
+		return &c.Crash
+	default:
+		return &c.Unknown
+	}
+}
+
+func (c *FailureConfig) deletes(class string, deleteFailedImages bool) bool {
+	switch c.policy(class).Action {
+	case failureActionDelete:
+		return true
+	case failureActionKeep:
+		return false
+	default:
+		return deleteFailedImages
+	}
+}
+
+// partition splits failed images into those to remove and those to keep.
+func (c *FailureConfig) partition(failures []scanFailure, deleteFailedImages bool) ([]unversioned.Image, []unversioned.Image) {
+	deleted := make([]unversioned.Image, 0, len(failures))
+	kept := make([]unversioned.Image, 0, len(failures))
+
+	for _, f := range failures {
+		if c.deletes(f.class, deleteFailedImages) {
+			deleted = append(deleted, f.img)
+		} else {
+			kept = append(kept, f.img)
+		}
+	}
+
+	return deleted, kept
+}
+
+func (c *FailureConfig) validate() error {
+	for _, class := range []string{failureAuth, failureNotFound, failureTimeout, failureDB, failureCrash, failureUnknown} {
+		p := c.policy(class)
+		if p.Retries < 0 {
+			return fmt.Errorf("invalid retries %d for %s failures: must not be negative", p.Retries, class)
+		}
+
+		switch p.Action {
+		case "", failureActionDelete, failureActionKeep:
+		default:
+			return fmt.Errorf("invalid action %q for %s failures: must be %s or %s", p.Action, class, failureActionDelete, failureActionKeep)
+		}
+	}
+
+	return nil
+}
+
+// sleep waits for the retry delay, returning false if ctx is done first.
+func (c *FailureConfig) sleep(ctx context.Context) bool {
+	t := time.NewTimer(time.Duration(c.RetryDelay))
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+)
+
+func TestClassify(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		desc     string
+		ctx      context.Context
+		stderr   string
+		expected string
+	}{
+		{desc: "timeout", ctx: cancelled, stderr: "", expected: failureTimeout},
+		{desc: "trivy timeout", ctx: context.Background(), stderr: "analyze error: context deadline exceeded", expected: failureTimeout},
+		{desc: "panic", ctx: context.Background(), stderr: "panic: runtime error: invalid memory address", expected: failureCrash},
+		{desc: "DB", ctx: context.Background(), stderr: "failed to open db: open /var/lib/trivy/db/trivy.db: no such file or directory", expected: failureDB},
+		{desc: "auth", ctx: context.Background(), stderr: "GET https://registry.example/v2/app/manifests/1.0: UNAUTHORIZED: authentication required", expected: failureAuth},
+		{desc: "not found", ctx: context.Background(), stderr: "MANIFEST_UNKNOWN: manifest unknown", expected: failureNotFound},
+		{desc: "name unknown", ctx: context.Background(), stderr: "GET https://registry.example/v2/app/manifests/1.0: NAME_UNKNOWN: name unknown", expected: failureNotFound},
+		{desc: "other not found", ctx: context.Background(), stderr: "unable to initialize a scanner: os not found", expected: failureUnknown},
+		{desc: "unknown", ctx: context.Background(), stderr: "something else went wrong", expected: failureUnknown},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			if actual := classify(tt.ctx, errors.New("exit status 1"), tt.stderr); actual != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFailurePartition(t *testing.T) {
+	failures := []scanFailure{
+		{img: unversioned.Image{ImageID: "auth"}, class: failureAuth},
+		{img: unversioned.Image{ImageID: "notFound"}, class: failureNotFound},
+		{img: unversioned.Image{ImageID: "db"}, class: failureDB},
+		{img: unversioned.Image{ImageID: "crash"}, class: failureCrash},
+	}
+
+	notFoundDeleted := DefaultConfig().Failures
+	notFoundDeleted.NotFound.Action = failureActionDelete
+
+	tests := []struct {
+		desc               string
+		cfg                FailureConfig
+		deleteFailedImages bool
+		deleted            []string
+	}{
+		{desc: "deleteFailedImages decides when no action is set", cfg: DefaultConfig().Failures, deleteFailedImages: true, deleted: []string{"notFound", "crash"}},
+		{desc: "defaults keep everything without deleteFailedImages", cfg: DefaultConfig().Failures, deleteFailedImages: false, deleted: []string{}},
+		{desc: "actions override deleteFailedImages", cfg: notFoundDeleted, deleteFailedImages: false, deleted: []string{"notFound"}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			deleted, kept := tt.cfg.partition(failures, tt.deleteFailedImages)
+			if len(deleted)+len(kept) != len(failures) {
+				t.Fatalf("expected every failure to be deleted or kept, got %d and %d", len(deleted), len(kept))
+			}
+
+			want := append([]string{}, tt.deleted...)
+			sort.Strings(want)
+			if got := ids(deleted); strings.Join(got, ",") != strings.Join(want, ",") {
+				t.Errorf("expected %v to be deleted, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestFailureConfigValidate(t *testing.T) {
+	if err := DefaultConfig().Failures.validate(); err != nil {
+		t.Errorf("expected default config to be valid: %v", err)
+	}
+
+	invalidAction := FailureConfig{DB: FailurePolicy{Action: "ignore"}}
+	if err := invalidAction.validate(); err == nil {
+		t.Error("expected an invalid action to be rejected")
+	}
+
+	negativeRetries := FailureConfig{Auth: FailurePolicy{Retries: -1}}
+	if err := negativeRetries.validate(); err == nil {
+		t.Error("expected negative retries to be rejected")
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"strings"
+
+	trivyTypes "github.com/aquasecurity/trivy/pkg/types"
+	"golang.org/x/exp/slices"
+)
+
+const (
+	findingVulnerability    = "vulnerability"
+	findingSecret           = "secret"
+	findingMisconfiguration = "misconfiguration"
+	findingLicense          = "license"
+)
+
+// FindingConfig decides which secret, misconfiguration or license findings
+// make an image non-compliant. Only findings with one of the listed
+// severities count, so an empty list keeps that kind of finding from
+// affecting the verdict. Allow lists finding IDs to accept; for licenses the
+// ID is the license name. Entries ending in `*` match by prefix.
+type FindingConfig struct {
+	Severities []string `json:"severities,omitempty"`
+	Allow      []string `json:"allow,omitempty"`
+}
+
+func (c *FindingConfig) counts(f *finding) bool {
+	if !slices.ContainsFunc(c.Severities, func(s string) bool { return strings.EqualFold(s, f.Severity) }) {
+		return false
+	}
+
+	for _, pattern := range c.Allow {
+		if matchesImagePattern(strings.ToLower(pattern), strings.ToLower(f.ID)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// findingConfig returns the config for a kind of finding other than a
+// vulnerability.
+func (c *Config) findingConfig(kind string) *FindingConfig {
+	switch kind {
+	case findingSecret:
+		return &c.Secrets
+	case findingMisconfiguration:
+		return &c.Misconfigurations
+	case findingLicense:
+		return &c.Licenses
+	default:
+		return nil
+	}
+}
+
+// otherFinding returns the first secret, misconfiguration or license finding
+// which makes the image non-compliant, if any.
+func (c *Config) otherFinding(findings []finding) *finding {
+	for i := range findings {
+		fc := c.findingConfig(findings[i].Kind)
+		if fc != nil && fc.counts(&findings[i]) {
+			return &findings[i]
+		}
+	}
+
+	return nil
+}
+
+// vulnerabilities returns the findings which are vulnerabilities.
+func vulnerabilities(findings []finding) []finding {
+	ret := make([]finding, 0, len(findings))
+	for i := range findings {
+		if findings[i].Kind == findingVulnerability {
+			ret = append(ret, findings[i])
+		}
+	}
+
+	return ret
+}
+
+func newVulnFinding(vuln *trivyTypes.DetectedVulnerability) finding {
+	f := finding{
+		Kind:     findingVulnerability,
+		ID:       vuln.VulnerabilityID,
+		Package:  vuln.PkgName,
+		Severity: vuln.Severity,
+		Fixable:  vuln.FixedVersion != "",
+	}
+
+	// vendors disagree on scores; take the highest, preferring CVSS v3
+	for _, cvss := range vuln.CVSS {
+		score := cvss.V3Score
+		if score == 0 {
+			score = cvss.V2Score
+		}
+		if score > f.CVSS {
+			f.CVSS = score
+		}
+	}
+
+	if vuln.PublishedDate != nil {
+		f.Published = *vuln.PublishedDate
+	}
+
+	return f
+}
+
+// otherFindings returns the secret, failed misconfiguration and license
+// findings in a result. Package holds the file the finding is in, or the
+// package a license belongs to.
+func otherFindings(result *trivyTypes.Result) []finding {
+	ret := make([]finding, 0, len(result.Secrets)+len(result.Misconfigurations)+len(result.Licenses))
+
+	for i := range result.Secrets {
+		s := &result.Secrets[i]
+		ret = append(ret, finding{Kind: findingSecret, ID: s.RuleID, Package: result.Target, Severity: s.Severity})
+	}
+
+	for i := range result.Misconfigurations {
+		m := &result.Misconfigurations[i]
+		if m.Status != trivyTypes.StatusFailure {
+			continue
+		}
+		ret = append(ret, finding{Kind: findingMisconfiguration, ID: m.ID, Package: result.Target, Severity: m.Severity})
+	}
+
+	for i := range result.Licenses {
+		l := &result.Licenses[i]
+		pkg := l.PkgName
+		if pkg == "" {
+			pkg = l.FilePath
+		}
+		ret = append(ret, finding{Kind: findingLicense, ID: l.Name, Package: pkg, Severity: l.Severity})
+	}
+
+	return ret
+}
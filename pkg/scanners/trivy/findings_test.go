@@ -0,0 +1,85 @@
+package main
+
+import (
+	"testing"
+
+	ftypes "github.com/aquasecurity/trivy/pkg/fanal/types"
+	trivyTypes "github.com/aquasecurity/trivy/pkg/types"
+	"github.com/eraser-dev/eraser/api/unversioned"
+)
+
+func TestVerdictOtherFindings(t *testing.T) {
+	secret := finding{Kind: findingSecret, ID: "aws-access-key-id", Package: "/app/.env", Severity: severityCritical}
+	misconf := finding{Kind: findingMisconfiguration, ID: "DS002", Package: "Dockerfile", Severity: severityHigh}
+	license := finding{Kind: findingLicense, ID: "AGPL-3.0", Package: "example", Severity: severityHigh}
+
+	tests := []struct {
+		desc     string
+		cfg      Config
+		findings []finding
+		expected ScanStatus
+	}{
+		{desc: "disabled by default", cfg: Config{}, findings: []finding{secret, misconf, license}, expected: StatusOK},
+		{desc: "secret with matching severity", cfg: Config{Secrets: FindingConfig{Severities: []string{"critical"}}}, findings: []finding{secret}, expected: StatusNonCompliant},
+		{desc: "secret below severity", cfg: Config{Secrets: FindingConfig{Severities: []string{severityHigh}}}, findings: []finding{secret}, expected: StatusOK},
+		{
+			desc:     "allowed secret",
+			cfg:      Config{Secrets: FindingConfig{Severities: []string{severityCritical}, Allow: []string{"aws-*"}}},
+			findings: []finding{secret},
+			expected: StatusOK,
+		},
+		{desc: "misconfiguration", cfg: Config{Misconfigurations: FindingConfig{Severities: []string{severityHigh}}}, findings: []finding{misconf}, expected: StatusNonCompliant},
+		{
+			desc:     "allowed license",
+			cfg:      Config{Licenses: FindingConfig{Severities: []string{severityHigh}, Allow: []string{"agpl-3.0"}}},
+			findings: []finding{license},
+			expected: StatusOK,
+		},
+		{desc: "kinds are configured separately", cfg: Config{Licenses: FindingConfig{Severities: []string{severityHigh}}}, findings: []finding{misconf}, expected: StatusOK},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			s := &ImageScanner{config: tt.cfg}
+			res := &scanResult{Findings: tt.findings}
+			if actual := s.verdict(unversioned.Image{ImageID: "sha256:a"}, res); actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSummarizeOtherFindings(t *testing.T) {
+	report := trivyTypes.Report{
+		Results: trivyTypes.Results{
+			{
+				Target:  "/app/.env",
+				Secrets: []ftypes.SecretFinding{{RuleID: "aws-access-key-id", Severity: severityCritical}},
+				Misconfigurations: []trivyTypes.DetectedMisconfiguration{
+					{ID: "DS001", Severity: severityLow, Status: trivyTypes.StatusPassed},
+					{ID: "DS002", Severity: severityHigh, Status: trivyTypes.StatusFailure},
+				},
+				Licenses: []trivyTypes.DetectedLicense{{Name: "AGPL-3.0", PkgName: "example", Severity: severityHigh}},
+			},
+		},
+	}
+
+	s := &ImageScanner{}
+	res := s.summarize(unversioned.Image{ImageID: "sha256:a"}, &report)
+
+	kinds := map[string]string{}
+	for _, f := range res.Findings {
+		kinds[f.ID] = f.Kind
+	}
+
+	expected := map[string]string{"aws-access-key-id": findingSecret, "DS002": findingMisconfiguration, "AGPL-3.0": findingLicense}
+	if len(kinds) != len(expected) {
+		t.Errorf("unexpected findings: %v", res.Findings)
+	}
+	for id, kind := range expected {
+		if kinds[id] != kind {
+			t.Errorf("expected %s to be a %s finding, got %q", id, kind, kinds[id])
+		}
+	}
+}
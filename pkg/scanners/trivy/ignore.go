@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+	"golang.org/x/exp/slices"
+)
+
+const ignoreDateLayout = "2006-01-02"
+
+// IgnoreRule accepts a vulnerability so that it does not count towards the
+// verdict. Images and Packages narrow the rule; if either is empty it applies
+// to every image or package. Image patterns ending in `*` match by prefix.
+type IgnoreRule struct {
+	ID            string   `json:"id"`
+	Images        []string `json:"images,omitempty"`
+	Packages      []string `json:"packages,omitempty"`
+	Expires       string   `json:"expires,omitempty"`
+	Justification string   `json:"justification,omitempty"`
+}
+
+// expiry parses Expires as either a date, which expires at the end of that
+// day in UTC, or an RFC 3339 timestamp. A zero time means the rule never
+// expires.
+func (r *IgnoreRule) expiry() (time.Time, error) {
+	if r.Expires == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(ignoreDateLayout, r.Expires); err == nil {
+		return t.Add(24 * time.Hour), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, r.Expires)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid expiry %q for ignore rule %q: must be YYYY-MM-DD or RFC 3339", r.Expires, r.ID)
+	}
+
+	return t, nil
+}
+
+// active reports whether the rule is in effect at the given time. Rules with
+// an expiry which cannot be parsed are never in effect, so that a typo does
+// not silently accept a vulnerability forever.
+func (r *IgnoreRule) active(now time.Time) bool {
+	exp, err := r.expiry()
+	if err != nil {
+		return false
+	}
+
+	return exp.IsZero() || now.Before(exp)
+}
+
+func (r *IgnoreRule) matches(img unversioned.Image, vulnID, pkgName string) bool {
+	if !strings.EqualFold(r.ID, vulnID) {
+		return false
+	}
+
+	if len(r.Packages) > 0 && !slices.Contains(r.Packages, pkgName) {
+		return false
+	}
+
+	if len(r.Images) == 0 {
+		return true
+	}
+
+	refs := make([]string, 0, len(img.Names)+len(img.Digests)+1)
+	refs = append(refs, img.ImageID)
+	refs = append(refs, img.Names...)
+	refs = append(refs, img.Digests...)
+
+	for _, pattern := range r.Images {
+		for _, ref := range refs {
+			if matchesImagePattern(pattern, ref) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func matchesImagePattern(pattern, ref string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(ref, prefix)
+	}
+
+	return pattern == ref
+}
+
+// activeIgnores returns the rules which are in effect at the given time.
+func (v *VulnConfig) activeIgnores(now time.Time) []IgnoreRule {
+	active := make([]IgnoreRule, 0, len(v.Ignore))
+	for i := range v.Ignore {
+		if v.Ignore[i].active(now) {
+			active = append(active, v.Ignore[i])
+		}
+	}
+
+	return active
+}
+
+// ignored returns the rule accepting the given finding, if any.
+func ignored(rules []IgnoreRule, img unversioned.Image, vulnID, pkgName string) *IgnoreRule {
+	for i := range rules {
+		if rules[i].matches(img, vulnID, pkgName) {
+			return &rules[i]
+		}
+	}
+
+	return nil
+}
+
+// logIgnoreRules reports rules which are expired or invalid, so that they can
+// be cleaned up.
+func logIgnoreRules(v *VulnConfig, now time.Time) {
+	for i := range v.Ignore {
+		r := &v.Ignore[i]
+		exp, err := r.expiry()
+		switch {
+		case err != nil:
+			log.Error(err, "ignore rule will not be applied")
+		case !exp.IsZero() && !now.Before(exp):
+			log.Info("ignore rule has expired and will not be applied", "id", r.ID, "expires", r.Expires, "justification", r.Justification)
+		}
+	}
+}
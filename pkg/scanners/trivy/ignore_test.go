@@ -0,0 +1,96 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	trivyTypes "github.com/aquasecurity/trivy/pkg/types"
+	"github.com/eraser-dev/eraser/api/unversioned"
+)
+
+func TestIgnoreRuleActive(t *testing.T) {
+	now := time.Date(2023, time.June, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		desc     string
+		expires  string
+		expected bool
+	}{
+		{desc: "no expiry", expires: "", expected: true},
+		{desc: "date in the future", expires: "2023-06-16", expected: true},
+		{desc: "expires at the end of the day", expires: "2023-06-15", expected: true},
+		{desc: "date in the past", expires: "2023-06-14", expected: false},
+		{desc: "timestamp in the past", expires: "2023-06-15T11:00:00Z", expected: false},
+		{desc: "invalid expiry", expires: "next week", expected: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			r := IgnoreRule{ID: "CVE-2023-0001", Expires: tt.expires}
+			if actual := r.active(now); actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestIgnoreRuleMatches(t *testing.T) {
+	img := unversioned.Image{
+		ImageID: "sha256:abc",
+		Names:   []string{"docker.io/library/alpine:3.7"},
+		Digests: []string{"sha256:def"},
+	}
+
+	tests := []struct {
+		desc     string
+		rule     IgnoreRule
+		vulnID   string
+		pkg      string
+		expected bool
+	}{
+		{desc: "ID only", rule: IgnoreRule{ID: "CVE-1"}, vulnID: "CVE-1", pkg: "openssl", expected: true},
+		{desc: "ID is case-insensitive", rule: IgnoreRule{ID: "cve-1"}, vulnID: "CVE-1", expected: true},
+		{desc: "different ID", rule: IgnoreRule{ID: "CVE-2"}, vulnID: "CVE-1", expected: false},
+		{desc: "matching package", rule: IgnoreRule{ID: "CVE-1", Packages: []string{"openssl"}}, vulnID: "CVE-1", pkg: "openssl", expected: true},
+		{desc: "different package", rule: IgnoreRule{ID: "CVE-1", Packages: []string{"zlib"}}, vulnID: "CVE-1", pkg: "openssl", expected: false},
+		{desc: "image prefix", rule: IgnoreRule{ID: "CVE-1", Images: []string{"docker.io/library/*"}}, vulnID: "CVE-1", expected: true},
+		{desc: "exact image name", rule: IgnoreRule{ID: "CVE-1", Images: []string{"docker.io/library/alpine:3.7"}}, vulnID: "CVE-1", expected: true},
+		{desc: "image digest", rule: IgnoreRule{ID: "CVE-1", Images: []string{"sha256:def"}}, vulnID: "CVE-1", expected: true},
+		{desc: "different image", rule: IgnoreRule{ID: "CVE-1", Images: []string{"docker.io/library/alpine:3.8"}}, vulnID: "CVE-1", expected: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			if actual := tt.rule.matches(img, tt.vulnID, tt.pkg); actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestSummarizeIgnoresFindings(t *testing.T) {
+	img := unversioned.Image{ImageID: "sha256:abc", Names: []string{"docker.io/library/alpine:3.7"}}
+	report := trivyTypes.Report{
+		Results: trivyTypes.Results{
+			{
+				Vulnerabilities: []trivyTypes.DetectedVulnerability{
+					{VulnerabilityID: "CVE-1", PkgName: "openssl"},
+					{VulnerabilityID: "CVE-2", PkgName: "zlib"},
+				},
+			},
+		},
+	}
+	report.Results[0].Vulnerabilities[0].Severity = severityCritical
+	report.Results[0].Vulnerabilities[1].Severity = severityHigh
+
+	s := &ImageScanner{config: Config{Vulnerabilities: VulnConfig{
+		Ignore: []IgnoreRule{{ID: "CVE-1", Images: []string{"docker.io/library/*"}, Justification: "accepted"}},
+	}}}
+
+	res := s.summarize(img, &report)
+	if len(res.Findings) != 1 || res.Findings[0].ID != "CVE-2" {
+		t.Errorf("unexpected findings: %v", res.Findings)
+	}
+}
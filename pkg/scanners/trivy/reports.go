@@ -0,0 +1,287 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+)
+
+const (
+	reportFormatJSON      = "json"
+	reportFormatSARIF     = "sarif"
+	reportFormatCycloneDX = "cyclonedx"
+
+	reportIndexName = "index.json"
+
+	trivyConvertArg     = "convert"
+	trivyFormatFlag     = "--format"
+	trivyOutputFlag     = "--output"
+	trivyListAllPkgFlag = "--list-all-pkgs"
+)
+
+var reportExtensions = map[string]string{
+	reportFormatJSON:      ".json",
+	reportFormatSARIF:     ".sarif.json",
+	reportFormatCycloneDX: ".cdx.json",
+}
+
+type (
+	// ReportConfig persists the full trivy report of every scanned image.
+	// Reports are written to Dir, to S3, or both. An empty Format disables
+	// reports.
+	ReportConfig struct {
+		Format string   `json:"format,omitempty"`
+		Dir    string   `json:"dir,omitempty"`
+		S3     S3Config `json:"s3,omitempty"`
+	}
+
+	reportSink interface {
+		put(name string, data []byte) error
+	}
+
+	// reporter writes reports under <node>/<job>/ in each sink, along with an
+	// index of every image scanned in the run.
+	reporter struct {
+		format string
+		sinks  []reportSink
+		prefix string
+
+		mu    sync.Mutex
+		index reportIndex
+	}
+
+	reportIndex struct {
+		Node     string        `json:"node"`
+		Job      string        `json:"job"`
+		Format   string        `json:"format"`
+		Started  time.Time     `json:"started"`
+		Finished time.Time     `json:"finished,omitempty"`
+		Images   []reportEntry `json:"images"`
+	}
+
+	reportEntry struct {
+		ImageID string   `json:"imageID"`
+		Names   []string `json:"names,omitempty"`
+		Verdict string   `json:"verdict"`
+		Failure string   `json:"failure,omitempty"`
+		Cached  bool     `json:"cached,omitempty"`
+		Report  string   `json:"report,omitempty"`
+
+		DBVersion   int       `json:"dbVersion,omitempty"`
+		DBUpdatedAt time.Time `json:"dbUpdatedAt,omitempty"`
+	}
+
+	dirSink struct {
+		dir string
+	}
+)
+
+func (c *ReportConfig) validate() error {
+	if c.Format == "" {
+		return nil
+	}
+
+	if _, ok := reportExtensions[c.Format]; !ok {
+		return fmt.Errorf("invalid report format %q: must be %s, %s or %s", c.Format, reportFormatJSON, reportFormatSARIF, reportFormatCycloneDX)
+	}
+
+	if c.Dir == "" && c.S3.Bucket == "" {
+		return fmt.Errorf("reports.dir or reports.s3.bucket must be set when reports.format is set")
+	}
+
+	return c.S3.validate()
+}
+
+// newReporter returns nil if reports are disabled. The job name is used to
+// group the reports of one run; if it is unknown, the start time is used.
+func newReporter(cfg *ReportConfig, node, job string, now time.Time) (*reporter, error) {
+	if cfg.Format == "" {
+		return nil, nil
+	}
+
+	var sinks []reportSink
+	if cfg.Dir != "" {
+		sinks = append(sinks, &dirSink{dir: cfg.Dir})
+	}
+
+	if cfg.S3.Bucket != "" {
+		s3, err := newS3Sink(&cfg.S3)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, s3)
+	}
+
+	if node == "" {
+		node = "unknown-node"
+	}
+	if job == "" {
+		job = now.UTC().Format("20060102T150405Z")
+	}
+
+	return &reporter{
+		format: cfg.Format,
+		sinks:  sinks,
+		prefix: path.Join(node, job),
+		index: reportIndex{
+			Node:    node,
+			Job:     job,
+			Format:  cfg.Format,
+			Started: now,
+			Images:  []reportEntry{},
+		},
+	}, nil
+}
+
+// record persists the report, if any, and adds the image to the index.
+// cached is whether the result, and the report with it, came from the cache.
+// Failures to write a report are logged and do not affect the verdict.
+func (r *reporter) record(img unversioned.Image, raw []byte, status ScanStatus, res *scanResult, failure string, cached bool) {
+	entry := reportEntry{
+		ImageID: img.ImageID,
+		Names:   img.Names,
+		Verdict: verdictName(status),
+		Failure: failure,
+		Cached:  cached,
+	}
+
+	if res != nil {
+		entry.DBVersion = res.DB.Version
+		entry.DBUpdatedAt = res.DB.UpdatedAt
+	}
+
+	if raw != nil {
+		name := path.Join(r.prefix, reportFileName(img.ImageID)+reportExtensions[r.format])
+		if err := r.save(name, raw); err != nil {
+			log.Error(err, "unable to persist scan report", "imageID", img.ImageID)
+		} else {
+			entry.Report = name
+		}
+	}
+
+	r.mu.Lock()
+	r.index.Images = append(r.index.Images, entry)
+	r.mu.Unlock()
+}
+
+func (r *reporter) save(name string, raw []byte) error {
+	data := raw
+	if r.format != reportFormatJSON {
+		var err error
+		data, err = convertReport(raw, r.format)
+		if err != nil {
+			return err
+		}
+	}
+
+	return r.put(name, data)
+}
+
+func (r *reporter) put(name string, data []byte) error {
+	var errs []string
+	for _, sink := range r.sinks {
+		if err := sink.put(name, data); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("unable to write %s: %s", name, strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+// finish writes the index of the run.
+func (r *reporter) finish(now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.index.Finished = now
+	b, err := json.MarshalIndent(&r.index, "", "  ")
+	if err != nil {
+		log.Error(err, "unable to marshal report index")
+		return
+	}
+
+	if err := r.put(path.Join(r.prefix, reportIndexName), b); err != nil {
+		log.Error(err, "unable to persist report index")
+		return
+	}
+
+	log.Info("persisted scan reports", "prefix", r.prefix, "images", len(r.index.Images))
+}
+
+func verdictName(status ScanStatus) string {
+	switch status {
+	case StatusNonCompliant:
+		return "nonCompliant"
+	case StatusOK:
+		return "compliant"
+	default:
+		return "failed"
+	}
+}
+
+// reportFileName turns an image ID such as sha256:abc into a name which is
+// valid for both file systems and object stores.
+func reportFileName(imageID string) string {
+	return strings.NewReplacer(":", "-", "/", "-").Replace(imageID)
+}
+
+// convertReport converts a JSON report to another format with trivy convert.
+func convertReport(raw []byte, format string) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "report-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "report.json")
+	out := filepath.Join(dir, "report.out")
+	if err := os.WriteFile(in, raw, 0o600); err != nil {
+		return nil, err
+	}
+
+	stderr := new(bytes.Buffer)
+	cmd := exec.Command(trivyCommandName, trivyConvertArg, trivyFormatFlag, format, trivyOutputFlag, out, in)
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("unable to convert report to %s: %w: %s", format, err, stderr.String())
+	}
+
+	return os.ReadFile(out)
+}
+
+func (d *dirSink) put(name string, data []byte) error {
+	p := filepath.Join(d.dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(p), "tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), p)
+}
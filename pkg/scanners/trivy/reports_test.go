@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+)
+
+func TestReporterDir(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2023, time.June, 15, 0, 0, 0, 0, time.UTC)
+
+	r, err := newReporter(&ReportConfig{Format: reportFormatJSON, Dir: dir}, "node-1", "imagejob-abc", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := &scanResult{DB: dbMetadata{Version: 2, UpdatedAt: now}}
+	r.record(unversioned.Image{ImageID: "sha256:a"}, []byte(`{"ArtifactName":"a"}`), StatusNonCompliant, res, "", false)
+	r.record(unversioned.Image{ImageID: "sha256:b"}, nil, StatusOK, res, "", true)
+	r.record(unversioned.Image{ImageID: "sha256:c"}, nil, StatusFailed, nil, failureNotFound, false)
+	r.record(unversioned.Image{ImageID: "sha256:d"}, []byte(`{"ArtifactName":"d"}`), StatusOK, res, "", true)
+	r.finish(now.Add(time.Minute))
+
+	report, err := os.ReadFile(filepath.Join(dir, "node-1", "imagejob-abc", "sha256-a.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(report) != `{"ArtifactName":"a"}` {
+		t.Errorf("unexpected report: %s", report)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "node-1", "imagejob-abc", reportIndexName))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var index reportIndex
+	if err := json.Unmarshal(b, &index); err != nil {
+		t.Fatal(err)
+	}
+
+	cachedReport, err := os.ReadFile(filepath.Join(dir, "node-1", "imagejob-abc", "sha256-d.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(cachedReport) != `{"ArtifactName":"d"}` {
+		t.Errorf("unexpected cached report: %s", cachedReport)
+	}
+
+	if index.Node != "node-1" || index.Job != "imagejob-abc" || len(index.Images) != 4 {
+		t.Fatalf("unexpected index: %s", b)
+	}
+
+	expected := []reportEntry{
+		{ImageID: "sha256:a", Verdict: "nonCompliant", Report: "node-1/imagejob-abc/sha256-a.json", DBVersion: 2},
+		{ImageID: "sha256:b", Verdict: "compliant", Cached: true, DBVersion: 2},
+		{ImageID: "sha256:c", Verdict: "failed", Failure: failureNotFound},
+		{ImageID: "sha256:d", Verdict: "compliant", Report: "node-1/imagejob-abc/sha256-d.json", Cached: true, DBVersion: 2},
+	}
+	for i, e := range expected {
+		got := index.Images[i]
+		if got.ImageID != e.ImageID || got.Verdict != e.Verdict || got.Report != e.Report || got.Cached != e.Cached || got.Failure != e.Failure || got.DBVersion != e.DBVersion {
+			t.Errorf("unexpected entry %d: %#v", i, got)
+		}
+	}
+}
+
+func TestReportConfigValidate(t *testing.T) {
+	tests := []struct {
+		desc    string
+		cfg     ReportConfig
+		wantErr bool
+	}{
+		{desc: "disabled", cfg: ReportConfig{}, wantErr: false},
+		{desc: "dir", cfg: ReportConfig{Format: reportFormatSARIF, Dir: "/reports"}, wantErr: false},
+		{desc: "no sink", cfg: ReportConfig{Format: reportFormatJSON}, wantErr: true},
+		{desc: "invalid format", cfg: ReportConfig{Format: "xml", Dir: "/reports"}, wantErr: true},
+		{desc: "s3 without endpoint", cfg: ReportConfig{Format: reportFormatJSON, S3: S3Config{Bucket: "reports"}}, wantErr: true},
+		{desc: "s3", cfg: ReportConfig{Format: reportFormatCycloneDX, S3: S3Config{Bucket: "reports", Endpoint: "http://minio:9000"}}, wantErr: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			if err := tt.cfg.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("expected error: %v, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestS3SinkPut(t *testing.T) {
+	var gotPath, gotAuth, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		gotPath, gotAuth, gotBody = r.URL.Path, r.Header.Get("Authorization"), string(b)
+	}))
+	defer server.Close()
+
+	t.Setenv(s3AccessKeyIDEnv, "minioadmin")
+	t.Setenv(s3SecretKeyEnv, "minioadmin")
+
+	s, err := newS3Sink(&S3Config{Endpoint: server.URL, Bucket: "reports", Prefix: "eraser"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.put("node-1/job/index.json", []byte("{}")); err != nil {
+		t.Fatal(err)
+	}
+
+	if gotPath != "/reports/eraser/node-1/job/index.json" {
+		t.Errorf("unexpected path: %s", gotPath)
+	}
+	if gotBody != "{}" {
+		t.Errorf("unexpected body: %s", gotBody)
+	}
+	if !strings.HasPrefix(gotAuth, "AWS4-HMAC-SHA256 Credential=minioadmin/") || !strings.Contains(gotAuth, "/us-east-1/s3/aws4_request") {
+		t.Errorf("unexpected authorization header: %s", gotAuth)
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	s3DefaultRegion    = "us-east-1"
+	s3RequestTimeout   = time.Minute
+	s3AccessKeyIDEnv   = "AWS_ACCESS_KEY_ID"
+	s3SecretKeyEnv     = "AWS_SECRET_ACCESS_KEY"
+	s3SigningAlgorithm = "AWS4-HMAC-SHA256"
+	s3SignedHeaders    = "content-type;host;x-amz-content-sha256;x-amz-date"
+	s3ContentType      = "application/json"
+)
+
+type (
+	// S3Config uploads reports to an S3-compatible object store, such as
+	// MinIO, using path-style URLs. Credentials are read from the files, if
+	// set, or from AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY.
+	S3Config struct {
+		Endpoint            string `json:"endpoint,omitempty"`
+		Bucket              string `json:"bucket,omitempty"`
+		Prefix              string `json:"prefix,omitempty"`
+		Region              string `json:"region,omitempty"`
+		AccessKeyIDFile     string `json:"accessKeyIDFile,omitempty"`
+		SecretAccessKeyFile string `json:"secretAccessKeyFile,omitempty"`
+	}
+
+	s3Sink struct {
+		endpoint  *url.URL
+		bucket    string
+		prefix    string
+		region    string
+		accessKey string
+		secretKey string
+		client    *http.Client
+	}
+)
+
+func (c *S3Config) validate() error {
+	if c.Bucket == "" {
+		return nil
+	}
+
+	if c.Endpoint == "" {
+		return fmt.Errorf("reports.s3.endpoint must be set when reports.s3.bucket is set")
+	}
+
+	u, err := url.Parse(c.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid reports.s3.endpoint %q: must be an http or https URL", c.Endpoint)
+	}
+
+	return nil
+}
+
+func newS3Sink(cfg *S3Config) (*s3Sink, error) {
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	accessKey, err := readCredential(cfg.AccessKeyIDFile, s3AccessKeyIDEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	secretKey, err := readCredential(cfg.SecretAccessKeyFile, s3SecretKeyEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	region := cfg.Region
+	if region == "" {
+		region = s3DefaultRegion
+	}
+
+	return &s3Sink{
+		endpoint:  endpoint,
+		bucket:    cfg.Bucket,
+		prefix:    cfg.Prefix,
+		region:    region,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		client:    &http.Client{Timeout: s3RequestTimeout},
+	}, nil
+}
+
+func readCredential(file, env string) (string, error) {
+	if file == "" {
+		v := os.Getenv(env)
+		if v == "" {
+			return "", fmt.Errorf("no S3 credential: set %s or the corresponding file", env)
+		}
+		return v, nil
+	}
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
+
+func (s *s3Sink) put(name string, data []byte) error {
+	u := *s.endpoint
+	u.Path = path.Join("/", s.endpoint.Path, s.bucket, s.prefix, name)
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPut, u.String(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", s3ContentType)
+	s.sign(req, data, time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status uploading %s: %s: %s", name, resp.Status, string(body))
+	}
+
+	return nil
+}
+
+// sign adds an AWS Signature Version 4 Authorization header to req.
+func (s *s3Sink) sign(req *http.Request, payload []byte, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	canonicalHeaders := fmt.Sprintf("content-type:%s\nhost:%s\nx-amz-content-sha256:%s\nx-amz-date:%s\n",
+		req.Header.Get("Content-Type"), req.URL.Host, payloadHash, amzDate)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		s3SignedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, s.region, "s3", "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{s3SigningAlgorithm, amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3SigningAlgorithm, s.accessKey, scope, s3SignedHeaders, signature))
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
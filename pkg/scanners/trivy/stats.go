@@ -0,0 +1,66 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/eraser-dev/eraser/pkg/metrics"
+)
+
+// scanStats collects the metrics of a scanner run. It is safe for use by
+// several workers.
+type scanStats struct {
+	mu    sync.Mutex
+	stats metrics.ScanStats
+}
+
+func newScanStats() *scanStats {
+	return &scanStats{stats: metrics.ScanStats{Severities: map[string]int64{}}}
+}
+
+// observe adds an image which was scanned in d, or whose result was cached
+// if d is zero. res is nil if the scan failed.
+func (s *scanStats) observe(res *scanResult, d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if d > 0 {
+		s.stats.ObserveDuration(d)
+	}
+
+	if res == nil {
+		return
+	}
+
+	if res.EOL {
+		s.stats.EOLImages++
+	}
+
+	severities := map[string]struct{}{}
+	for _, f := range vulnerabilities(res.Findings) {
+		severities[f.Severity] = struct{}{}
+	}
+	for severity := range severities {
+		s.stats.Severities[severity]++
+	}
+}
+
+// finish returns the stats of the run, with failures counted by class.
+func (s *scanStats) finish(failures []scanFailure, db dbMetadata, now time.Time) *metrics.ScanStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := s.stats
+	if len(failures) > 0 {
+		stats.Failures = map[string]int64{}
+		for _, f := range failures {
+			stats.Failures[f.class]++
+		}
+	}
+
+	if !db.UpdatedAt.IsZero() {
+		stats.DBAge = now.Sub(db.UpdatedAt).Seconds()
+	}
+
+	return &stats
+}
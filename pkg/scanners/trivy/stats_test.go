@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+)
+
+func TestScanStats(t *testing.T) {
+	s := newScanStats()
+
+	s.observe(&scanResult{EOL: true, Findings: []finding{
+		{Kind: findingVulnerability, Severity: "CRITICAL"},
+		{Kind: findingVulnerability, Severity: "CRITICAL"},
+		{Kind: findingVulnerability, Severity: "LOW"},
+	}}, 3*time.Second)
+	// cached results count towards the gauges, but have no duration
+	s.observe(&scanResult{Findings: []finding{
+		{Kind: findingVulnerability, Severity: "CRITICAL"},
+		{Kind: findingSecret, Severity: "HIGH"},
+	}}, 0)
+	s.observe(nil, 20*time.Second)
+
+	now := time.Date(2023, time.June, 15, 12, 0, 0, 0, time.UTC)
+	failures := []scanFailure{
+		{img: unversioned.Image{ImageID: "a"}, class: failureAuth},
+		{img: unversioned.Image{ImageID: "b"}, class: failureAuth},
+		{img: unversioned.Image{ImageID: "c"}, class: failureTimeout},
+	}
+	stats := s.finish(failures, dbMetadata{UpdatedAt: now.Add(-6 * time.Hour)}, now)
+
+	if stats.Severities["CRITICAL"] != 2 || stats.Severities["LOW"] != 1 || len(stats.Severities) != 2 {
+		t.Errorf("unexpected severities: %v", stats.Severities)
+	}
+	if stats.EOLImages != 1 {
+		t.Errorf("expected 1 EOL image, got %d", stats.EOLImages)
+	}
+	if stats.Failures[failureAuth] != 2 || stats.Failures[failureTimeout] != 1 {
+		t.Errorf("unexpected failures: %v", stats.Failures)
+	}
+	if stats.DBAge != (6 * time.Hour).Seconds() {
+		t.Errorf("expected a DB age of 6h, got %vs", stats.DBAge)
+	}
+
+	var scans int64
+	for _, b := range stats.Durations {
+		scans += b.Count
+	}
+	if scans != 2 {
+		t.Errorf("expected 2 scan durations, got %d", scans)
+	}
+}
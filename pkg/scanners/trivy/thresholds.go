@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+)
+
+// ThresholdConfig decides when the vulnerabilities found in an image make it
+// non-compliant. FixableOnly and MinAge select which vulnerabilities are
+// counted; Counts, CVSSScore and RiskScore are the thresholds, any one of
+// which makes the image non-compliant. If no threshold is set, a single
+// counted vulnerability is enough.
+type ThresholdConfig struct {
+	Counts      map[string]int64     `json:"counts,omitempty"`
+	CVSSScore   float64              `json:"cvssScore,omitempty"`
+	RiskScore   float64              `json:"riskScore,omitempty"`
+	RiskWeights map[string]float64   `json:"riskWeights,omitempty"`
+	FixableOnly bool                 `json:"fixableOnly,omitempty"`
+	MinAge      unversioned.Duration `json:"minAge,omitempty"`
+}
+
+// severityRanks orders the known severities, most severe first.
+var severityRanks = map[string]int{
+	severityCritical: 0,
+	severityHigh:     1,
+	severityMedium:   2,
+	severityLow:      3,
+	severityUnknown:  4,
+}
+
+var defaultRiskWeights = map[string]float64{
+	severityCritical: 10,
+	severityHigh:     5,
+	severityMedium:   2,
+	severityLow:      1,
+	severityUnknown:  0,
+}
+
+// counted returns the findings which the thresholds apply to. Findings
+// without a published date are never old enough to count towards MinAge.
+func (t *ThresholdConfig) counted(findings []finding, now time.Time) []finding {
+	ret := make([]finding, 0, len(findings))
+	for i := range findings {
+		f := findings[i]
+
+		if t.FixableOnly && !f.Fixable {
+			continue
+		}
+
+		if t.MinAge > 0 && (f.Published.IsZero() || now.Sub(f.Published) < time.Duration(t.MinAge)) {
+			continue
+		}
+
+		ret = append(ret, f)
+	}
+
+	return ret
+}
+
+// bySeverityRank returns the severities of counts, most severe first, so that
+// the threshold reported as reached doesn't depend on map order. Severities
+// which aren't known come last, in alphabetical order.
+func bySeverityRank(counts map[string]int64) []string {
+	severities := make([]string, 0, len(counts))
+	for severity := range counts {
+		severities = append(severities, severity)
+	}
+
+	rank := func(severity string) int {
+		if r, ok := severityRanks[strings.ToUpper(severity)]; ok {
+			return r
+		}
+		return len(severityRanks)
+	}
+
+	sort.Slice(severities, func(i, j int) bool {
+		ri, rj := rank(severities[i]), rank(severities[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return severities[i] < severities[j]
+	})
+
+	return severities
+}
+
+// exceeded reports whether the findings reach any threshold, and which one.
+func (t *ThresholdConfig) exceeded(findings []finding, now time.Time) (string, bool) {
+	counted := t.counted(findings, now)
+
+	if len(t.Counts) == 0 && t.CVSSScore <= 0 && t.RiskScore <= 0 {
+		return "any", len(counted) > 0
+	}
+
+	if len(t.Counts) > 0 {
+		bySeverity := map[string]int64{}
+		for i := range counted {
+			bySeverity[strings.ToUpper(counted[i].Severity)]++
+		}
+
+		for _, severity := range bySeverityRank(t.Counts) {
+			if threshold := t.Counts[severity]; threshold > 0 && bySeverity[strings.ToUpper(severity)] >= threshold {
+				return fmt.Sprintf("counts.%s", severity), true
+			}
+		}
+	}
+
+	if t.CVSSScore > 0 {
+		for i := range counted {
+			if counted[i].CVSS >= t.CVSSScore {
+				return "cvssScore", true
+			}
+		}
+	}
+
+	if t.RiskScore > 0 {
+		weights := defaultRiskWeights
+		if len(t.RiskWeights) > 0 {
+			weights = map[string]float64{}
+			for severity, w := range t.RiskWeights {
+				weights[strings.ToUpper(severity)] = w
+			}
+		}
+
+		var score float64
+		for i := range counted {
+			score += weights[strings.ToUpper(counted[i].Severity)]
+		}
+
+		if score >= t.RiskScore {
+			return "riskScore", true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+)
+
+func TestThresholdsExceeded(t *testing.T) {
+	now := time.Date(2023, time.June, 15, 0, 0, 0, 0, time.UTC)
+	old := now.Add(-60 * 24 * time.Hour)
+	recent := now.Add(-24 * time.Hour)
+
+	findings := []finding{
+		{ID: "CVE-1", Severity: severityCritical, CVSS: 9.8, Fixable: false, Published: old},
+		{ID: "CVE-2", Severity: severityHigh, CVSS: 7.5, Fixable: true, Published: recent},
+		{ID: "CVE-3", Severity: severityMedium, CVSS: 5.3, Fixable: true, Published: old},
+		{ID: "CVE-4", Severity: severityLow, Fixable: true},
+	}
+
+	tests := []struct {
+		desc     string
+		cfg      ThresholdConfig
+		findings []finding
+		expected bool
+	}{
+		{desc: "default: no findings", cfg: ThresholdConfig{}, findings: nil, expected: false},
+		{desc: "default: any finding", cfg: ThresholdConfig{}, findings: findings, expected: true},
+		{desc: "critical count reached", cfg: ThresholdConfig{Counts: map[string]int64{"critical": 1}}, findings: findings, expected: true},
+		{desc: "critical count not reached", cfg: ThresholdConfig{Counts: map[string]int64{severityCritical: 2}}, findings: findings, expected: false},
+		{desc: "CVSS reached", cfg: ThresholdConfig{CVSSScore: 9}, findings: findings, expected: true},
+		{desc: "CVSS not reached", cfg: ThresholdConfig{CVSSScore: 9.9}, findings: findings, expected: false},
+		{desc: "fixable only excludes unfixed critical", cfg: ThresholdConfig{CVSSScore: 9, FixableOnly: true}, findings: findings, expected: false},
+		{
+			desc:     "min age excludes recent and undated findings",
+			cfg:      ThresholdConfig{FixableOnly: true, MinAge: unversioned.Duration(30 * 24 * time.Hour)},
+			findings: findings[1:2],
+			expected: false,
+		},
+		{
+			desc:     "fixable and old enough",
+			cfg:      ThresholdConfig{FixableOnly: true, MinAge: unversioned.Duration(30 * 24 * time.Hour)},
+			findings: findings,
+			expected: true,
+		},
+		{desc: "default risk weights reached", cfg: ThresholdConfig{RiskScore: 18}, findings: findings, expected: true},
+		{desc: "default risk weights not reached", cfg: ThresholdConfig{RiskScore: 19}, findings: findings, expected: false},
+		{
+			desc:     "custom risk weights",
+			cfg:      ThresholdConfig{RiskScore: 3, RiskWeights: map[string]float64{"low": 3}},
+			findings: findings,
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			if _, actual := tt.cfg.exceeded(tt.findings, now); actual != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestThresholdsExceededReason(t *testing.T) {
+	findings := []finding{
+		{ID: "CVE-1", Severity: severityCritical},
+		{ID: "CVE-2", Severity: severityHigh},
+		{ID: "CVE-3", Severity: severityLow},
+		{ID: "CVE-4", Severity: "NEGLIGIBLE"},
+	}
+
+	cfg := ThresholdConfig{Counts: map[string]int64{"negligible": 1, "low": 1, "high": 1, "critical": 1}}
+	for i := 0; i < 20; i++ {
+		if reason, _ := cfg.exceeded(findings, time.Now()); reason != "counts.critical" {
+			t.Fatalf("expected counts.critical, got %s", reason)
+		}
+	}
+
+	cfg = ThresholdConfig{Counts: map[string]int64{"negligible": 1, "low": 1, "critical": 2}}
+	if reason, _ := cfg.exceeded(findings, time.Now()); reason != "counts.low" {
+		t.Errorf("expected counts.low, got %s", reason)
+	}
+}
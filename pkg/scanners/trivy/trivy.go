@@ -5,18 +5,19 @@ import (
 	"errors"
 	"flag"
 	"fmt"
-	"net/http"
 	"os"
 	"time"
 
 	"github.com/eraser-dev/eraser/api/unversioned"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
-	_ "net/http/pprof"
-
 	trivylogger "github.com/aquasecurity/trivy/pkg/log"
 	"github.com/eraser-dev/eraser/pkg/logger"
+	"github.com/eraser-dev/eraser/pkg/policy"
 	"github.com/eraser-dev/eraser/pkg/scanners/template"
+	"github.com/eraser-dev/eraser/pkg/tracing"
 	"github.com/eraser-dev/eraser/pkg/utils"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -70,13 +71,9 @@ func main() {
 	log.Info("config", "config", *config)
 
 	userConfig := *DefaultConfig()
-	if *config != "" {
-		var err error
-		userConfig, err = loadConfig(*config)
-		if err != nil {
-			log.Error(err, "unable to read config")
-			os.Exit(generalErr)
-		}
+	if err := template.LoadConfig(*config, &userConfig); err != nil {
+		log.Error(err, "unable to read config")
+		os.Exit(generalErr)
 	}
 
 	log.V(1).Info("userConfig",
@@ -85,69 +82,75 @@ func main() {
 	)
 
 	if *enableProfile {
-		go runProfileServer()
+		go template.RunProfileServer(log, *profilePort)
 	}
 
-	recordMetrics := false
-	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" {
-		recordMetrics = true
+	ctx, shutdownTracing, err := tracing.ConfigureFromEnv(context.Background())
+	if err != nil {
+		log.Error(err, "unable to configure tracing")
 	}
 
-	ctx := context.Background()
 	provider := template.NewImageProvider(
 		template.WithContext(ctx),
 		template.WithLogger(log),
-		template.WithMetrics(recordMetrics),
-		template.WithDeleteScanFailedImages(userConfig.DeleteFailedImages),
+		template.WithMetrics(os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != ""),
+		// the failure policies below decide which failed images are sent
+		template.WithDeleteScanFailedImages(true),
 		template.WithDeleteEOLImages(userConfig.DeleteEOLImages),
 	)
 
-	allImages, err := provider.ReceiveImages()
-	if err != nil {
-		log.Error(err, "unable to read images from provider")
-		os.Exit(generalErr)
+	err = template.Run(ctx, provider, log, func() (template.ScanFunc, error) {
+		s, err := initScanner(&userConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(ctx context.Context, allImages []unversioned.Image) ([]unversioned.Image, []unversioned.Image, error) {
+			return scanAndReport(ctx, s, provider, &userConfig, allImages)
+		}, nil
+	})
+
+	if err := shutdownTracing(context.Background()); err != nil {
+		log.Error(err, "unable to export traces")
 	}
 
-	s, err := initScanner(&userConfig)
 	if err != nil {
-		log.Error(err, "error initializing scanner")
+		log.Error(err, "unable to scan images")
+		os.Exit(generalErr)
 	}
+}
 
-	vulnerableImages, failedImages, err := scan(s, allImages)
-	if err != nil {
-		log.Error(err, "total image scan timed out")
+// scanAndReport scans the images, records the scan's statistics and reports,
+// and returns the vulnerable images and the failed images to be removed.
+// Removed failures are returned separately, so that they aren't counted as
+// vulnerable.
+func scanAndReport(ctx context.Context, s *ImageScanner, provider template.ImageProvider, userConfig *Config, allImages []unversioned.Image) ([]unversioned.Image, []unversioned.Image, error) {
+	scanCtx, span := tracing.Tracer().Start(ctx, "scanner.scanImages", trace.WithAttributes(attribute.Int("images", len(allImages))))
+	vulnerableImages, failures, err := scan(scanCtx, s, allImages, userConfig.Workers)
+	span.SetAttributes(attribute.Int("images.vulnerable", len(vulnerableImages)), attribute.Int("images.failed", len(failures)))
+	tracing.End(span, err)
+
+	if s.reports != nil {
+		s.reports.finish(time.Now())
 	}
 
+	provider.RecordStats(s.stats.finish(failures, s.db, time.Now()))
+
+	deletedFailures, keptFailures := userConfig.Failures.partition(failures, userConfig.DeleteFailedImages)
 	log.Info("Vulnerable", "Images", vulnerableImages, "Total count", len(vulnerableImages))
 
-	if len(failedImages) > 0 {
-		log.Info("Failed", "Images", failedImages)
+	if len(deletedFailures) > 0 {
+		log.Info("Failed, to be removed", "Images", deletedFailures)
 	}
 
-	err = provider.SendImages(vulnerableImages, failedImages)
-	if err != nil {
-		log.Error(err, "unable to write images")
+	if len(keptFailures) > 0 {
+		log.Info("Failed, to be kept", "Images", keptFailures)
 	}
 
-	log.Info("scanning complete, waiting for remover to finish...")
-	err = provider.Finish()
-	if err != nil {
-		log.Error(err, "unable to complete scanning process")
-	}
-
-	log.Info("remover job completed, shutting down...")
-}
-
-func runProfileServer() {
-	server := &http.Server{
-		Addr:              fmt.Sprintf("localhost:%d", *profilePort),
-		ReadHeaderTimeout: 3 * time.Second,
-	}
-	err := server.ListenAndServe()
-	log.Error(err, "pprof server failed")
+	return vulnerableImages, deletedFailures, err
 }
 
-func initScanner(userConfig *Config) (Scanner, error) {
+func initScanner(userConfig *Config) (*ImageScanner, error) {
 	if userConfig == nil {
 		return nil, fmt.Errorf("invalid trivy scanner config")
 	}
@@ -160,49 +163,99 @@ func initScanner(userConfig *Config) (Scanner, error) {
 	sugar := logger.Sugar()
 	trivylogger.Logger = sugar
 
+	if err := userConfig.Failures.validate(); err != nil {
+		return nil, err
+	}
+
+	if err := userConfig.Reports.validate(); err != nil {
+		return nil, err
+	}
+
 	userConfig.Runtime = unversioned.RuntimeSpec{
 		Name:    unversioned.Runtime(os.Getenv(utils.EnvEraserRuntimeName)),
 		Address: utils.CRIPath,
 	}
 
+	// expiry is only checked here, so that the rules in effect are the same
+	// for every image and are reflected in the cache key
+	now := time.Now()
+	logIgnoreRules(&userConfig.Vulnerabilities, now)
+	userConfig.Vulnerabilities.Ignore = userConfig.Vulnerabilities.activeIgnores(now)
+
+	db, err := prepareDB(userConfig, now)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("using vulnerability DB", "dbVersion", db.Version, "dbUpdatedAt", db.UpdatedAt)
+
+	imagePolicy, err := policy.FromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("error loading image policy: %w", err)
+	}
+	nodeName, nodeLabels := policy.NodeFromEnv()
+
+	reports, err := newReporter(&userConfig.Reports, nodeName, os.Getenv(utils.EnvEraserJobName), now)
+	if err != nil {
+		return nil, fmt.Errorf("error setting up scan reports: %w", err)
+	}
+
 	totalTimeout := time.Duration(userConfig.Timeout.Total)
 	timer := time.NewTimer(totalTimeout)
 
-	var s Scanner = &ImageScanner{
-		config: *userConfig,
-		timer:  timer,
+	s := &ImageScanner{
+		config:     *userConfig,
+		timer:      timer,
+		cache:      initCache(userConfig, db),
+		db:         *db,
+		reports:    reports,
+		stats:      newScanStats(),
+		policy:     imagePolicy,
+		nodeName:   nodeName,
+		nodeLabels: nodeLabels,
 	}
 	return s, nil
 }
 
-func scan(s Scanner, allImages []unversioned.Image) ([]unversioned.Image, []unversioned.Image, error) {
-	vulnerableImages := make([]unversioned.Image, 0, len(allImages))
-	failedImages := make([]unversioned.Image, 0, len(allImages))
-	// track total scan job time
-
-	for idx, img := range allImages {
-		select {
-		case <-s.Timer().C:
-			failedImages = append(failedImages, allImages[idx:]...)
-			return vulnerableImages, failedImages, errors.New("image scan total timeout exceeded")
-		default:
-			// Logs scan failures
-			status, err := s.Scan(img)
-			if err != nil {
-				failedImages = append(failedImages, img)
-				log.Error(err, "scan failed")
-				continue
-			}
-
-			switch status {
-			case StatusNonCompliant:
-				log.Info("vulnerable image found", "img", img)
-				vulnerableImages = append(vulnerableImages, img)
-			case StatusFailed:
-				failedImages = append(failedImages, img)
-			}
+// initCache returns nil if the cache is disabled or cannot be used. Any
+// failure disables the cache rather than the scan.
+func initCache(userConfig *Config, db *dbMetadata) *resultCache {
+	if userConfig.Cache.Dir == "" {
+		return nil
+	}
+
+	cache, err := newResultCache(userConfig, db)
+	if err != nil {
+		log.Error(err, "unable to initialize scan result cache, scan result cache disabled")
+		return nil
+	}
+
+	cache.prune(time.Duration(userConfig.Cache.MaxAge))
+	log.Info("using scan result cache", "dir", userConfig.Cache.Dir, "dbVersion", db.Version, "dbUpdatedAt", db.UpdatedAt)
+
+	return cache
+}
+
+// scan distributes images over the given number of workers and classifies
+// the failures. Images which were not scanned before the total timeout are
+// reported as timeouts.
+func scan(ctx context.Context, s Scanner, allImages []unversioned.Image, workers int) ([]unversioned.Image, []scanFailure, error) {
+	results, err := template.ScanImages(ctx, log, s.Timer(), allImages, workers, s.Scan)
+
+	vulnerableImages := make([]unversioned.Image, 0, len(results))
+	failures := make([]scanFailure, 0, len(results))
+	for _, r := range results {
+		switch {
+		case errors.Is(r.Err, template.ErrTotalTimeout):
+			failures = append(failures, scanFailure{img: r.Image, class: failureTimeout})
+		case r.Err != nil:
+			failures = append(failures, scanFailure{img: r.Image, class: failureClass(r.Err)})
+		case r.Status == StatusNonCompliant:
+			log.Info("vulnerable image found", "img", r.Image)
+			vulnerableImages = append(vulnerableImages, r.Image)
+		case r.Status == StatusFailed:
+			failures = append(failures, scanFailure{img: r.Image, class: failureUnknown})
 		}
 	}
 
-	return vulnerableImages, failedImages, nil
+	return vulnerableImages, failures, err
 }
@@ -1 +1,108 @@
 package main
+
+import (
+	"context"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+)
+
+type fakeScanner struct {
+	timer    *time.Timer
+	delay    time.Duration
+	statuses map[string]ScanStatus
+	active   int32
+	peak     int32
+}
+
+func (f *fakeScanner) Scan(ctx context.Context, img unversioned.Image) (ScanStatus, error) {
+	n := atomic.AddInt32(&f.active, 1)
+	defer atomic.AddInt32(&f.active, -1)
+
+	for {
+		peak := atomic.LoadInt32(&f.peak)
+		if n <= peak || atomic.CompareAndSwapInt32(&f.peak, peak, n) {
+			break
+		}
+	}
+
+	select {
+	case <-time.After(f.delay):
+	case <-ctx.Done():
+		return StatusFailed, &scanError{class: failureTimeout, err: ctx.Err()}
+	}
+
+	return f.statuses[img.ImageID], nil
+}
+
+func (f *fakeScanner) Timer() *time.Timer {
+	return f.timer
+}
+
+func ids(images []unversioned.Image) []string {
+	ret := make([]string, 0, len(images))
+	for _, img := range images {
+		ret = append(ret, img.ImageID)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func failedIDs(failures []scanFailure, class string) []string {
+	ret := make([]string, 0, len(failures))
+	for _, f := range failures {
+		if f.class == class {
+			ret = append(ret, f.img.ImageID)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func TestScanWorkers(t *testing.T) {
+	images := []unversioned.Image{{ImageID: "a"}, {ImageID: "b"}, {ImageID: "c"}, {ImageID: "d"}}
+	s := &fakeScanner{
+		timer:    time.NewTimer(time.Hour),
+		delay:    20 * time.Millisecond,
+		statuses: map[string]ScanStatus{"a": StatusNonCompliant, "b": StatusOK, "c": StatusFailed, "d": StatusNonCompliant},
+	}
+
+	vulnerable, failed, err := scan(context.Background(), s, images, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := ids(vulnerable); len(got) != 2 || got[0] != "a" || got[1] != "d" {
+		t.Errorf("unexpected vulnerable images: %v", got)
+	}
+	if got := failedIDs(failed, failureUnknown); len(got) != 1 || got[0] != "c" {
+		t.Errorf("unexpected failed images: %v", got)
+	}
+	if s.peak < 2 {
+		t.Errorf("expected images to be scanned concurrently, peak concurrency was %d", s.peak)
+	}
+}
+
+func TestScanTotalTimeout(t *testing.T) {
+	images := []unversioned.Image{{ImageID: "a"}, {ImageID: "b"}, {ImageID: "c"}, {ImageID: "d"}}
+	s := &fakeScanner{
+		timer:    time.NewTimer(10 * time.Millisecond),
+		delay:    time.Hour,
+		statuses: map[string]ScanStatus{},
+	}
+
+	vulnerable, failed, err := scan(context.Background(), s, images, 2)
+	if err == nil {
+		t.Error("expected a timeout error")
+	}
+
+	if len(vulnerable) != 0 {
+		t.Errorf("unexpected vulnerable images: %v", ids(vulnerable))
+	}
+	if got := failedIDs(failed, failureTimeout); len(got) != len(images) {
+		t.Errorf("expected every image to time out, got: %v", got)
+	}
+}
@@ -2,7 +2,9 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -11,16 +13,21 @@ import (
 
 	trivyTypes "github.com/aquasecurity/trivy/pkg/types"
 	"github.com/eraser-dev/eraser/api/unversioned"
+	"github.com/eraser-dev/eraser/pkg/policy"
+	"github.com/eraser-dev/eraser/pkg/scanners/template"
+	"github.com/eraser-dev/eraser/pkg/tracing"
 	"github.com/eraser-dev/eraser/pkg/utils"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
-	StatusFailed ScanStatus = iota
-	StatusNonCompliant
-	StatusOK
-	ImgSrcPodman     = "podman"
-	ImgSrcDocker     = "docker"
-	ImgSrcContainerd = "containerd"
+	StatusFailed       = template.StatusFailed
+	StatusNonCompliant = template.StatusNonCompliant
+	StatusOK           = template.StatusOK
+	ImgSrcPodman       = "podman"
+	ImgSrcDocker       = "docker"
+	ImgSrcContainerd   = "containerd"
 )
 
 const (
@@ -36,6 +43,9 @@ const (
 	trivySeveritiesFlag     = "--severity"
 	trivyRuntimeFlag        = "--image-src"
 	trivyIgnoreStatusFlag   = "--ignore-status"
+	trivyDownloadDBOnlyFlag = "--download-db-only"
+	trivySkipDBUpdateFlag   = "--skip-db-update"
+	trivyOfflineScanFlag    = "--offline-scan"
 )
 
 type (
@@ -43,29 +53,51 @@ type (
 		Runtime            unversioned.RuntimeSpec `json:"runtime,omitempty"`
 		CacheDir           string                  `json:"cacheDir,omitempty"`
 		DBRepo             string                  `json:"dbRepo,omitempty"`
+		DB                 DBConfig                `json:"db,omitempty"`
 		DeleteFailedImages bool                    `json:"deleteFailedImages,omitempty"`
 		DeleteEOLImages    bool                    `json:"deleteEOLImages,omitempty"`
 		Vulnerabilities    VulnConfig              `json:"vulnerabilities,omitempty"`
+		Secrets            FindingConfig           `json:"secrets,omitempty"`
+		Misconfigurations  FindingConfig           `json:"misconfigurations,omitempty"`
+		Licenses           FindingConfig           `json:"licenses,omitempty"`
 		Timeout            TimeoutConfig           `json:"timeout,omitempty"`
+		Cache              CacheConfig             `json:"cache,omitempty"`
+		Workers            int                     `json:"workers,omitempty"`
+		Failures           FailureConfig           `json:"failures,omitempty"`
+		Reports            ReportConfig            `json:"reports,omitempty"`
 	}
 
 	VulnConfig struct {
-		IgnoreUnfixed   bool     `json:"ignoreUnfixed,omitempty"`
-		Types           []string `json:"types,omitempty"`
-		SecurityChecks  []string `json:"securityChecks,omitempty"`
-		Severities      []string `json:"severities,omitempty"`
-		IgnoredStatuses []string `json:"ignoredStatuses,omitempty"`
+		IgnoreUnfixed   bool            `json:"ignoreUnfixed,omitempty"`
+		Types           []string        `json:"types,omitempty"`
+		SecurityChecks  []string        `json:"securityChecks,omitempty"`
+		Severities      []string        `json:"severities,omitempty"`
+		IgnoredStatuses []string        `json:"ignoredStatuses,omitempty"`
+		Ignore          []IgnoreRule    `json:"ignore,omitempty"`
+		Thresholds      ThresholdConfig `json:"thresholds,omitempty"`
 	}
 
-	TimeoutConfig struct {
-		Total    unversioned.Duration `json:"total,omitempty"`
-		PerImage unversioned.Duration `json:"perImage,omitempty"`
+	TimeoutConfig = template.TimeoutConfig
+
+	// DBConfig controls where the vulnerability DB comes from and how old it
+	// may be. DBRepo may also point at an in-cluster registry.
+	DBConfig struct {
+		BundleDir   string               `json:"bundleDir,omitempty"`
+		SkipUpdate  bool                 `json:"skipUpdate,omitempty"`
+		OfflineScan bool                 `json:"offlineScan,omitempty"`
+		MaxAge      unversioned.Duration `json:"maxAge,omitempty"`
+		RefuseStale bool                 `json:"refuseStale,omitempty"`
+	}
+
+	CacheConfig struct {
+		Dir    string               `json:"dir,omitempty"`
+		MaxAge unversioned.Duration `json:"maxAge,omitempty"`
 	}
 
-	ScanStatus int
+	ScanStatus = template.ScanStatus
 
 	Scanner interface {
-		Scan(unversioned.Image) (ScanStatus, error)
+		Scan(context.Context, unversioned.Image) (ScanStatus, error)
 		Timer() *time.Timer
 	}
 )
@@ -94,6 +126,17 @@ func DefaultConfig() *Config {
 			Total:    unversioned.Duration(time.Hour * 23),
 			PerImage: unversioned.Duration(time.Hour),
 		},
+		Cache: CacheConfig{
+			MaxAge: unversioned.Duration(time.Hour * 24 * 7),
+		},
+		Workers: 1,
+		Failures: FailureConfig{
+			Auth:       FailurePolicy{Retries: 1, Action: failureActionKeep},
+			Timeout:    FailurePolicy{Retries: 1},
+			DB:         FailurePolicy{Retries: 2, Action: failureActionKeep},
+			Crash:      FailurePolicy{Retries: 1},
+			RetryDelay: unversioned.Duration(time.Second * 10),
+		},
 	}
 }
 
@@ -116,10 +159,11 @@ func (c *Config) cliArgs(ref string) []string {
 		log.Error(err, "invalid runtime provided")
 	}
 
-	args = append(args, trivyImageArg, trivyRuntimeFlag, runtimeVar)
+	// the DB is prepared before the first scan
+	args = append(args, trivyImageArg, trivyRuntimeFlag, runtimeVar, trivySkipDBUpdateFlag)
 
-	if c.DBRepo != "" {
-		args = append(args, trivyDBRepoFlag, c.DBRepo)
+	if c.DB.OfflineScan {
+		args = append(args, trivyOfflineScanFlag)
 	}
 
 	if c.Vulnerabilities.IgnoreUnfixed {
@@ -146,11 +190,32 @@ func (c *Config) cliArgs(ref string) []string {
 		args = append(args, trivyIgnoreStatusFlag, allIgnoredStatuses)
 	}
 
+	// reports need every package to be converted to an SBOM
+	if c.Reports.Format == reportFormatCycloneDX {
+		args = append(args, trivyListAllPkgFlag)
+	}
+
 	args = append(args, ref)
 
 	return args
 }
 
+// downloadDBArgs returns the arguments to fetch the vulnerability DB without
+// scanning.
+func (c *Config) downloadDBArgs() []string {
+	args := []string{trivyImageArg, trivyDownloadDBOnlyFlag}
+
+	if c.CacheDir != "" {
+		args = append(args, trivyCacheDirFlag, c.CacheDir)
+	}
+
+	if c.DBRepo != "" {
+		args = append(args, trivyDBRepoFlag, c.DBRepo)
+	}
+
+	return args
+}
+
 func (c *Config) getRuntimeVar() (string, error) {
 	var imgsrc string
 	runtimeName := c.Runtime.Name
@@ -168,101 +233,225 @@ func (c *Config) getRuntimeVar() (string, error) {
 }
 
 type ImageScanner struct {
-	config Config
-	timer  *time.Timer
+	config  Config
+	timer   *time.Timer
+	cache   *resultCache
+	db      dbMetadata
+	reports *reporter
+	stats   *scanStats
+
+	policy     *policy.Engine
+	nodeName   string
+	nodeLabels map[string]string
 }
 
-func (s *ImageScanner) Scan(img unversioned.Image) (ScanStatus, error) {
+func (s *ImageScanner) Scan(ctx context.Context, img unversioned.Image) (status ScanStatus, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "scanner.scan", trace.WithAttributes(
+		attribute.String("image.id", img.ImageID),
+		attribute.StringSlice("image.names", img.Names),
+	))
+	defer func() {
+		span.SetAttributes(attribute.String("scan.verdict", verdictName(status)))
+		tracing.End(span, err)
+	}()
+
+	if s.cache != nil {
+		if res, ok := s.cache.get(img.ImageID); ok {
+			log.Info("using cached scan result", "imageID", img.ImageID)
+			span.SetAttributes(attribute.Bool("scan.cached", true))
+			status = s.verdict(img, res)
+
+			var raw []byte
+			if s.reports != nil {
+				raw = s.cache.report(img.ImageID)
+			}
+			s.record(img, raw, status, res, "", 0)
+			return status, nil
+		}
+	}
+
+	start := time.Now()
+	res, raw, err := s.scanRefs(ctx, img)
+	for attempt := 1; err != nil; attempt++ {
+		class := failureClass(err)
+		if attempt > s.config.Failures.policy(class).Retries || !s.config.Failures.sleep(ctx) {
+			span.SetAttributes(attribute.String("scan.failure", class), attribute.Int("scan.attempts", attempt))
+			s.record(img, nil, StatusFailed, nil, class, time.Since(start))
+			return StatusFailed, err
+		}
+
+		log.Info("retrying scan", "imageID", img.ImageID, "failure", class, "attempt", attempt)
+		res, raw, err = s.scanRefs(ctx, img)
+	}
+
+	if s.cache != nil {
+		// the raw report is only kept for persisting it again
+		var cached []byte
+		if s.reports != nil {
+			cached = raw
+		}
+		s.cache.put(img.ImageID, res, cached)
+	}
+
+	status = s.verdict(img, res)
+	s.record(img, raw, status, res, "", time.Since(start))
+	return status, nil
+}
+
+// record adds the image to the reports and stats. d is the time spent
+// scanning it, which is zero for cached results.
+func (s *ImageScanner) record(img unversioned.Image, raw []byte, status ScanStatus, res *scanResult, failure string, d time.Duration) {
+	if s.reports != nil {
+		s.reports.record(img, raw, status, res, failure, res != nil && d == 0)
+	}
+
+	if s.stats != nil {
+		s.stats.observe(res, d)
+	}
+}
+
+// scanRefs scans the image by each of its references in turn, and summarizes
+// the report of the first scan which succeeds. The raw report is returned
+// too. If every scan fails, the error of the last one is returned.
+func (s *ImageScanner) scanRefs(ctx context.Context, img unversioned.Image) (*scanResult, []byte, error) {
 	refs := make([]string, 0, len(img.Names)+len(img.Digests))
 	refs = append(refs, img.Digests...)
 	refs = append(refs, img.Names...)
-	scanSucceeded := false
+
+	var lastErr error = &scanError{class: failureUnknown, err: errors.New("image has no references to scan")}
 
 	log.Info("scanning image with id", "imageID", img.ImageID, "refs", refs)
-	for i := 0; i < len(refs) && !scanSucceeded; i++ {
+	for i := 0; i < len(refs) && ctx.Err() == nil; i++ {
 		log.Info("scanning image with ref", "ref", refs[i])
 
 		stdout := new(bytes.Buffer)
 		stderr := new(bytes.Buffer)
 
+		// trivy's own --timeout does not cover every stage of a scan, so
+		// the process is also killed once the per-image timeout elapses
+		refCtx, cancel := ctx, context.CancelFunc(func() {})
+		if s.config.Timeout.PerImage != 0 {
+			refCtx, cancel = context.WithTimeout(ctx, time.Duration(s.config.Timeout.PerImage))
+		}
+
 		cliArgs := s.config.cliArgs(refs[i])
-		cmd := exec.Command(trivyCommandName, cliArgs...)
+		cmd := exec.CommandContext(refCtx, trivyCommandName, cliArgs...)
 		cmd.Stdout = stdout
 		cmd.Stderr = stderr
 		cmd.Env = append(cmd.Env, os.Environ()...)
-		cmd.Env = setRuntimeSocketEnvVars(cmd, s.config.Runtime)
+		cmd.Env = append(cmd.Env, template.RuntimeSocketEnv(log, s.config.Runtime)...)
 
 		log.V(1).Info("scanning image ref", "ref", refs[i], "cli_invocation", fmt.Sprintf("%s %s", trivyCommandName, strings.Join(cliArgs, " ")), "env", cmd.Env)
-		if err := cmd.Run(); err != nil {
-			log.Error(err, "error scanning image", "imageID", img.ImageID, "reference", refs[i], "stderr", stderr.String())
+		err := cmd.Run()
+		if err != nil {
+			lastErr = &scanError{class: classify(refCtx, err, stderr.String()), err: err}
+			cancel()
+			log.Error(err, "error scanning image", "imageID", img.ImageID, "reference", refs[i], "failure", failureClass(lastErr), "stderr", stderr.String())
 			continue
 		}
+		cancel()
 
 		var report trivyTypes.Report
 		if err := json.Unmarshal(stdout.Bytes(), &report); err != nil {
+			// trivy exited cleanly without writing a report
+			lastErr = &scanError{class: failureCrash, err: err}
 			log.Error(err, "error unmarshaling report", "imageID", img.ImageID, "reference", refs[i], "report", stdout.String(), "stderr", stderr.String())
 			continue
 		}
 
-		if s.config.DeleteEOLImages {
-			if report.Metadata.OS != nil && report.Metadata.OS.Eosl {
-				log.Info("image is end of life", "imageID", img.ImageID, "reference", refs[i])
-				return StatusNonCompliant, nil
-			}
-		}
+		res := s.summarize(img, &report)
+		res.DB = s.db
+		return res, stdout.Bytes(), nil
+	}
+
+	if ctx.Err() != nil {
+		lastErr = &scanError{class: failureTimeout, err: ctx.Err()}
+	}
 
-		for j := range report.Results {
-			if len(report.Results[j].Vulnerabilities) > 0 {
-				return StatusNonCompliant, nil
+	return nil, nil, lastErr
+}
+
+func (s *ImageScanner) summarize(img unversioned.Image, report *trivyTypes.Report) *scanResult {
+	res := &scanResult{
+		Findings: []finding{},
+		Created:  report.Metadata.ImageConfig.Created.Time,
+	}
+
+	if report.Metadata.OS != nil {
+		res.EOL = report.Metadata.OS.Eosl
+	}
+
+	for i := range report.Results {
+		for j := range report.Results[i].Vulnerabilities {
+			vuln := &report.Results[i].Vulnerabilities[j]
+			if rule := ignored(s.config.Vulnerabilities.Ignore, img, vuln.VulnerabilityID, vuln.PkgName); rule != nil {
+				log.V(1).Info("ignoring vulnerability", "imageID", img.ImageID, "id", vuln.VulnerabilityID, "package", vuln.PkgName, "justification", rule.Justification)
+				continue
 			}
+			res.Findings = append(res.Findings, newVulnFinding(vuln))
 		}
 
-		// causes a break from the loop
-		scanSucceeded = true
+		res.Findings = append(res.Findings, otherFindings(&report.Results[i])...)
 	}
 
-	status := StatusOK
-	if !scanSucceeded {
-		status = StatusFailed
+	return res
+}
+
+func (s *ImageScanner) verdict(img unversioned.Image, res *scanResult) ScanStatus {
+	if s.policy != nil {
+		return s.evaluatePolicy(img, res)
 	}
 
-	return status, nil
-}
+	if s.config.DeleteEOLImages && res.EOL {
+		log.Info("image is end of life", "imageID", img.ImageID, "dbVersion", res.DB.Version, "dbUpdatedAt", res.DB.UpdatedAt)
+		return StatusNonCompliant
+	}
 
-func setRuntimeSocketEnvVars(cmd *exec.Cmd, runtime unversioned.RuntimeSpec) []string {
-	envKey := "CONTAINERD_ADDRESS"
-	envVal := utils.CRIPath
+	if f := s.config.otherFinding(res.Findings); f != nil {
+		log.Info("image has a non-compliant finding", "imageID", img.ImageID, "kind", f.Kind, "id", f.ID, "target", f.Package, "severity", f.Severity)
+		return StatusNonCompliant
+	}
 
-	switch runtime.Name {
-	case unversioned.RuntimeDockerShim:
-		envKey = "DOCKER_HOST"
-	case unversioned.RuntimeCrio:
-		infoParent, err := os.Stat("/run/cri")
-		if err != nil {
-			log.Error(err, "unable to get permissions for cri directory")
-		}
+	if reason, exceeded := s.config.Vulnerabilities.Thresholds.exceeded(vulnerabilities(res.Findings), time.Now()); exceeded {
+		log.Info("image exceeds vulnerability threshold", "imageID", img.ImageID, "threshold", reason, "dbVersion", res.DB.Version, "dbUpdatedAt", res.DB.UpdatedAt)
+		return StatusNonCompliant
+	}
 
-		infoSocket, err := os.Stat(utils.CRIPath)
-		if err != nil {
-			log.Error(err, "unable to get permissions for cri socket")
-		}
+	return StatusOK
+}
 
-		if err := os.Mkdir("/run/podman", infoParent.Mode().Perm()); err != nil {
-			log.Error(err, "unable to create /run/podman dir")
-		}
+// evaluatePolicy replaces the default verdict with the configured
+// ImagePolicy. Thresholds are not applied.
+func (s *ImageScanner) evaluatePolicy(img unversioned.Image, res *scanResult) ScanStatus {
+	vulns := map[string]int64{}
+	for _, f := range vulnerabilities(res.Findings) {
+		vulns[f.Severity]++
+	}
 
-		if err := os.Symlink(utils.CRIPath, "/run/podman/podman.sock"); err != nil {
-			log.Error(err, "unable to create symlink between CRI path and /run/podman/podman.sock")
-		}
+	facts := policy.Facts{
+		Image:      img,
+		Created:    res.Created,
+		Vulns:      vulns,
+		EOL:        s.config.DeleteEOLImages && res.EOL,
+		Scanned:    true,
+		NodeName:   s.nodeName,
+		NodeLabels: s.nodeLabels,
+	}
 
-		if err := os.Chmod("/run/podman/podman.sock", infoSocket.Mode().Perm()); err != nil {
-			log.Error(err, "unable to change /run/podman/podman.sock permissions")
-		}
-		envKey = "XDG_RUNTIME_DIR"
-		envVal = "/run"
+	remove, rule, err := s.policy.Evaluate(&facts)
+	if err != nil {
+		// the image is kept, as it is by the collector and remover, rather
+		// than counted as a failed scan, which may be deleted
+		log.Error(err, "error evaluating image policy, keeping image", "imageID", img.ImageID)
+		return StatusOK
+	}
+
+	if remove {
+		log.Info("image selected by policy", "imageID", img.ImageID, "rule", rule, "dbVersion", res.DB.Version, "dbUpdatedAt", res.DB.UpdatedAt)
+		return StatusNonCompliant
 	}
 
-	return append(cmd.Env, fmt.Sprintf("%s=%s", envKey, envVal))
+	return StatusOK
 }
 
 func (s *ImageScanner) Timer() *time.Timer {
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/eraser-dev/eraser/api/unversioned"
+	"github.com/eraser-dev/eraser/pkg/policy"
 )
 
 const ref = "image:tag"
@@ -26,78 +27,83 @@ func TestCLIArgs(t *testing.T) {
 			desc:   "empty config",
 			config: Config{},
 			// default container runtime is containerd
-			expected: []string{"--format=json", "image", "--image-src", ImgSrcContainerd, ref},
+			expected: []string{"--format=json", "image", "--image-src", ImgSrcContainerd, "--skip-db-update", ref},
 		},
 		{
 			desc:     "DeleteFailedImages has no effect",
 			config:   Config{DeleteFailedImages: true},
-			expected: []string{"--format=json", "image", "--image-src", ImgSrcContainerd, ref},
+			expected: []string{"--format=json", "image", "--image-src", ImgSrcContainerd, "--skip-db-update", ref},
 		},
 		{
 			desc:     "DeleteEOLImages has no effect",
 			config:   Config{DeleteEOLImages: true},
-			expected: []string{"--format=json", "image", "--image-src", ImgSrcContainerd, ref},
+			expected: []string{"--format=json", "image", "--image-src", ImgSrcContainerd, "--skip-db-update", ref},
 		},
 		{
 			desc:     "alternative runtime crio",
 			config:   Config{Runtime: unversioned.RuntimeSpec{Name: unversioned.RuntimeCrio, Address: unversioned.CrioPath}},
-			expected: []string{"--format=json", "image", "--image-src", ImgSrcPodman, ref},
+			expected: []string{"--format=json", "image", "--image-src", ImgSrcPodman, "--skip-db-update", ref},
 		},
 		{
 			desc:     "alternative runtime dockershim",
 			config:   Config{Runtime: unversioned.RuntimeSpec{Name: unversioned.RuntimeDockerShim, Address: unversioned.DockerPath}},
-			expected: []string{"--format=json", "image", "--image-src", ImgSrcDocker, ref},
+			expected: []string{"--format=json", "image", "--image-src", ImgSrcDocker, "--skip-db-update", ref},
 		},
 		{
 			desc:     "with cachedir",
 			config:   Config{CacheDir: "/var/lib/trivy"},
-			expected: []string{"--format=json", "--cache-dir", "/var/lib/trivy", "image", "--image-src", ImgSrcContainerd, ref},
+			expected: []string{"--format=json", "--cache-dir", "/var/lib/trivy", "image", "--image-src", ImgSrcContainerd, "--skip-db-update", ref},
 		},
 		{
-			desc:     "with custom db repo",
+			desc:     "db repo is only used to download the DB",
 			config:   Config{DBRepo: "example.test/db/repo"},
-			expected: []string{"--format=json", "image", "--image-src", ImgSrcContainerd, "--db-repository", "example.test/db/repo", ref},
+			expected: []string{"--format=json", "image", "--image-src", ImgSrcContainerd, "--skip-db-update", ref},
+		},
+		{
+			desc:     "offline scan",
+			config:   Config{DB: DBConfig{OfflineScan: true}},
+			expected: []string{"--format=json", "image", "--image-src", ImgSrcContainerd, "--skip-db-update", "--offline-scan", ref},
 		},
 		{
 			desc:     "ignore unfixed",
 			config:   Config{Vulnerabilities: VulnConfig{IgnoreUnfixed: true}},
-			expected: []string{"--format=json", "image", "--image-src", ImgSrcContainerd, "--ignore-unfixed", ref},
+			expected: []string{"--format=json", "image", "--image-src", ImgSrcContainerd, "--skip-db-update", "--ignore-unfixed", ref},
 		},
 		{
 			desc:     "specify vulnerability types",
 			config:   Config{Vulnerabilities: VulnConfig{Types: []string{"library", "os"}}},
-			expected: []string{"--format=json", "image", "--image-src", ImgSrcContainerd, "--vuln-type", "library,os", ref},
+			expected: []string{"--format=json", "image", "--image-src", ImgSrcContainerd, "--skip-db-update", "--vuln-type", "library,os", ref},
 		},
 		{
 			desc:     "specify security checks / scanners",
 			config:   Config{Vulnerabilities: VulnConfig{SecurityChecks: []string{"license", "vuln"}}},
-			expected: []string{"--format=json", "image", "--image-src", ImgSrcContainerd, "--scanners", "license,vuln", ref},
+			expected: []string{"--format=json", "image", "--image-src", ImgSrcContainerd, "--skip-db-update", "--scanners", "license,vuln", ref},
 		},
 		{
 			desc:     "specify severities",
 			config:   Config{Vulnerabilities: VulnConfig{Severities: []string{"LOW", "MEDIUM"}}},
-			expected: []string{"--format=json", "image", "--image-src", ImgSrcContainerd, "--severity", "LOW,MEDIUM", ref},
+			expected: []string{"--format=json", "image", "--image-src", ImgSrcContainerd, "--skip-db-update", "--severity", "LOW,MEDIUM", ref},
 		},
 		{
 			desc:     "specify statuses to ignore",
 			config:   Config{Vulnerabilities: VulnConfig{IgnoredStatuses: []string{statusUnknown, statusFixed, statusWillNotFix}}},
-			expected: []string{"--format=json", "image", "--image-src", ImgSrcContainerd, "--ignore-status", "unknown,fixed,will_not_fix", ref},
+			expected: []string{"--format=json", "image", "--image-src", ImgSrcContainerd, "--skip-db-update", "--ignore-status", "unknown,fixed,will_not_fix", ref},
 		},
 		{
 			desc:     "total timeout has no effect",
 			config:   Config{Timeout: TimeoutConfig{Total: testDuration}},
-			expected: []string{"--format=json", "image", "--image-src", ImgSrcContainerd, ref},
+			expected: []string{"--format=json", "image", "--image-src", ImgSrcContainerd, "--skip-db-update", ref},
 		},
 		{
 			desc:     "per-image timeout",
 			config:   Config{Timeout: TimeoutConfig{PerImage: testDuration}},
-			expected: []string{"--format=json", "--timeout", "1m40s", "image", "--image-src", ImgSrcContainerd, ref},
+			expected: []string{"--format=json", "--timeout", "1m40s", "image", "--image-src", ImgSrcContainerd, "--skip-db-update", ref},
 		},
 		{
 			desc:   "all global options",
 			config: Config{CacheDir: "/var/lib/trivy", Timeout: TimeoutConfig{PerImage: testDuration}},
 			// these are output in a consistent order
-			expected: []string{"--format=json", "--cache-dir", "/var/lib/trivy", "--timeout", "1m40s", "image", "--image-src", "containerd", ref},
+			expected: []string{"--format=json", "--cache-dir", "/var/lib/trivy", "--timeout", "1m40s", "image", "--image-src", "containerd", "--skip-db-update", ref},
 		},
 		{
 			desc: "all `image` options",
@@ -116,7 +122,7 @@ func TestCLIArgs(t *testing.T) {
 				},
 			},
 			expected: []string{
-				"--format=json", "image", "--image-src", ImgSrcPodman, "--db-repository", "example.test/db/repo", "--ignore-unfixed",
+				"--format=json", "image", "--image-src", ImgSrcPodman, "--skip-db-update", "--ignore-unfixed",
 				"--vuln-type", "library,os", "--scanners", "license,vuln", "--severity", "LOW,MEDIUM", "--ignore-status", "unknown,fixed", ref,
 			},
 		},
@@ -140,8 +146,7 @@ func TestCLIArgs(t *testing.T) {
 			},
 			expected: []string{
 				"--format=json", "--cache-dir", "/var/lib/trivy", "--timeout", "1m40s", "image", "--image-src", ImgSrcPodman,
-				"--db-repository", "example.test/db/repo", "--ignore-unfixed", "--vuln-type", "os", "--scanners",
-				"license,vuln", "--severity", "CRITICAL", "--ignore-status", "unknown,fixed", ref,
+				"--skip-db-update", "--ignore-unfixed", "--vuln-type", "os", "--scanners", "license,vuln", "--severity", "CRITICAL", "--ignore-status", "unknown,fixed", ref,
 			},
 		},
 	}
@@ -168,3 +173,31 @@ func TestCLIArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestEvaluatePolicy(t *testing.T) {
+	tests := []struct {
+		desc       string
+		expression string
+		expected   ScanStatus
+	}{
+		{desc: "selected", expression: "node.name == 'node-1'", expected: StatusNonCompliant},
+		{desc: "not selected", expression: "node.name == 'node-2'", expected: StatusOK},
+		// reading a label which the node does not have fails to evaluate
+		{desc: "unset node label", expression: "node.labels['team'] == 'a'", expected: StatusOK},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			engine, err := policy.New([]policy.Rule{{Name: "test", Expression: tt.expression}})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := &ImageScanner{policy: engine, nodeName: "node-1", nodeLabels: map[string]string{}}
+			if got := s.evaluatePolicy(unversioned.Image{ImageID: "sha256:abc"}, &scanResult{}); got != tt.expected {
+				t.Errorf("expected status %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+// Package schedule works out when the collector runs, from either a repeat
+// interval or a cron expression, and the maintenance windows which runs have
+// to respect.
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+)
+
+// searchLimit is how far ahead a run is looked for before the maintenance
+// windows are considered to never permit one.
+const searchLimit = 366 * 24 * time.Hour
+
+// Schedule is a parsed ScheduleConfig.
+type Schedule struct {
+	// cron is nil when runs repeat at interval instead.
+	cron     cron.Schedule
+	interval time.Duration
+	allowed  []window
+	blocked  []window
+}
+
+type window struct {
+	start    cron.Schedule
+	duration time.Duration
+}
+
+// New parses the cron expressions and time zone of cfg.
+func New(cfg *unversioned.ScheduleConfig) (*Schedule, error) {
+	loc, err := time.LoadLocation(cfg.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Schedule{interval: time.Duration(cfg.RepeatInterval)}
+	if cfg.Cron != "" {
+		if s.cron, err = ParseCron(cfg.Cron, loc); err != nil {
+			return nil, fmt.Errorf("cron: %w", err)
+		}
+	} else if s.interval <= 0 {
+		return nil, errors.New("repeatInterval must be positive")
+	}
+
+	if s.allowed, err = parseWindows(cfg.AllowedWindows, loc); err != nil {
+		return nil, fmt.Errorf("allowedWindows%w", err)
+	}
+	if s.blocked, err = parseWindows(cfg.BlockedWindows, loc); err != nil {
+		return nil, fmt.Errorf("blockedWindows%w", err)
+	}
+
+	return s, nil
+}
+
+// ParseCron parses a standard five-field cron expression, or a descriptor
+// such as @daily, in the time zone loc. The time zone can't be set in the
+// expression itself, and @every isn't supported, since RepeatInterval does
+// the same.
+func ParseCron(expr string, loc *time.Location) (cron.Schedule, error) {
+	if strings.HasPrefix(expr, "TZ=") || strings.HasPrefix(expr, "CRON_TZ=") {
+		return nil, errors.New("the time zone is set by timeZone")
+	}
+
+	sched, err := cron.ParseStandard(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, ok := sched.(*cron.SpecSchedule)
+	if !ok {
+		return nil, errors.New("@every is not supported, use repeatInterval instead")
+	}
+	spec.Location = loc
+
+	if spec.Next(time.Now()).IsZero() {
+		return nil, errors.New("matches no time")
+	}
+
+	return spec, nil
+}
+
+func parseWindows(windows []unversioned.MaintenanceWindow, loc *time.Location) ([]window, error) {
+	parsed := make([]window, 0, len(windows))
+	for i, w := range windows {
+		start, err := ParseCron(w.Cron, loc)
+		if err != nil {
+			return nil, fmt.Errorf("[%d].cron: %w", i, err)
+		}
+
+		if w.Duration <= 0 {
+			return nil, fmt.Errorf("[%d].duration must be positive", i)
+		}
+
+		parsed = append(parsed, window{start: start, duration: time.Duration(w.Duration)})
+	}
+
+	return parsed, nil
+}
+
+// First returns when the first run after the manager starts at now should
+// start. If immediately is true that is now, unless the maintenance windows
+// don't permit it.
+func (s *Schedule) First(now time.Time, immediately bool) (time.Time, error) {
+	if !immediately || (s.cron != nil && !s.Permitted(now)) {
+		return s.Next(now, now)
+	}
+
+	return s.permit(now)
+}
+
+// Next returns when the run after one which started at last should start.
+// A cron schedule skips runs which were due before now, and an interval
+// schedule starts an overdue run at now. Either way, the run is then moved
+// to the first time the maintenance windows permit.
+func (s *Schedule) Next(last, now time.Time) (time.Time, error) {
+	if s.cron == nil {
+		next := last.Add(s.interval)
+		if next.Before(now) {
+			next = now
+		}
+
+		return s.permit(next)
+	}
+
+	if last.Before(now) {
+		last = now
+	}
+
+	return s.permit(s.cron.Next(last))
+}
+
+// Permitted returns whether the maintenance windows permit a run to start
+// at t.
+func (s *Schedule) Permitted(t time.Time) bool {
+	return s.postpone(t).Equal(t)
+}
+
+// permit returns the first time the schedule runs, at or after t, which the
+// maintenance windows permit.
+func (s *Schedule) permit(t time.Time) (time.Time, error) {
+	limit := t.Add(searchLimit)
+	for !t.IsZero() && t.Before(limit) {
+		next := s.postpone(t)
+		if next.Equal(t) {
+			return t, nil
+		}
+
+		t = s.align(next)
+	}
+
+	return time.Time{}, errors.New("the maintenance windows do not permit any run within a year")
+}
+
+// postpone returns t if the maintenance windows permit a run to start at t,
+// and otherwise the earliest time after t at which they might.
+func (s *Schedule) postpone(t time.Time) time.Time {
+	for _, w := range s.blocked {
+		if end, ok := w.end(t); ok {
+			return end
+		}
+	}
+
+	if len(s.allowed) == 0 {
+		return t
+	}
+
+	var next time.Time
+	for _, w := range s.allowed {
+		if _, ok := w.end(t); ok {
+			return t
+		}
+
+		if start := w.start.Next(t); !start.IsZero() && (next.IsZero() || start.Before(next)) {
+			next = start
+		}
+	}
+
+	return next
+}
+
+// align returns the first time the schedule runs at or after t.
+func (s *Schedule) align(t time.Time) time.Time {
+	if s.cron == nil || t.IsZero() {
+		return t
+	}
+
+	// Next returns times strictly after the one it is given
+	return s.cron.Next(t.Add(-time.Nanosecond))
+}
+
+// end returns the end of the occurrence of the window which t falls in, if
+// there is one.
+func (w window) end(t time.Time) (time.Time, bool) {
+	start := w.start.Next(t.Add(-w.duration))
+	if start.IsZero() || start.After(t) {
+		return time.Time{}, false
+	}
+
+	return start.Add(w.duration), true
+}
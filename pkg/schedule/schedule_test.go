@@ -0,0 +1,218 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eraser-dev/eraser/api/unversioned"
+)
+
+// 2023-06-01 is a Thursday.
+var now = time.Date(2023, time.June, 1, 10, 30, 0, 0, time.UTC)
+
+func duration(d time.Duration) unversioned.Duration {
+	return unversioned.Duration(d)
+}
+
+// businessHours blocks 9am to 5pm on weekdays.
+var businessHours = unversioned.MaintenanceWindow{Cron: "0 9 * * 1-5", Duration: duration(8 * time.Hour)}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		desc string
+		cfg  unversioned.ScheduleConfig
+		last time.Time
+		want time.Time
+	}{
+		{
+			desc: "interval from the start of the last run",
+			cfg:  unversioned.ScheduleConfig{RepeatInterval: duration(time.Hour)},
+			last: now.Add(-10 * time.Minute),
+			want: now.Add(50 * time.Minute),
+		},
+		{
+			desc: "overdue interval runs now",
+			cfg:  unversioned.ScheduleConfig{RepeatInterval: duration(time.Hour)},
+			last: now.Add(-2 * time.Hour),
+			want: now,
+		},
+		{
+			desc: "interval postponed to the end of a blocked window",
+			cfg: unversioned.ScheduleConfig{
+				RepeatInterval: duration(time.Hour),
+				BlockedWindows: []unversioned.MaintenanceWindow{businessHours},
+			},
+			last: now,
+			want: time.Date(2023, time.June, 1, 17, 0, 0, 0, time.UTC),
+		},
+		{
+			desc: "cron",
+			cfg:  unversioned.ScheduleConfig{Cron: "0 2 * * *"},
+			last: now.Add(-8 * time.Hour),
+			want: time.Date(2023, time.June, 2, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			desc: "cron in a time zone",
+			cfg:  unversioned.ScheduleConfig{Cron: "0 2 * * *", TimeZone: "America/New_York"},
+			last: now,
+			want: time.Date(2023, time.June, 2, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			desc: "cron skips runs in a blocked window",
+			cfg: unversioned.ScheduleConfig{
+				Cron:           "0 * * * *",
+				BlockedWindows: []unversioned.MaintenanceWindow{businessHours},
+			},
+			last: now,
+			want: time.Date(2023, time.June, 1, 17, 0, 0, 0, time.UTC),
+		},
+		{
+			desc: "allowed window on the weekend",
+			cfg: unversioned.ScheduleConfig{
+				RepeatInterval: duration(time.Hour),
+				AllowedWindows: []unversioned.MaintenanceWindow{{Cron: "0 0 * * 6", Duration: duration(48 * time.Hour)}},
+			},
+			last: now,
+			want: time.Date(2023, time.June, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			desc: "inside an allowed window",
+			cfg: unversioned.ScheduleConfig{
+				RepeatInterval: duration(time.Hour),
+				AllowedWindows: []unversioned.MaintenanceWindow{{Cron: "0 10 * * *", Duration: duration(4 * time.Hour)}},
+			},
+			last: now,
+			want: now.Add(time.Hour),
+		},
+		{
+			desc: "blocked freeze days inside an allowed window",
+			cfg: unversioned.ScheduleConfig{
+				Cron:           "0 3 * * *",
+				AllowedWindows: []unversioned.MaintenanceWindow{{Cron: "0 0 * * *", Duration: duration(6 * time.Hour)}},
+				BlockedWindows: []unversioned.MaintenanceWindow{{Cron: "0 0 1-3 * *", Duration: duration(24 * time.Hour)}},
+			},
+			last: now,
+			want: time.Date(2023, time.June, 4, 3, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			s, err := New(&tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := s.Next(tt.last, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("expected %s, got %s", tt.want, got.UTC())
+			}
+		})
+	}
+}
+
+func TestFirst(t *testing.T) {
+	tests := []struct {
+		desc        string
+		cfg         unversioned.ScheduleConfig
+		immediately bool
+		want        time.Time
+	}{
+		{
+			desc:        "immediately",
+			cfg:         unversioned.ScheduleConfig{RepeatInterval: duration(time.Hour)},
+			immediately: true,
+			want:        now,
+		},
+		{
+			desc: "after the interval",
+			cfg:  unversioned.ScheduleConfig{RepeatInterval: duration(time.Hour)},
+			want: now.Add(time.Hour),
+		},
+		{
+			desc: "interval immediately after a blocked window",
+			cfg: unversioned.ScheduleConfig{
+				RepeatInterval: duration(time.Hour),
+				BlockedWindows: []unversioned.MaintenanceWindow{businessHours},
+			},
+			immediately: true,
+			want:        time.Date(2023, time.June, 1, 17, 0, 0, 0, time.UTC),
+		},
+		{
+			desc: "cron immediately in a blocked window",
+			cfg: unversioned.ScheduleConfig{
+				Cron:           "0 2 * * *",
+				BlockedWindows: []unversioned.MaintenanceWindow{businessHours},
+			},
+			immediately: true,
+			want:        time.Date(2023, time.June, 2, 2, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			s, err := New(&tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := s.First(now, tt.immediately)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("expected %s, got %s", tt.want, got.UTC())
+			}
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		desc string
+		cfg  unversioned.ScheduleConfig
+	}{
+		{desc: "no interval", cfg: unversioned.ScheduleConfig{}},
+		{desc: "invalid cron", cfg: unversioned.ScheduleConfig{Cron: "0 2 * *"}},
+		{desc: "every", cfg: unversioned.ScheduleConfig{Cron: "@every 1h"}},
+		{desc: "time zone in cron", cfg: unversioned.ScheduleConfig{Cron: "CRON_TZ=UTC 0 2 * * *"}},
+		{desc: "never matches", cfg: unversioned.ScheduleConfig{Cron: "0 0 30 2 *"}},
+		{desc: "unknown time zone", cfg: unversioned.ScheduleConfig{Cron: "0 2 * * *", TimeZone: "Mars/Olympus_Mons"}},
+		{
+			desc: "window without duration",
+			cfg: unversioned.ScheduleConfig{
+				RepeatInterval: duration(time.Hour),
+				BlockedWindows: []unversioned.MaintenanceWindow{{Cron: "0 9 * * *"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.desc, func(t *testing.T) {
+			if _, err := New(&tt.cfg); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestNeverPermitted(t *testing.T) {
+	s, err := New(&unversioned.ScheduleConfig{
+		RepeatInterval: duration(time.Hour),
+		AllowedWindows: []unversioned.MaintenanceWindow{businessHours},
+		BlockedWindows: []unversioned.MaintenanceWindow{{Cron: "0 0 * * *", Duration: duration(24 * time.Hour)}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Next(now, now); err == nil {
+		t.Error("expected an error when every allowed window is blocked")
+	}
+}
@@ -0,0 +1,167 @@
+// Package tracing follows a run of Eraser across the manager and job pods.
+// The manager starts the trace when it creates an ImageJob, and passes its
+// context to the ImageJob in an annotation and to each job pod in an
+// environment variable, so that the collector, scanner and remover spans of
+// every node are part of the same trace.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/eraser-dev/eraser/pkg/metrics"
+)
+
+const (
+	// EnvTraceparent holds the context of a job pod's span, in the format of
+	// the W3C traceparent header. Job pods only trace if it is set.
+	EnvTraceparent = "TRACEPARENT"
+	// TraceparentAnnotation holds the context of the span which created an
+	// ImageJob.
+	TraceparentAnnotation = "eraser.sh/traceparent"
+
+	traceparentHeader = "traceparent"
+	tracerName        = "eraser"
+)
+
+var propagator = propagation.TraceContext{}
+
+// Tracer returns Eraser's tracer from the global TracerProvider. Its spans
+// are dropped until Configure is called.
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// Configure sets the global TracerProvider to one which exports spans with
+// the OTLP exporter configured by cfg. Root spans are sampled at
+// sampleRatio, and other spans follow their parent. The returned function
+// flushes the remaining spans.
+func Configure(ctx context.Context, cfg metrics.ExporterConfig, sampleRatio float64) (func(context.Context) error, error) {
+	exporter, err := newExporter(ctx, &cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		// the default resource names the service after OTEL_SERVICE_NAME
+		sdktrace.WithResource(resource.Default()),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// ConfigureFromEnv configures tracing in a job pod, if the manager passed it
+// a trace context, and returns the context of the pod's span. The returned
+// function flushes the remaining spans, and does nothing if tracing is off.
+func ConfigureFromEnv(ctx context.Context) (context.Context, func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+
+	traceparent := os.Getenv(EnvTraceparent)
+	if traceparent == "" || os.Getenv(metrics.EnvOTLPEndpoint) == "" {
+		return ctx, noop, nil
+	}
+
+	cfg, err := metrics.ExporterConfigFromEnv()
+	if err != nil {
+		return ctx, noop, err
+	}
+
+	// the manager has already decided whether to sample the trace
+	shutdown, err := Configure(ctx, cfg, 0)
+	if err != nil {
+		return ctx, noop, err
+	}
+
+	return WithTraceparent(ctx, traceparent), shutdown, nil
+}
+
+// End ends span, and marks it as failed if err is not nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Traceparent returns the context of the span in ctx in the format of the
+// W3C traceparent header, or an empty string if there is no sampled span.
+func Traceparent(ctx context.Context) string {
+	if !trace.SpanContextFromContext(ctx).IsSampled() {
+		return ""
+	}
+
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+
+	return carrier.Get(traceparentHeader)
+}
+
+// WithTraceparent returns a copy of ctx whose remote span is traceparent.
+func WithTraceparent(ctx context.Context, traceparent string) context.Context {
+	if traceparent == "" {
+		return ctx
+	}
+
+	return propagator.Extract(ctx, propagation.MapCarrier{traceparentHeader: traceparent})
+}
+
+func newExporter(ctx context.Context, cfg *metrics.ExporterConfig) (*otlptrace.Exporter, error) {
+	switch cfg.Protocol {
+	case metrics.ProtocolGRPC:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		} else {
+			tlsCfg, err := cfg.TLSConfig()
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(tlsCfg)))
+		}
+		if len(cfg.Headers) > 0 {
+			opts = append(opts, otlptracegrpc.WithHeaders(cfg.Headers))
+		}
+		if cfg.Compression == metrics.CompressionGzip {
+			opts = append(opts, otlptracegrpc.WithCompressor(metrics.CompressionGzip))
+		}
+
+		return otlptracegrpc.New(ctx, opts...)
+	case metrics.ProtocolHTTP, "":
+		opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		} else {
+			tlsCfg, err := cfg.TLSConfig()
+			if err != nil {
+				return nil, err
+			}
+			opts = append(opts, otlptracehttp.WithTLSClientConfig(tlsCfg))
+		}
+		if len(cfg.Headers) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(cfg.Headers))
+		}
+		if cfg.Compression == metrics.CompressionGzip {
+			opts = append(opts, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
+		}
+
+		return otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unsupported OTLP protocol %q", cfg.Protocol)
+	}
+}
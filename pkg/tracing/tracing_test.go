@@ -0,0 +1,67 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/eraser-dev/eraser/pkg/metrics"
+)
+
+func TestTraceparent(t *testing.T) {
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.AlwaysSample()))
+	ctx, span := provider.Tracer("test").Start(context.Background(), "parent")
+	defer span.End()
+
+	traceparent := Traceparent(ctx)
+	require.NotEmpty(t, traceparent)
+
+	// a job pod continues the trace of the span which created it
+	podCtx := WithTraceparent(context.Background(), traceparent)
+	_, child := provider.Tracer("test").Start(podCtx, "child")
+	defer child.End()
+
+	assert.Equal(t, span.SpanContext().TraceID(), child.SpanContext().TraceID())
+	assert.True(t, child.SpanContext().IsSampled())
+}
+
+func TestTraceparentNotSampled(t *testing.T) {
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.NeverSample()))
+	ctx, span := provider.Tracer("test").Start(context.Background(), "parent")
+	defer span.End()
+
+	assert.Empty(t, Traceparent(ctx))
+	assert.Empty(t, Traceparent(context.Background()))
+}
+
+func TestWithTraceparentEmpty(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, ctx, WithTraceparent(ctx, ""))
+}
+
+func TestConfigureFromEnvDisabled(t *testing.T) {
+	tests := []struct {
+		name        string
+		traceparent string
+		endpoint    string
+	}{
+		{name: "no traceparent", endpoint: "otel-collector:4318"},
+		{name: "no endpoint", traceparent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvTraceparent, tt.traceparent)
+			t.Setenv(metrics.EnvOTLPEndpoint, tt.endpoint)
+
+			ctx, shutdown, err := ConfigureFromEnv(context.Background())
+			require.NoError(t, err)
+			assert.False(t, trace.SpanContextFromContext(ctx).IsValid())
+			assert.NoError(t, shutdown(context.Background()))
+		})
+	}
+}
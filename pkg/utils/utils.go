@@ -33,6 +33,11 @@ const (
 	CRIPath = "/run/cri/cri.sock"
 
 	EnvEraserRuntimeName = "ERASER_RUNTIME_NAME"
+	EnvEraserImagePolicy = "ERASER_IMAGE_POLICY"
+	EnvEraserNodeLabels  = "ERASER_NODE_LABELS"
+	EnvEraserJobName     = "ERASER_JOB_NAME"
+
+	EnvEraserMetricsMaxRepositories = "ERASER_METRICS_MAX_REPOSITORIES"
 )
 
 type ExclusionList struct {
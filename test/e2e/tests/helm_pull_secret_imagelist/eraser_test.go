@@ -32,9 +32,12 @@ func TestHelmPullSecretImagelist(t *testing.T) {
 			}
 
 			imgList := &eraserv1.ImageList{
-				ObjectMeta: metav1.ObjectMeta{Name: util.Prune},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        util.Prune,
+					Annotations: map[string]string{eraserv1.ConfirmPruneAnnotation: "true"},
+				},
 				Spec: eraserv1.ImageListSpec{
-					Images: []string{"*"},
+					Images: []string{eraserv1.PruneAll},
 				},
 			}
 			if err := cfg.Client().Resources().Create(ctx, imgList); err != nil {
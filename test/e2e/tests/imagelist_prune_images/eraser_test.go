@@ -131,9 +131,12 @@ func TestPrune(t *testing.T) {
 		}).
 		Assess("All non-running images are removed from the cluster", func(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
 			imgList := &eraserv1.ImageList{
-				ObjectMeta: metav1.ObjectMeta{Name: util.Prune},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        util.Prune,
+					Annotations: map[string]string{eraserv1.ConfirmPruneAnnotation: "true"},
+				},
 				Spec: eraserv1.ImageListSpec{
-					Images: []string{"*"},
+					Images: []string{eraserv1.PruneAll},
 				},
 			}
 